@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,13 +10,17 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"unicode"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/askpass"
+	"github.com/idursun/jjui/internal/mergetool"
+	"github.com/idursun/jjui/internal/scripting"
 	"github.com/idursun/jjui/internal/ui/common"
+	dashboardui "github.com/idursun/jjui/internal/ui/dashboard"
 
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/context"
@@ -45,6 +50,10 @@ var (
 	version    bool
 	editConfig bool
 	help       bool
+	script     string
+	validate   bool
+	repos      string
+	dashboard  bool
 )
 
 func init() {
@@ -57,6 +66,10 @@ func init() {
 	flag.BoolVar(&version, "version", false, "Show version information")
 	flag.BoolVar(&editConfig, "config", false, "Open configuration file in $EDITOR")
 	flag.BoolVar(&help, "help", false, "Show help information")
+	flag.BoolVar(&validate, "validate-config", false, "Check the configuration files for problems and exit")
+	flag.StringVar(&repos, "repos", "", "Show a dashboard of the comma separated jj repos, e.g. jjui --repos ~/src/a,~/src/b")
+	flag.BoolVar(&dashboard, "dashboard", false, "Show a dashboard of the repos listed in dashboard.repos of the config")
+	flag.StringVar(&script, "script", "", "Run a Lua script without the UI, e.g. jjui --script file.lua [args]")
 
 	flag.Usage = func() {
 		fmt.Printf("Usage: jjui [flags] [location]\n")
@@ -135,6 +148,11 @@ func main() {
 }
 
 func run() int {
+	// checked first since jj passes on the environment of the askpass
+	// subprocesses to the merge tools it runs
+	if code, ok := mergetool.Run(os.Args[1:]); ok {
+		return code
+	}
 	askpassServer := askpass.NewUnstartedServer("JJUI")
 	if askpassServer.IsSubprocess() {
 		return 0
@@ -152,8 +170,16 @@ func run() int {
 		return config.Edit()
 	}
 
+	if repos != "" || dashboard {
+		return runDashboard(askpassServer)
+	}
+
+	// the arguments after the script are passed to the script
 	var location string
-	if args := flag.Args(); len(args) > 0 {
+	var scriptArgs []string
+	if args := flag.Args(); script != "" {
+		scriptArgs = args
+	} else if len(args) > 0 {
 		location = args[0]
 	}
 
@@ -167,6 +193,10 @@ func run() int {
 	}
 
 	rootLocation, err := getJJRootDir(location)
+	if validate {
+		// the global config can still be checked outside a repository
+		return validateConfig(rootLocation)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
 		return 1
@@ -183,92 +213,143 @@ func run() int {
 		log.SetOutput(io.Discard)
 	}
 
-	if limit > 0 {
-		config.Current.Limit = limit
-	}
-
 	appContext := context.NewAppContext(rootLocation, askpassServer)
 	defer appContext.Histories.Flush()
-	if output, err := config.LoadConfigFile(); err == nil {
-		if err := config.Current.Load(string(output)); err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
-			return 1
-		}
-		if registry, err := context.LoadCustomCommands(string(output)); err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading custom commands: %v\n", err)
-			return 1
-		} else {
-			appContext.CustomCommands = registry
-		}
-		if registry, err := context.LoadLeader(string(output)); err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading leader keys: %v\n", err)
+	// the terminal cannot be queried while the UI is running, or at all
+	// when running a script
+	loader := newConfigLoader(script == "" && lipgloss.HasDarkBackground())
+	if repoConfig, err := config.LoadRepoConfig(rootLocation); err == nil {
+		loader.RepoConfig = repoConfig
+		loader.RepoConfigTrusted = trustRepoConfig(repoConfig)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := loader.Load(appContext); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if src, err := config.LoadInitLua(); err == nil {
+		hooks, err := scripting.LoadHooks(appContext, string(src))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading init.lua: %v\n", err)
 			return 1
-		} else {
-			appContext.Leader = registry
 		}
+		defer hooks.Close()
+		appContext.SetCommandHooks(hooks)
+		appContext.StatusSegments = hooks
 	} else if !errors.Is(err, fs.ErrNotExist) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
 
-	var theme map[string]config.Color
+	if script != "" {
+		for _, warning := range loader.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		return runScript(appContext, script, scriptArgs)
+	}
 
-	var defaultThemeName string
-	if lipgloss.HasDarkBackground() {
-		defaultThemeName = "default_dark"
-	} else {
-		defaultThemeName = "default_light"
+	loader.Watch(rootLocation)
+	appContext.ConfigLoader = loader
+
+	p := tea.NewProgram(ui.New(appContext), tea.WithAltScreen(), tea.WithReportFocus(), tea.WithMouseCellMotion())
+	if config.Current.Ssh.HijackAskpass {
+		if err := askpassServer.StartListening(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: ssh.hijack_askpass: %v\n", err)
+			return 1
+		}
+		defer askpassServer.Close()
+
+		go askpassServer.Serve(showPassword(p.Send))
+
+		// uncomment the line below to show a fake prompt upon startup
+		// go showPassword(p.Send)("test", "Enter PIN for 'ssh': ", make(<-chan struct{}))
+	}
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Error running program: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newConfigLoader returns a loader applying the flags over the configuration
+func newConfigLoader(darkBackground bool) *context.ConfigLoader {
+	return &context.ConfigLoader{
+		Revset:         revset,
+		DarkBackground: darkBackground,
+		Overrides: func(c *config.Config) {
+			if limit > 0 {
+				c.Limit = limit
+			}
+			if period >= 0 {
+				c.UI.AutoRefreshInterval = period
+			}
+		},
+		Plugins: scripting.LoadPlugins,
 	}
+}
 
-	theme, err = config.LoadEmbeddedTheme(defaultThemeName)
+// runDashboard shows the repositories given with --repos, or listed in the
+// config, in a dashboard. Every repository gets its own context and config
+// loader, the dashboard itself uses the config outside of them.
+func runDashboard(askpassServer *askpass.Server) int {
+	log.SetOutput(io.Discard)
+	location, err := os.Getwd()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading default theme '%s': %v\n", defaultThemeName, err)
+		fmt.Fprintf(os.Stderr, "Error: couldn't determine the current directory: %v.\n", err)
 		return 1
 	}
-
-	var userThemeName string
-	if lipgloss.HasDarkBackground() {
-		userThemeName = config.Current.UI.Theme.Dark
-	} else {
-		userThemeName = config.Current.UI.Theme.Light
+	darkBackground := lipgloss.HasDarkBackground()
+	appContext := context.NewAppContext(location, askpassServer)
+	defer appContext.Histories.Flush()
+	loader := newConfigLoader(darkBackground)
+	if err := loader.Load(appContext); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
 	}
 
-	if userThemeName != "" {
-		theme, err = config.LoadTheme(userThemeName, theme)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading user theme '%s': %v\n", userThemeName, err)
-			return 1
+	names := config.Current.Dashboard.Repos
+	if repos != "" {
+		names = strings.Split(repos, ",")
+	}
+	var initLua string
+	if src, err := config.LoadInitLua(); err == nil {
+		initLua = string(src)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	var repositories []dashboardui.Repository
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		repository, hooks := openRepository(name, appContext, darkBackground, askpassServer, initLua)
+		if hooks != nil {
+			defer hooks.Close()
 		}
+		repositories = append(repositories, repository)
 	}
-
-	common.DefaultPalette.Update(theme)
-	common.DefaultPalette.Update(appContext.JJConfig.GetApplicableColors())
-	common.DefaultPalette.Update(config.Current.UI.Colors)
-
-	if period >= 0 {
-		config.Current.UI.AutoRefreshInterval = period
+	if len(repositories) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no repositories to show, pass them with --repos or list them in dashboard.repos of the config\n")
+		return 1
 	}
-	if revset != "" {
-		appContext.DefaultRevset = revset
-	} else if config.Current.Revisions.Revset != "" {
-		appContext.DefaultRevset = config.Current.Revisions.Revset
-	} else {
-		appContext.DefaultRevset = appContext.JJConfig.Revsets.Log
+	// the repositories loaded their own configuration
+	if err := loader.Load(appContext); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
 	}
-	appContext.CurrentRevset = appContext.DefaultRevset
 
-	p := tea.NewProgram(ui.New(appContext), tea.WithAltScreen(), tea.WithReportFocus(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(ui.NewDashboard(appContext, loader, repositories), tea.WithAltScreen(), tea.WithReportFocus(), tea.WithMouseCellMotion())
 	if config.Current.Ssh.HijackAskpass {
 		if err := askpassServer.StartListening(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: ssh.hijack_askpass: %v\n", err)
 			return 1
 		}
 		defer askpassServer.Close()
-
 		go askpassServer.Serve(showPassword(p.Send))
-
-		// uncomment the line below to show a fake prompt upon startup
-		// go showPassword(p.Send)("test", "Enter PIN for 'ssh': ", make(<-chan struct{}))
 	}
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
@@ -277,6 +358,142 @@ func run() int {
 	return 0
 }
 
+// openRepository prepares the context of a repository of the dashboard. There
+// is no terminal to ask on while the dashboard runs, so untrusted repository
+// configs are skipped.
+func openRepository(name string, appContext *context.MainContext, darkBackground bool, askpassServer *askpass.Server, initLua string) (dashboardui.Repository, *scripting.Hooks) {
+	repository := dashboardui.Repository{Name: name}
+	root, err := getJJRootDir(expandHome(name))
+	if err != nil {
+		repository.Err = err
+		return repository, nil
+	}
+	ctx := context.NewAppContext(root, askpassServer)
+	ctx.Histories = appContext.Histories
+	loader := newConfigLoader(darkBackground)
+	if repoConfig, err := config.LoadRepoConfig(root); err == nil {
+		loader.RepoConfig = repoConfig
+		loader.RepoConfigTrusted = repoConfig.IsTrusted()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		repository.Err = err
+		return repository, nil
+	}
+	if err := loader.Load(ctx); err != nil {
+		repository.Err = err
+		return repository, nil
+	}
+	var hooks *scripting.Hooks
+	if initLua != "" {
+		if hooks, err = scripting.LoadHooks(ctx, initLua); err != nil {
+			repository.Err = fmt.Errorf("loading init.lua: %w", err)
+			return repository, nil
+		}
+		ctx.SetCommandHooks(hooks)
+		ctx.StatusSegments = hooks
+	}
+	loader.Watch(root)
+	ctx.ConfigLoader = loader
+	repository.Context = ctx
+	repository.Loader = loader
+	return repository, hooks
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// trustRepoConfig asks before loading a repository config that has not been
+// trusted yet, since its custom commands can run arbitrary programs. The
+// config is skipped when there is no terminal to ask on.
+func trustRepoConfig(repoConfig *config.RepoConfig) bool {
+	if repoConfig.IsTrusted() {
+		return true
+	}
+	if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: skipping untrusted repository config %s\n", repoConfig.File)
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "This repository has a jjui config at %s that has not been trusted yet.\n", repoConfig.File)
+	fmt.Fprint(os.Stderr, "It can define commands that jjui runs. Trust it? [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		if err := repoConfig.Trust(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: couldn't remember the repository config as trusted: %v\n", err)
+		}
+		return true
+	}
+	return false
+}
+
+// validateConfig prints the problems in config.toml and the repository config
+// at root, one per line, and fails when there are any
+func validateConfig(root string) int {
+	type configFile struct {
+		file string
+		data []byte
+	}
+	var files []configFile
+	if data, err := config.LoadConfigFile(); err == nil {
+		files = append(files, configFile{file: config.ConfigFile(), data: data})
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if root != "" {
+		if repoConfig, err := config.LoadRepoConfig(root); err == nil {
+			files = append(files, configFile{file: repoConfig.File, data: repoConfig.Data})
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	failed := false
+	for _, f := range files {
+		problems, err := context.ValidateConfig(string(f.data))
+		if err != nil {
+			fmt.Printf("%s: %v\n", f.file, err)
+			failed = true
+			continue
+		}
+		for _, problem := range problems {
+			fmt.Println(problem.In(f.file))
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	fmt.Println("No problems found")
+	return 0
+}
+
+func runScript(appContext *context.MainContext, file string, args []string) int {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	code, err := scripting.RunHeadless(appContext, string(src), args, scripting.Headless{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	return code
+}
+
 func showPassword(send func(tea.Msg)) func(name, prompt string, done <-chan struct{}) []byte {
 	adjustPrompt := func(s string) string {
 		// ensure that the prompt is not only made of spaces
@@ -17,15 +17,19 @@ var configFS embed.FS
 var Current = loadDefaultConfig()
 
 type Config struct {
-	Keys      KeyMappings[keys] `toml:"keys"`
-	UI        UIConfig          `toml:"ui"`
-	Suggest   SuggestConfig     `toml:"suggest"`
-	Revisions RevisionsConfig   `toml:"revisions"`
-	Preview   PreviewConfig     `toml:"preview"`
-	OpLog     OpLogConfig       `toml:"oplog"`
-	Limit     int               `toml:"limit"`
-	Git       GitConfig         `toml:"git"`
-	Ssh       SshConfig         `toml:"ssh"`
+	Keys      KeyMappings[keys]     `toml:"keys"`
+	UI        UIConfig              `toml:"ui"`
+	Suggest   SuggestConfig         `toml:"suggest"`
+	Revisions RevisionsConfig       `toml:"revisions"`
+	Preview   PreviewConfig         `toml:"preview"`
+	OpLog     OpLogConfig           `toml:"oplog"`
+	Limit     int                   `toml:"limit"`
+	Git       GitConfig             `toml:"git"`
+	Ssh       SshConfig             `toml:"ssh"`
+	Forge     ForgeConfig           `toml:"forge"`
+	Dashboard DashboardConfig       `toml:"dashboard"`
+	Views     map[string]ViewConfig `toml:"views"`
+	Plugins   []string              `toml:"plugins"`
 }
 
 type Color struct {
@@ -136,10 +140,12 @@ func GetExpiringFlashMessageTimeout(c *Config) time.Duration {
 }
 
 type RevisionsConfig struct {
-	LogBatching  bool   `toml:"log_batching"`
-	LogBatchSize int    `toml:"log_batch_size"`
-	Template     string `toml:"template"`
-	Revset       string `toml:"revset"`
+	LogBatching     bool   `toml:"log_batching"`
+	LogBatchSize    int    `toml:"log_batch_size"`
+	LogWindowSize   int    `toml:"log_window_size"`
+	StructuredGraph bool   `toml:"structured_graph"`
+	Template        string `toml:"template"`
+	Revset          string `toml:"revset"`
 }
 
 type PreviewPosition int
@@ -174,6 +180,17 @@ func GetPreviewPosition(c *Config) (PreviewPosition, error) {
 	}
 }
 
+type ForgeConfig struct {
+	Provider string   `toml:"provider"`
+	Command  []string `toml:"command"`
+	Limit    int      `toml:"limit"`
+}
+
+// DashboardConfig lists the repositories shown by `jjui --dashboard`
+type DashboardConfig struct {
+	Repos []string `toml:"repos"`
+}
+
 type OpLogConfig struct {
 	Limit int `toml:"limit"`
 }
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// maxFolds is the number of most recent folds of each kind kept in FoldState
+const maxFolds = 200
+
+// FoldState is the change ids of the linear runs and the branches folded in
+// the revisions view, oldest first
+type FoldState struct {
+	Runs     []string `json:"runs,omitempty"`
+	Branches []string `json:"branches,omitempty"`
+}
+
+// Folds persists the FoldState of a repository as JSON in the cache directory
+type Folds struct {
+	mutex sync.Mutex
+	file  string
+}
+
+// NewFolds returns the folds of the repository at location
+func NewFolds(location string) *Folds {
+	return NewFoldsFile(repoCacheFile("folds", location, ".json"))
+}
+
+func NewFoldsFile(file string) *Folds {
+	return &Folds{file: file}
+}
+
+// Load returns the saved state, which is empty when nothing was folded or when
+// there is no file
+func (f *Folds) Load() (FoldState, error) {
+	var state FoldState
+	if f == nil {
+		return state, nil
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	data, err := os.ReadFile(f.file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// Save replaces the saved state, keeping the most recent maxFolds folds of
+// each kind
+func (f *Folds) Save(state FoldState) error {
+	if f == nil {
+		return nil
+	}
+	recent := func(ids []string) []string {
+		return slices.Clone(ids[max(len(ids)-maxFolds, 0):])
+	}
+	data, err := json.Marshal(FoldState{Runs: recent(state.Runs), Branches: recent(state.Branches)})
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := os.MkdirAll(filepath.Dir(f.file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.file, data, 0644)
+}
+
+// repoCacheFile returns the file in the kind directory of the cache that
+// belongs to the repository at location
+func repoCacheFile(kind string, location string, extension string) string {
+	var cacheDir string
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = dir
+	} else {
+		cacheDir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(cacheDir, "jjui", kind, hex.EncodeToString(sum[:8])+extension)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolds_SaveAndLoad(t *testing.T) {
+	folds := NewFoldsFile(filepath.Join(t.TempDir(), "folds", "repo.json"))
+
+	state, err := folds.Load()
+	require.NoError(t, err)
+	assert.Equal(t, FoldState{}, state)
+
+	saved := FoldState{Runs: []string{"abc"}, Branches: []string{"def", "ghi"}}
+	require.NoError(t, folds.Save(saved))
+
+	state, err = folds.Load()
+	require.NoError(t, err)
+	assert.Equal(t, saved, state)
+}
+
+func TestFolds_Save_KeepsMostRecent(t *testing.T) {
+	folds := NewFoldsFile(filepath.Join(t.TempDir(), "repo.json"))
+	var runs []string
+	for i := range maxFolds + 5 {
+		runs = append(runs, fmt.Sprintf("change%d", i))
+	}
+	require.NoError(t, folds.Save(FoldState{Runs: runs}))
+
+	state, err := folds.Load()
+	require.NoError(t, err)
+	assert.Equal(t, runs[5:], state.Runs)
+}
+
+func TestFolds_NilIsEmpty(t *testing.T) {
+	var folds *Folds
+	state, err := folds.Load()
+	require.NoError(t, err)
+	assert.Equal(t, FoldState{}, state)
+	assert.NoError(t, folds.Save(FoldState{Runs: []string{"abc"}}))
+}
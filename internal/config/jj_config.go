@@ -1,13 +1,17 @@
 package config
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/BurntSushi/toml"
 )
 
 type JJConfig struct {
-	Colors        map[string]Color  `toml:"colors"`
-	RevsetAliases map[string]string `toml:"revset-aliases"`
-	Revsets       struct {
+	Colors          map[string]Color  `toml:"colors"`
+	RevsetAliases   map[string]string `toml:"revset-aliases"`
+	TemplateAliases map[string]string `toml:"template-aliases"`
+	Revsets         struct {
 		Log string `toml:"log"`
 	} `toml:"revsets"`
 	Templates struct {
@@ -39,6 +43,22 @@ func (c *JJConfig) GetApplicableColors() map[string]Color {
 	return ret
 }
 
+// TemplateNames returns the names of the template aliases that take no
+// parameters, so they can be used as a template on their own
+func (c *JJConfig) TemplateNames() []string {
+	var names []string
+	if c == nil {
+		return names
+	}
+	for name := range c.TemplateAliases {
+		if !strings.Contains(name, "(") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
 func parseConfig(configContent string) (*JJConfig, error) {
 	var config JJConfig
 	_, err := toml.Decode(configContent, &config)
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxJournalEntries is the number of most recent entries returned by Entries.
+// The file is trimmed to them once it holds twice as many lines.
+const maxJournalEntries = 1000
+
+// JournalEntry records a jj command run by jjui
+type JournalEntry struct {
+	Time     time.Time `json:"time"`
+	Args     []string  `json:"args"`
+	ExitCode int       `json:"exit_code"`
+	Stderr   string    `json:"stderr,omitempty"`
+	OpBefore string    `json:"op_before,omitempty"`
+	OpAfter  string    `json:"op_after,omitempty"`
+	// Input is what the command read from stdin, nil when it was given none
+	Input *string `json:"input,omitempty"`
+	// Interactive is set when the command was given the terminal
+	Interactive bool `json:"interactive,omitempty"`
+}
+
+var safeShellWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// ShellCommand returns the entry as a command line that can be pasted into a shell
+func (e JournalEntry) ShellCommand() string {
+	words := []string{"jj"}
+	for _, arg := range e.Args {
+		if safeShellWord.MatchString(arg) {
+			words = append(words, arg)
+		} else {
+			words = append(words, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Journal persists the jj commands run in a repository as JSON lines in the
+// cache directory
+type Journal struct {
+	mutex sync.Mutex
+	file  string
+	// lines is the number of lines in the file, counted on the first append
+	lines   int
+	counted bool
+}
+
+// NewJournal returns the journal of the repository at location
+func NewJournal(location string) *Journal {
+	return NewJournalFile(repoCacheFile("journal", location, ".jsonl"))
+}
+
+func NewJournalFile(file string) *Journal {
+	return &Journal{file: file}
+}
+
+func (j *Journal) Append(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if err := os.MkdirAll(filepath.Dir(j.file), 0755); err != nil {
+		return err
+	}
+	if !j.counted {
+		lines, err := readLines(j.file)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		j.lines, j.counted = len(lines), true
+	}
+	f, err := os.OpenFile(j.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	j.lines++
+	if j.lines > 2*maxJournalEntries {
+		return j.trim()
+	}
+	return nil
+}
+
+// trim rewrites the file with its most recent entries
+func (j *Journal) trim() error {
+	lines, err := readLines(j.file)
+	if err != nil {
+		return err
+	}
+	lines = lines[max(len(lines)-maxJournalEntries, 0):]
+	tmp := j.file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.file); err != nil {
+		return err
+	}
+	j.lines = len(lines)
+	return nil
+}
+
+// readLines returns the lines of the file
+func readLines(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Entries returns the most recent entries, oldest first. Lines that cannot be
+// parsed are skipped.
+func (j *Journal) Entries() ([]JournalEntry, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	f, err := os.Open(j.file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) > 2*maxJournalEntries {
+			entries = append([]JournalEntry(nil), entries[len(entries)-maxJournalEntries:]...)
+		}
+	}
+	if len(entries) > maxJournalEntries {
+		entries = entries[len(entries)-maxJournalEntries:]
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal_AppendAndEntries(t *testing.T) {
+	journal := NewJournalFile(filepath.Join(t.TempDir(), "journal", "repo.jsonl"))
+
+	entries, err := journal.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	input := "description"
+	first := JournalEntry{Time: time.Unix(100, 0).UTC(), Args: []string{"describe", "--stdin"}, Input: &input, OpBefore: "aaa", OpAfter: "bbb"}
+	second := JournalEntry{Time: time.Unix(200, 0).UTC(), Args: []string{"git", "push"}, ExitCode: 1, Stderr: "Error: rejected", Interactive: true}
+	require.NoError(t, journal.Append(first))
+	require.NoError(t, journal.Append(second))
+
+	entries, err = journal.Entries()
+	require.NoError(t, err)
+	assert.Equal(t, []JournalEntry{first, second}, entries)
+}
+
+func TestJournal_Append_TrimsToMostRecentEntries(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "repo.jsonl")
+	journal := NewJournalFile(file)
+	for i := range 2*maxJournalEntries + 1 {
+		require.NoError(t, journal.Append(JournalEntry{Args: []string{"status", strconv.Itoa(i)}}))
+	}
+
+	lines, err := readLines(file)
+	require.NoError(t, err)
+	assert.Len(t, lines, maxJournalEntries)
+	entries, err := journal.Entries()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status", strconv.Itoa(maxJournalEntries + 1)}, entries[0].Args)
+	assert.Equal(t, []string{"status", strconv.Itoa(2 * maxJournalEntries)}, entries[len(entries)-1].Args)
+}
+
+func TestJournal_Append_CountsLinesOfExistingFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "repo.jsonl")
+	require.NoError(t, os.WriteFile(file, []byte(strings.Repeat("{\"args\":[\"status\"]}\n", 2*maxJournalEntries)), 0644))
+
+	require.NoError(t, NewJournalFile(file).Append(JournalEntry{Args: []string{"new"}}))
+	lines, err := readLines(file)
+	require.NoError(t, err)
+	assert.Len(t, lines, maxJournalEntries)
+}
+
+func TestJournal_Entries_SkipsInvalidLines(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "repo.jsonl")
+	require.NoError(t, os.WriteFile(file, []byte("not json\n{\"args\":[\"status\"]}\n"), 0644))
+
+	entries, err := NewJournalFile(file).Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"status"}, entries[0].Args)
+}
+
+func TestJournalEntry_ShellCommand(t *testing.T) {
+	entry := JournalEntry{Args: []string{"describe", "-r", "abc", "-m", "it's done"}}
+	assert.Equal(t, `jj describe -r abc -m 'it'\''s done'`, entry.ShellCommand())
+}
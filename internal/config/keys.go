@@ -66,31 +66,34 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 		ExecShell:       key.NewBinding(key.WithKeys(m.ExecShell...), key.WithHelp(JoinKeys(m.ExecShell), "interactive shell command")),
 		CopyCommitSHA:   key.NewBinding(key.WithKeys(copyChangeID...), key.WithHelp(JoinKeys(copyChangeID), "copy change id")),
 		Revert: revertModeKeys[key.Binding]{
-			Mode:   key.NewBinding(key.WithKeys(m.Revert.Mode...), key.WithHelp(JoinKeys(m.Revert.Mode), "revert")),
-			Target: key.NewBinding(key.WithKeys(m.Revert.Target...), key.WithHelp(JoinKeys(m.Revert.Target), "target")),
-			After:  key.NewBinding(key.WithKeys(m.Revert.After...), key.WithHelp(JoinKeys(m.Revert.After), "insert after")),
-			Before: key.NewBinding(key.WithKeys(m.Revert.Before...), key.WithHelp(JoinKeys(m.Revert.Before), "insert before")),
-			Onto:   key.NewBinding(key.WithKeys(m.Revert.Onto...), key.WithHelp(JoinKeys(m.Revert.Onto), "onto")),
-			Insert: key.NewBinding(key.WithKeys(m.Revert.Insert...), key.WithHelp(JoinKeys(m.Revert.Insert), "insert between")),
+			Mode:          key.NewBinding(key.WithKeys(m.Revert.Mode...), key.WithHelp(JoinKeys(m.Revert.Mode), "revert")),
+			Target:        key.NewBinding(key.WithKeys(m.Revert.Target...), key.WithHelp(JoinKeys(m.Revert.Target), "target")),
+			After:         key.NewBinding(key.WithKeys(m.Revert.After...), key.WithHelp(JoinKeys(m.Revert.After), "insert after")),
+			Before:        key.NewBinding(key.WithKeys(m.Revert.Before...), key.WithHelp(JoinKeys(m.Revert.Before), "insert before")),
+			Onto:          key.NewBinding(key.WithKeys(m.Revert.Onto...), key.WithHelp(JoinKeys(m.Revert.Onto), "onto")),
+			Insert:        key.NewBinding(key.WithKeys(m.Revert.Insert...), key.WithHelp(JoinKeys(m.Revert.Insert), "insert between")),
+			PreviewResult: key.NewBinding(key.WithKeys(m.Revert.PreviewResult...), key.WithHelp(JoinKeys(m.Revert.PreviewResult), "preview result")),
 		},
 		Rebase: rebaseModeKeys[key.Binding]{
-			Mode:        key.NewBinding(key.WithKeys(m.Rebase.Mode...), key.WithHelp(JoinKeys(m.Rebase.Mode), "rebase")),
-			Revision:    key.NewBinding(key.WithKeys(m.Rebase.Revision...), key.WithHelp(JoinKeys(m.Rebase.Revision), "revision")),
-			Source:      key.NewBinding(key.WithKeys(m.Rebase.Source...), key.WithHelp(JoinKeys(m.Rebase.Source), "source")),
-			Branch:      key.NewBinding(key.WithKeys(m.Rebase.Branch...), key.WithHelp(JoinKeys(m.Rebase.Branch), "branch")),
-			Target:      key.NewBinding(key.WithKeys(m.Rebase.Target...), key.WithHelp(JoinKeys(m.Rebase.Target), "target")),
-			After:       key.NewBinding(key.WithKeys(m.Rebase.After...), key.WithHelp(JoinKeys(m.Rebase.After), "insert after")),
-			Before:      key.NewBinding(key.WithKeys(m.Rebase.Before...), key.WithHelp(JoinKeys(m.Rebase.Before), "insert before")),
-			Onto:        key.NewBinding(key.WithKeys(m.Rebase.Onto...), key.WithHelp(JoinKeys(m.Rebase.Onto), "onto")),
-			Insert:      key.NewBinding(key.WithKeys(m.Rebase.Insert...), key.WithHelp(JoinKeys(m.Rebase.Insert), "insert between")),
-			SkipEmptied: key.NewBinding(key.WithKeys(m.Rebase.SkipEmptied...), key.WithHelp(JoinKeys(m.Rebase.SkipEmptied), "skip emptied")),
+			Mode:          key.NewBinding(key.WithKeys(m.Rebase.Mode...), key.WithHelp(JoinKeys(m.Rebase.Mode), "rebase")),
+			Revision:      key.NewBinding(key.WithKeys(m.Rebase.Revision...), key.WithHelp(JoinKeys(m.Rebase.Revision), "revision")),
+			Source:        key.NewBinding(key.WithKeys(m.Rebase.Source...), key.WithHelp(JoinKeys(m.Rebase.Source), "source")),
+			Branch:        key.NewBinding(key.WithKeys(m.Rebase.Branch...), key.WithHelp(JoinKeys(m.Rebase.Branch), "branch")),
+			Target:        key.NewBinding(key.WithKeys(m.Rebase.Target...), key.WithHelp(JoinKeys(m.Rebase.Target), "target")),
+			After:         key.NewBinding(key.WithKeys(m.Rebase.After...), key.WithHelp(JoinKeys(m.Rebase.After), "insert after")),
+			Before:        key.NewBinding(key.WithKeys(m.Rebase.Before...), key.WithHelp(JoinKeys(m.Rebase.Before), "insert before")),
+			Onto:          key.NewBinding(key.WithKeys(m.Rebase.Onto...), key.WithHelp(JoinKeys(m.Rebase.Onto), "onto")),
+			Insert:        key.NewBinding(key.WithKeys(m.Rebase.Insert...), key.WithHelp(JoinKeys(m.Rebase.Insert), "insert between")),
+			SkipEmptied:   key.NewBinding(key.WithKeys(m.Rebase.SkipEmptied...), key.WithHelp(JoinKeys(m.Rebase.SkipEmptied), "skip emptied")),
+			PreviewResult: key.NewBinding(key.WithKeys(m.Rebase.PreviewResult...), key.WithHelp(JoinKeys(m.Rebase.PreviewResult), "preview result")),
 		},
 		Duplicate: duplicateModeKeys[key.Binding]{
-			Mode:   key.NewBinding(key.WithKeys(m.Duplicate.Mode...), key.WithHelp(JoinKeys(m.Duplicate.Mode), "duplicate")),
-			Target: key.NewBinding(key.WithKeys(m.Duplicate.Target...), key.WithHelp(JoinKeys(m.Duplicate.Target), "target")),
-			After:  key.NewBinding(key.WithKeys(m.Duplicate.After...), key.WithHelp(JoinKeys(m.Duplicate.After), "duplicate after")),
-			Before: key.NewBinding(key.WithKeys(m.Duplicate.Before...), key.WithHelp(JoinKeys(m.Duplicate.Before), "duplicate before")),
-			Onto:   key.NewBinding(key.WithKeys(m.Duplicate.Onto...), key.WithHelp(JoinKeys(m.Duplicate.Onto), "duplicate onto")),
+			Mode:          key.NewBinding(key.WithKeys(m.Duplicate.Mode...), key.WithHelp(JoinKeys(m.Duplicate.Mode), "duplicate")),
+			Target:        key.NewBinding(key.WithKeys(m.Duplicate.Target...), key.WithHelp(JoinKeys(m.Duplicate.Target), "target")),
+			After:         key.NewBinding(key.WithKeys(m.Duplicate.After...), key.WithHelp(JoinKeys(m.Duplicate.After), "duplicate after")),
+			Before:        key.NewBinding(key.WithKeys(m.Duplicate.Before...), key.WithHelp(JoinKeys(m.Duplicate.Before), "duplicate before")),
+			Onto:          key.NewBinding(key.WithKeys(m.Duplicate.Onto...), key.WithHelp(JoinKeys(m.Duplicate.Onto), "duplicate onto")),
+			PreviewResult: key.NewBinding(key.WithKeys(m.Duplicate.PreviewResult...), key.WithHelp(JoinKeys(m.Duplicate.PreviewResult), "preview result")),
 		},
 		Squash: squashModeKeys[key.Binding]{
 			Mode:                  key.NewBinding(key.WithKeys(m.Squash.Mode...), key.WithHelp(JoinKeys(m.Squash.Mode), "squash")),
@@ -110,6 +113,8 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Diff:                  key.NewBinding(key.WithKeys(m.Details.Diff...), key.WithHelp(JoinKeys(m.Details.Diff), "diff")),
 			ToggleSelect:          key.NewBinding(key.WithKeys(m.Details.ToggleSelect...), key.WithHelp(JoinKeys(m.Details.ToggleSelect), "toggle select")),
 			RevisionsChangingFile: key.NewBinding(key.WithKeys(m.Details.RevisionsChangingFile...), key.WithHelp(JoinKeys(m.Details.RevisionsChangingFile), "show revisions changing file")),
+			PickHunks:             key.NewBinding(key.WithKeys(m.Details.PickHunks...), key.WithHelp(JoinKeys(m.Details.PickHunks), "pick hunks")),
+			ResolveConflicts:      key.NewBinding(key.WithKeys(m.Details.ResolveConflicts...), key.WithHelp(JoinKeys(m.Details.ResolveConflicts), "resolve conflicts")),
 		},
 		Bookmark: bookmarkModeKeys[key.Binding]{
 			Mode:    key.NewBinding(key.WithKeys(m.Bookmark.Mode...), key.WithHelp(JoinKeys(m.Bookmark.Mode), "bookmarks")),
@@ -163,8 +168,53 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			HalfPageDown: key.NewBinding(key.WithKeys(m.DiffView.HalfPageDown...), key.WithHelp(JoinKeys(m.DiffView.HalfPageDown), "half page down")),
 			Left:         key.NewBinding(key.WithKeys(m.DiffView.Left...), key.WithHelp(JoinKeys(m.DiffView.Left), "scroll left")),
 			Right:        key.NewBinding(key.WithKeys(m.DiffView.Right...), key.WithHelp(JoinKeys(m.DiffView.Right), "scroll right")),
+			NextFile:     key.NewBinding(key.WithKeys(m.DiffView.NextFile...), key.WithHelp(JoinKeys(m.DiffView.NextFile), "next file")),
+			PrevFile:     key.NewBinding(key.WithKeys(m.DiffView.PrevFile...), key.WithHelp(JoinKeys(m.DiffView.PrevFile), "prev file")),
+			NextHunk:     key.NewBinding(key.WithKeys(m.DiffView.NextHunk...), key.WithHelp(JoinKeys(m.DiffView.NextHunk), "next hunk")),
+			PrevHunk:     key.NewBinding(key.WithKeys(m.DiffView.PrevHunk...), key.WithHelp(JoinKeys(m.DiffView.PrevHunk), "prev hunk")),
+			ToggleFold:   key.NewBinding(key.WithKeys(m.DiffView.ToggleFold...), key.WithHelp(JoinKeys(m.DiffView.ToggleFold), "fold file")),
+			SideBySide:   key.NewBinding(key.WithKeys(m.DiffView.SideBySide...), key.WithHelp(JoinKeys(m.DiffView.SideBySide), "side by side")),
 			Close:        key.NewBinding(key.WithKeys(m.DiffView.Close...), key.WithHelp(JoinKeys(m.DiffView.Close), "close")),
 		},
+		HunkPicker: hunkPickerKeys[key.Binding]{
+			Toggle:  key.NewBinding(key.WithKeys(m.HunkPicker.Toggle...), key.WithHelp(JoinKeys(m.HunkPicker.Toggle), "toggle")),
+			Split:   key.NewBinding(key.WithKeys(m.HunkPicker.Split...), key.WithHelp(JoinKeys(m.HunkPicker.Split), "split")),
+			Squash:  key.NewBinding(key.WithKeys(m.HunkPicker.Squash...), key.WithHelp(JoinKeys(m.HunkPicker.Squash), "squash")),
+			Restore: key.NewBinding(key.WithKeys(m.HunkPicker.Restore...), key.WithHelp(JoinKeys(m.HunkPicker.Restore), "restore")),
+			Close:   key.NewBinding(key.WithKeys(m.HunkPicker.Close...), key.WithHelp(JoinKeys(m.HunkPicker.Close), "close")),
+		},
+		Conflicts: conflictsKeys[key.Binding]{
+			Ours:   key.NewBinding(key.WithKeys(m.Conflicts.Ours...), key.WithHelp(JoinKeys(m.Conflicts.Ours), "ours")),
+			Theirs: key.NewBinding(key.WithKeys(m.Conflicts.Theirs...), key.WithHelp(JoinKeys(m.Conflicts.Theirs), "theirs")),
+			Base:   key.NewBinding(key.WithKeys(m.Conflicts.Base...), key.WithHelp(JoinKeys(m.Conflicts.Base), "base")),
+			Both:   key.NewBinding(key.WithKeys(m.Conflicts.Both...), key.WithHelp(JoinKeys(m.Conflicts.Both), "both")),
+			Reset:  key.NewBinding(key.WithKeys(m.Conflicts.Reset...), key.WithHelp(JoinKeys(m.Conflicts.Reset), "unresolve")),
+			Write:  key.NewBinding(key.WithKeys(m.Conflicts.Write...), key.WithHelp(JoinKeys(m.Conflicts.Write), "write")),
+			Close:  key.NewBinding(key.WithKeys(m.Conflicts.Close...), key.WithHelp(JoinKeys(m.Conflicts.Close), "close")),
+		},
+		Journal: journalKeys[key.Binding]{
+			Mode:  key.NewBinding(key.WithKeys(m.Journal.Mode...), key.WithHelp(JoinKeys(m.Journal.Mode), "journal")),
+			Copy:  key.NewBinding(key.WithKeys(m.Journal.Copy...), key.WithHelp(JoinKeys(m.Journal.Copy), "copy as shell command")),
+			Rerun: key.NewBinding(key.WithKeys(m.Journal.Rerun...), key.WithHelp(JoinKeys(m.Journal.Rerun), "re-run")),
+			Close: key.NewBinding(key.WithKeys(m.Journal.Close...), key.WithHelp(JoinKeys(m.Journal.Close), "close")),
+		},
+		Views: viewsKeys[key.Binding]{
+			Mode:   key.NewBinding(key.WithKeys(m.Views.Mode...), key.WithHelp(JoinKeys(m.Views.Mode), "views")),
+			Save:   key.NewBinding(key.WithKeys(m.Views.Save...), key.WithHelp(JoinKeys(m.Views.Save), "save current revset")),
+			Delete: key.NewBinding(key.WithKeys(m.Views.Delete...), key.WithHelp(JoinKeys(m.Views.Delete), "delete saved view")),
+			Close:  key.NewBinding(key.WithKeys(m.Views.Close...), key.WithHelp(JoinKeys(m.Views.Close), "close")),
+		},
+		Templates: templatesKeys[key.Binding]{
+			Mode:   key.NewBinding(key.WithKeys(m.Templates.Mode...), key.WithHelp(JoinKeys(m.Templates.Mode), "templates")),
+			Fields: key.NewBinding(key.WithKeys(m.Templates.Fields...), key.WithHelp(JoinKeys(m.Templates.Fields), "templates/fields")),
+			Toggle: key.NewBinding(key.WithKeys(m.Templates.Toggle...), key.WithHelp(JoinKeys(m.Templates.Toggle), "toggle field")),
+			Close:  key.NewBinding(key.WithKeys(m.Templates.Close...), key.WithHelp(JoinKeys(m.Templates.Close), "close")),
+		},
+		Fold: foldKeys[key.Binding]{
+			Run:       key.NewBinding(key.WithKeys(m.Fold.Run...), key.WithHelp(JoinKeys(m.Fold.Run), "fold linear run")),
+			Branch:    key.NewBinding(key.WithKeys(m.Fold.Branch...), key.WithHelp(JoinKeys(m.Fold.Branch), "collapse branch")),
+			UnfoldAll: key.NewBinding(key.WithKeys(m.Fold.UnfoldAll...), key.WithHelp(JoinKeys(m.Fold.UnfoldAll), "unfold all")),
+		},
 	}
 }
 
@@ -257,6 +307,12 @@ type KeyMappings[T any] struct {
 	OpLog             opLogModeKeys[T]          `toml:"oplog"`
 	FileSearch        fileSearchKeys[T]         `toml:"file_search"`
 	DiffView          diffModeKeys[T]           `toml:"diff_view"`
+	HunkPicker        hunkPickerKeys[T]         `toml:"hunk_picker"`
+	Conflicts         conflictsKeys[T]          `toml:"conflicts"`
+	Journal           journalKeys[T]            `toml:"journal"`
+	Fold              foldKeys[T]               `toml:"fold"`
+	Views             viewsKeys[T]              `toml:"views"`
+	Templates         templatesKeys[T]          `toml:"templates"`
 }
 
 type bookmarkModeKeys[T any] struct {
@@ -280,33 +336,36 @@ type squashModeKeys[T any] struct {
 }
 
 type revertModeKeys[T any] struct {
-	Mode   T `toml:"mode"`
-	Target T `toml:"target"`
-	After  T `toml:"after"`
-	Before T `toml:"before"`
-	Onto   T `toml:"onto"`
-	Insert T `toml:"insert"`
+	Mode          T `toml:"mode"`
+	Target        T `toml:"target"`
+	After         T `toml:"after"`
+	Before        T `toml:"before"`
+	Onto          T `toml:"onto"`
+	Insert        T `toml:"insert"`
+	PreviewResult T `toml:"preview_result"`
 }
 
 type rebaseModeKeys[T any] struct {
-	Mode        T `toml:"mode"`
-	Revision    T `toml:"revision"`
-	Source      T `toml:"source"`
-	Branch      T `toml:"branch"`
-	Target      T `toml:"target"`
-	After       T `toml:"after"`
-	Before      T `toml:"before"`
-	Onto        T `toml:"onto"`
-	Insert      T `toml:"insert"`
-	SkipEmptied T `toml:"skip_emptied"`
+	Mode          T `toml:"mode"`
+	Revision      T `toml:"revision"`
+	Source        T `toml:"source"`
+	Branch        T `toml:"branch"`
+	Target        T `toml:"target"`
+	After         T `toml:"after"`
+	Before        T `toml:"before"`
+	Onto          T `toml:"onto"`
+	Insert        T `toml:"insert"`
+	SkipEmptied   T `toml:"skip_emptied"`
+	PreviewResult T `toml:"preview_result"`
 }
 
 type duplicateModeKeys[T any] struct {
-	Mode   T `toml:"mode"`
-	Target T `toml:"target"`
-	After  T `toml:"after"`
-	Before T `toml:"before"`
-	Onto   T `toml:"onto"`
+	Mode          T `toml:"mode"`
+	Target        T `toml:"target"`
+	After         T `toml:"after"`
+	Before        T `toml:"before"`
+	Onto          T `toml:"onto"`
+	PreviewResult T `toml:"preview_result"`
 }
 
 type evologModeKeys[T any] struct {
@@ -326,6 +385,8 @@ type detailsModeKeys[T any] struct {
 	Diff                  T `toml:"diff"`
 	ToggleSelect          T `toml:"select"`
 	RevisionsChangingFile T `toml:"revisions_changing_file"`
+	PickHunks             T `toml:"pick_hunks"`
+	ResolveConflicts      T `toml:"resolve_conflicts"`
 }
 
 type gitModeKeys[T any] struct {
@@ -365,6 +426,51 @@ type fileSearchKeys[T any] struct {
 	Edit   T `toml:"edit"`
 }
 
+type hunkPickerKeys[T any] struct {
+	Toggle  T `toml:"toggle"`
+	Split   T `toml:"split"`
+	Squash  T `toml:"squash"`
+	Restore T `toml:"restore"`
+	Close   T `toml:"close"`
+}
+
+type conflictsKeys[T any] struct {
+	Ours   T `toml:"ours"`
+	Theirs T `toml:"theirs"`
+	Base   T `toml:"base"`
+	Both   T `toml:"both"`
+	Reset  T `toml:"reset"`
+	Write  T `toml:"write"`
+	Close  T `toml:"close"`
+}
+
+type foldKeys[T any] struct {
+	Run       T `toml:"run"`
+	Branch    T `toml:"branch"`
+	UnfoldAll T `toml:"unfold_all"`
+}
+
+type viewsKeys[T any] struct {
+	Mode   T `toml:"mode"`
+	Save   T `toml:"save"`
+	Delete T `toml:"delete"`
+	Close  T `toml:"close"`
+}
+
+type templatesKeys[T any] struct {
+	Mode   T `toml:"mode"`
+	Fields T `toml:"fields"`
+	Toggle T `toml:"toggle"`
+	Close  T `toml:"close"`
+}
+
+type journalKeys[T any] struct {
+	Mode  T `toml:"mode"`
+	Copy  T `toml:"copy"`
+	Rerun T `toml:"rerun"`
+	Close T `toml:"close"`
+}
+
 type diffModeKeys[T any] struct {
 	ScrollUp     T `toml:"scroll_up"`
 	ScrollDown   T `toml:"scroll_down"`
@@ -374,5 +480,11 @@ type diffModeKeys[T any] struct {
 	HalfPageDown T `toml:"half_page_down"`
 	Left         T `toml:"left"`
 	Right        T `toml:"right"`
+	NextFile     T `toml:"next_file"`
+	PrevFile     T `toml:"prev_file"`
+	NextHunk     T `toml:"next_hunk"`
+	PrevHunk     T `toml:"prev_hunk"`
+	ToggleFold   T `toml:"toggle_fold"`
+	SideBySide   T `toml:"side_by_side"`
 	Close        T `toml:"close"`
 }
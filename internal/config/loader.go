@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
@@ -46,6 +47,11 @@ func getConfigFilePath() string {
 	return ""
 }
 
+// ConfigFile returns the location of config.toml, which may not exist yet
+func ConfigFile() string {
+	return getConfigFilePath()
+}
+
 func loadDefaultConfig() *Config {
 	data, err := configFS.ReadFile("default/config.toml")
 	if err != nil {
@@ -62,6 +68,11 @@ func loadDefaultConfig() *Config {
 	return config
 }
 
+// Default returns a new copy of the embedded default config
+func Default() *Config {
+	return loadDefaultConfig()
+}
+
 func (c *Config) Load(data string) error {
 	var err error
 
@@ -86,6 +97,16 @@ func LoadConfigFile() ([]byte, error) {
 	return data, nil
 }
 
+// LoadInitLua reads init.lua from the config directory. It is loaded once at
+// startup to register Lua hooks.
+func LoadInitLua() ([]byte, error) {
+	configFile := getConfigFilePath()
+	if configFile == "" {
+		return nil, fs.ErrNotExist
+	}
+	return os.ReadFile(filepath.Join(filepath.Dir(configFile), "init.lua"))
+}
+
 func loadTheme(data []byte, base map[string]Color) (map[string]Color, error) {
 	colors := make(map[string]Color)
 	for key, color := range base {
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// PluginDirs returns the directories Lua modules are required from: the
+// plugins directory of the repository at root, then the one next to
+// config.toml
+func PluginDirs(root string) []string {
+	var dirs []string
+	if root != "" {
+		dirs = append(dirs, filepath.Join(root, ".jj", "jjui", "plugins"))
+	}
+	if configFile := getConfigFilePath(); configFile != "" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(configFile), "plugins"))
+	}
+	return dirs
+}
+
+// FindPlugin returns the file of a Lua script looked up in the plugin
+// directories, unless its name is an absolute path
+func FindPlugin(root string, name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	for _, dir := range PluginDirs(root) {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("%s is not in the plugin directories: %w", name, fs.ErrNotExist)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPlugin_PrefersRepositoryDirectory(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("JJUI_CONFIG_DIR", configDir)
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "plugins"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "plugins", "stack.lua"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "plugins", "review.lua"), nil, 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".jj", "jjui", "plugins"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".jj", "jjui", "plugins", "stack.lua"), nil, 0o644))
+
+	file, err := FindPlugin(root, "stack.lua")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".jj", "jjui", "plugins", "stack.lua"), file)
+
+	file, err = FindPlugin(root, "review.lua")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configDir, "plugins", "review.lua"), file)
+
+	_, err = FindPlugin(root, "missing.lua")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// repoConfigFiles are the locations of the repository config relative to the
+// repository root, in order of precedence
+var repoConfigFiles = []string{
+	filepath.Join(".jj", "jjui.toml"),
+	".jjui.toml",
+}
+
+// RepoConfig is a config file of a repository that is loaded over the global
+// config.toml
+type RepoConfig struct {
+	File string
+	Data []byte
+}
+
+// LoadRepoConfig reads the config file of the repository at root
+func LoadRepoConfig(root string) (*RepoConfig, error) {
+	for _, name := range repoConfigFiles {
+		file := filepath.Join(root, name)
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &RepoConfig{File: file, Data: data}, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+// checksum identifies the file together with its content so that a changed
+// repository config has to be trusted again
+func (r *RepoConfig) checksum() string {
+	sum := sha256.Sum256(append([]byte(r.File+"\x00"), r.Data...))
+	return hex.EncodeToString(sum[:])
+}
+
+func getTrustFilePath() string {
+	configFile := getConfigFilePath()
+	if configFile == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(configFile), "trusted")
+}
+
+// IsTrusted reports whether the user trusted this version of the repository
+// config before
+func (r *RepoConfig) IsTrusted() bool {
+	trustFile := getTrustFilePath()
+	if trustFile == "" {
+		return false
+	}
+	f, err := os.Open(trustFile)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	checksum := r.checksum()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if sum, _, _ := strings.Cut(scanner.Text(), " "); sum == checksum {
+			return true
+		}
+	}
+	return false
+}
+
+// Trust remembers this version of the repository config as trusted
+func (r *RepoConfig) Trust() error {
+	trustFile := getTrustFilePath()
+	if trustFile == "" {
+		return errors.New("no config directory to store trusted repository configs")
+	}
+	if err := os.MkdirAll(filepath.Dir(trustFile), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(trustFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s %s\n", r.checksum(), r.File)
+	return err
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepoConfig(t *testing.T) {
+	root := t.TempDir()
+	_, err := LoadRepoConfig(root)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".jjui.toml"), []byte("root"), 0644))
+	repoConfig, err := LoadRepoConfig(root)
+	require.NoError(t, err)
+	assert.Equal(t, "root", string(repoConfig.Data))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".jj"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".jj", "jjui.toml"), []byte("jj"), 0644))
+	repoConfig, err = LoadRepoConfig(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".jj", "jjui.toml"), repoConfig.File)
+	assert.Equal(t, "jj", string(repoConfig.Data))
+}
+
+func TestRepoConfig_Trust(t *testing.T) {
+	t.Setenv("JJUI_CONFIG_DIR", t.TempDir())
+
+	repoConfig := &RepoConfig{File: "/repo/.jj/jjui.toml", Data: []byte("[revisions]\nrevset = \"@\"\n")}
+	assert.False(t, repoConfig.IsTrusted())
+
+	require.NoError(t, repoConfig.Trust())
+	assert.True(t, repoConfig.IsTrusted())
+
+	changed := &RepoConfig{File: repoConfig.File, Data: []byte("[revisions]\nrevset = \"all()\"\n")}
+	assert.False(t, changed.IsTrusted())
+}
+
+func TestLoad_OverlaysExistingConfig(t *testing.T) {
+	config := &Config{}
+	require.NoError(t, config.Load(`
+[revisions]
+revset = "::@"
+[ui.colors]
+"text" = "white"
+[preview]
+revision_command = ["show"]
+`))
+	require.NoError(t, config.Load(`
+[revisions]
+revset = "mine()"
+[ui.colors]
+"selected" = "blue"
+`))
+	assert.Equal(t, "mine()", config.Revisions.Revset)
+	assert.Equal(t, []string{"show"}, config.Preview.RevisionCommand)
+	assert.Equal(t, "white", config.UI.Colors["text"].Fg)
+	assert.Equal(t, "blue", config.UI.Colors["selected"].Fg)
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Problem is an issue found while validating a config file
+type Problem struct {
+	Line    int // 0 when the line is not known
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// In formats the problem as file:line: message, like compilers do
+func (p Problem) In(file string) string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", file, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", file, p.Message)
+}
+
+// externalTables are decoded outside Config and validated where they are loaded
+var externalTables = []string{"custom_commands", "leader"}
+
+// Validate checks a config file for unknown keys, invalid values and key
+// bindings that conflict with each other. An error is returned when the file
+// cannot be decoded at all.
+func Validate(data string) ([]Problem, error) {
+	c := Default()
+	md, err := toml.Decode(data, c)
+	if err != nil {
+		return nil, err
+	}
+	lines := FindKeyLines(data)
+
+	var problems []Problem
+	var reported []toml.Key
+	for _, key := range md.Undecoded() {
+		if slices.Contains(externalTables, key[0]) {
+			continue
+		}
+		// the keys of an unknown table are not reported again
+		if slices.ContainsFunc(reported, func(parent toml.Key) bool { return isPrefix(parent, key) }) {
+			continue
+		}
+		reported = append(reported, key)
+		problems = append(problems, Problem{Line: lines.Line(key...), Message: fmt.Sprintf("unknown key %q", key.String())})
+	}
+
+	if md.IsDefined("preview", "position") {
+		if _, err := GetPreviewPosition(c); err != nil {
+			problems = append(problems, Problem{Line: lines.Line("preview", "position"), Message: err.Error()})
+		}
+	}
+	if md.IsDefined("suggest", "exec", "mode") {
+		if _, err := GetSuggestExecMode(c); err != nil {
+			problems = append(problems, Problem{Line: lines.Line("suggest", "exec", "mode"), Message: err.Error()})
+		}
+	}
+
+	problems = append(problems, keyConflicts(c, md, lines)...)
+	SortProblems(problems)
+	return problems, nil
+}
+
+// SortProblems orders problems by their line
+func SortProblems(problems []Problem) {
+	slices.SortStableFunc(problems, func(a, b Problem) int { return a.Line - b.Line })
+}
+
+func isPrefix(parent toml.Key, key toml.Key) bool {
+	return len(parent) < len(key) && slices.Equal(parent, key[:len(parent)])
+}
+
+type binding struct {
+	path []string
+	keys []string
+}
+
+type conflict struct {
+	a, b string
+	key  string
+}
+
+// keyConflicts reports keys bound to more than one action that can be used at
+// the same time. The actions of a mode are checked against each other, and the
+// keys entering the modes against the actions of the revisions view. Conflicts
+// that are already in the default config are not reported.
+func keyConflicts(c *Config, md toml.MetaData, lines KeyLines) []Problem {
+	defaults := findConflicts(Default())
+	var problems []Problem
+	for _, conflict := range findConflicts(c) {
+		if slices.Contains(defaults, conflict) {
+			continue
+		}
+		a, b := strings.Split(conflict.a, "."), strings.Split(conflict.b, ".")
+		line := lines.Line(b...)
+		if !md.IsDefined(b...) {
+			line = lines.Line(a...)
+		}
+		problems = append(problems, Problem{
+			Line:    line,
+			Message: fmt.Sprintf("key %q is bound to both %s and %s", JoinKeys([]string{conflict.key}), conflict.a, conflict.b),
+		})
+	}
+	return problems
+}
+
+// globalGroups are the groups of keys without a mode, bound in the revisions
+// view next to the global keys
+var globalGroups = []string{"fold"}
+
+func findConflicts(c *Config) []conflict {
+	var global []binding
+	var modes [][]binding
+	keysType := reflect.TypeFor[keys]()
+
+	value := reflect.ValueOf(c.Keys)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := field.Tag.Get("toml")
+		if field.Type == keysType {
+			global = append(global, binding{path: []string{"keys", name}, keys: value.Field(i).Interface().(keys)})
+			continue
+		}
+		var mode []binding
+		modeValue := value.Field(i)
+		for j := 0; j < modeValue.NumField(); j++ {
+			modeField := modeValue.Type().Field(j)
+			b := binding{path: []string{"keys", name, modeField.Tag.Get("toml")}, keys: modeValue.Field(j).Interface().(keys)}
+			if modeField.Name == "Mode" || slices.Contains(globalGroups, name) {
+				global = append(global, b)
+			} else {
+				mode = append(mode, b)
+			}
+		}
+		modes = append(modes, mode)
+	}
+
+	var conflicts []conflict
+	for _, scope := range append([][]binding{global}, modes...) {
+		for i, a := range scope {
+			for _, b := range scope[i+1:] {
+				for _, k := range a.keys {
+					if slices.Contains(b.keys, k) {
+						conflicts = append(conflicts, conflict{a: strings.Join(a.path, "."), b: strings.Join(b.path, "."), key: k})
+					}
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// KeyLines maps the keys of a TOML document to the lines they are defined on
+type KeyLines map[string]int
+
+// FindKeyLines locates the tables and the keys of a TOML document. It does not
+// parse values, so keys inside inline tables and multi-line values are not
+// found; Line falls back to their parent for those.
+func FindKeyLines(data string) KeyLines {
+	lines := KeyLines{}
+	var table []string
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			// the header may be followed by a comment
+			header := line[:strings.LastIndex(line, "]")+1]
+			table = splitKey(strings.Trim(header, "[] "))
+			lines.add(table, i+1)
+			continue
+		}
+		name, _, found := cutOutsideQuotes(line, '=')
+		if !found {
+			continue
+		}
+		lines.add(append(slices.Clone(table), splitKey(name)...), i+1)
+	}
+	return lines
+}
+
+func (k KeyLines) add(key []string, line int) {
+	name := strings.Join(key, ".")
+	if _, ok := k[name]; !ok {
+		k[name] = line
+	}
+}
+
+// Line returns the line of the key, or of its closest parent that was found
+func (k KeyLines) Line(key ...string) int {
+	for n := len(key); n > 0; n-- {
+		if line, ok := k[strings.Join(key[:n], ".")]; ok {
+			return line
+		}
+	}
+	return 0
+}
+
+// splitKey splits a dotted TOML key into its parts, removing the quotes
+func splitKey(key string) []string {
+	var parts []string
+	for {
+		part, rest, found := cutOutsideQuotes(key, '.')
+		part = strings.TrimSpace(part)
+		if len(part) >= 2 && (part[0] == '"' || part[0] == '\'') && part[len(part)-1] == part[0] {
+			part = part[1 : len(part)-1]
+		}
+		parts = append(parts, part)
+		if !found {
+			return parts
+		}
+		key = rest
+	}
+}
+
+func cutOutsideQuotes(s string, sep byte) (string, string, bool) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_UnknownKeys(t *testing.T) {
+	problems, err := Validate(`limit = 10
+
+[keys.rebas]
+  mode = ["r"]
+  after = ["a"]
+
+[preview]
+  postion = "right"
+
+[custom_commands.hello]
+  args = ["log"]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{Line: 3, Message: `unknown key "keys.rebas"`},
+		{Line: 8, Message: `unknown key "preview.postion"`},
+	}, problems)
+}
+
+func TestValidate_InvalidValues(t *testing.T) {
+	problems, err := Validate(`[preview]
+position = "left"
+[suggest.exec]
+mode = "smart"
+`)
+	require.NoError(t, err)
+	require.Len(t, problems, 2)
+	assert.Equal(t, 2, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "preview.position")
+	assert.Equal(t, 4, problems[1].Line)
+	assert.Contains(t, problems[1].Message, "suggest.exec.mode")
+}
+
+func TestValidate_KeyConflicts(t *testing.T) {
+	problems, err := Validate(`[keys]
+new = ["d"]
+[keys.git]
+mode = ["o"]
+[keys.rebase]
+after = ["b"]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{Line: 2, Message: `key "d" is bound to both keys.new and keys.diff`},
+		{Line: 4, Message: `key "o" is bound to both keys.git.mode and keys.oplog.mode`},
+		{Line: 6, Message: `key "b" is bound to both keys.rebase.after and keys.rebase.before`},
+	}, problems)
+}
+
+func TestValidate_FoldKeysConflictWithGlobalKeys(t *testing.T) {
+	problems, err := Validate(`[keys.fold]
+run = ["n"]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{Line: 2, Message: `key "n" is bound to both keys.new and keys.fold.run`},
+	}, problems)
+}
+
+func TestValidate_DefaultConfigHasNoProblems(t *testing.T) {
+	data, err := configFS.ReadFile("default/config.toml")
+	require.NoError(t, err)
+	problems, err := Validate(string(data))
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestValidate_InvalidToml(t *testing.T) {
+	_, err := Validate("[keys\n")
+	assert.Error(t, err)
+}
+
+func TestFindKeyLines(t *testing.T) {
+	lines := FindKeyLines(`# comment
+[ui.colors]
+"revisions selected" = { fg = "blue" }
+[keys.rebase] # rebase
+after.x = ["a"]
+`)
+	assert.Equal(t, 3, lines.Line("ui", "colors", "revisions selected", "fg"))
+	assert.Equal(t, 4, lines.Line("keys", "rebase"))
+	assert.Equal(t, 5, lines.Line("keys", "rebase", "after", "x"))
+	assert.Equal(t, 0, lines.Line("limit"))
+}
+
+func TestProblem_In(t *testing.T) {
+	assert.Equal(t, "config.toml:3: unknown key \"x\"", Problem{Line: 3, Message: `unknown key "x"`}.In("config.toml"))
+	assert.Equal(t, "config.toml: unknown key \"x\"", Problem{Message: `unknown key "x"`}.In("config.toml"))
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ViewConfig is a revset shown with its own settings under a name
+type ViewConfig struct {
+	Name     string   `toml:"-"`
+	Revset   string   `toml:"revset"`
+	Template string   `toml:"template,omitempty"` // overrides the template of the revisions
+	Limit    int      `toml:"limit,omitempty"`    // overrides the number of revisions shown
+	Preview  *bool    `toml:"preview,omitempty"`  // shows or hides the preview, kept as is when unset
+	Key      []string `toml:"key,omitempty"`
+}
+
+type viewsFile struct {
+	Views map[string]ViewConfig `toml:"views"`
+}
+
+// SavedViews persists the views created while jjui runs in the .jj directory
+// of the repository, in the same format as the views of the config
+type SavedViews struct {
+	mutex sync.Mutex
+	file  string
+}
+
+// NewSavedViews returns the views saved in the repository at root
+func NewSavedViews(root string) *SavedViews {
+	return NewSavedViewsFile(filepath.Join(root, ".jj", "jjui", "views.toml"))
+}
+
+func NewSavedViewsFile(file string) *SavedViews {
+	return &SavedViews{file: file}
+}
+
+// Load returns the saved views by name, none when there is no file
+func (s *SavedViews) Load() (map[string]ViewConfig, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.load()
+}
+
+func (s *SavedViews) load() (map[string]ViewConfig, error) {
+	var saved viewsFile
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(data), &saved); err != nil {
+		return nil, err
+	}
+	for name, view := range saved.Views {
+		view.Name = name
+		saved.Views[name] = view
+	}
+	return saved.Views, nil
+}
+
+// Save adds the view, replacing the saved view with the same name
+func (s *SavedViews) Save(view ViewConfig) error {
+	return s.update(func(views map[string]ViewConfig) {
+		views[view.Name] = view
+	})
+}
+
+// Delete removes the saved view with the given name
+func (s *SavedViews) Delete(name string) error {
+	return s.update(func(views map[string]ViewConfig) {
+		delete(views, name)
+	})
+}
+
+func (s *SavedViews) update(change func(views map[string]ViewConfig)) error {
+	if s == nil {
+		return errors.New("views can't be saved outside of a repository")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	views, err := s.load()
+	if err != nil {
+		return err
+	}
+	if views == nil {
+		views = make(map[string]ViewConfig)
+	}
+	change(views)
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(viewsFile{Views: views}); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.file, buf.Bytes(), 0644)
+}
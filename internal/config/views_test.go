@@ -0,0 +1,51 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedViews_SaveAndLoad(t *testing.T) {
+	views := NewSavedViewsFile(filepath.Join(t.TempDir(), ".jj", "jjui", "views.toml"))
+
+	saved, err := views.Load()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+
+	preview := true
+	require.NoError(t, views.Save(ViewConfig{Name: "review queue", Revset: "reviewers(me)", Limit: 20, Preview: &preview}))
+	require.NoError(t, views.Save(ViewConfig{Name: "conflicts", Revset: "conflicts()"}))
+
+	saved, err = views.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ViewConfig{
+		"review queue": {Name: "review queue", Revset: "reviewers(me)", Limit: 20, Preview: &preview},
+		"conflicts":    {Name: "conflicts", Revset: "conflicts()"},
+	}, saved)
+
+	require.NoError(t, views.Delete("conflicts"))
+	saved, err = views.Load()
+	require.NoError(t, err)
+	assert.Len(t, saved, 1)
+}
+
+func TestSavedViews_NilIsEmpty(t *testing.T) {
+	var views *SavedViews
+	saved, err := views.Load()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+	assert.Error(t, views.Save(ViewConfig{Name: "all", Revset: "all()"}))
+}
+
+func TestConfig_LoadsViews(t *testing.T) {
+	c := Default()
+	require.NoError(t, c.Load(`
+[views."my stacks"]
+revset = "stack()"
+key = ["1"]
+`))
+	assert.Equal(t, ViewConfig{Revset: "stack()", Key: []string{"1"}}, c.Views["my stacks"])
+}
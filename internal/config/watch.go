@@ -0,0 +1,69 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Watcher polls the modification times of files to tell when they change.
+// Directories are watched through the files directly inside them. Files that
+// do not exist yet are watched for their creation.
+type Watcher struct {
+	paths []string
+	state map[string]time.Time
+}
+
+func NewWatcher(paths ...string) *Watcher {
+	w := &Watcher{paths: paths}
+	w.state = w.snapshot()
+	return w
+}
+
+// WatchedFiles returns the files the configuration of the repository at root
+// is loaded from: config.toml, the themes, the repository config, the plugin
+// directories and the jj config of the repository
+func WatchedFiles(root string) []string {
+	var files []string
+	if configFile := getConfigFilePath(); configFile != "" {
+		files = append(files, configFile, filepath.Join(filepath.Dir(configFile), "themes"))
+	}
+	for _, name := range repoConfigFiles {
+		files = append(files, filepath.Join(root, name))
+	}
+	files = append(files, PluginDirs(root)...)
+	return append(files, filepath.Join(root, ".jj", "repo", "config.toml"))
+}
+
+// Changed reports whether any of the files changed since the last call
+func (w *Watcher) Changed() bool {
+	state := w.snapshot()
+	changed := !maps.Equal(state, w.state)
+	w.state = state
+	return changed
+}
+
+func (w *Watcher) snapshot() map[string]time.Time {
+	state := make(map[string]time.Time)
+	for _, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		state[path] = info.ModTime()
+		if !info.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil {
+				state[filepath.Join(path, entry.Name())] = info.ModTime()
+			}
+		}
+	}
+	return state
+}
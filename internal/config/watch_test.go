@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Changed(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.toml")
+	themes := filepath.Join(dir, "themes")
+	require.NoError(t, os.Mkdir(themes, 0755))
+
+	w := NewWatcher(file, themes)
+	assert.False(t, w.Changed())
+
+	require.NoError(t, os.WriteFile(file, []byte("a"), 0644))
+	assert.True(t, w.Changed())
+	assert.False(t, w.Changed())
+
+	theme := filepath.Join(themes, "mine.toml")
+	require.NoError(t, os.WriteFile(theme, []byte("a"), 0644))
+	assert.True(t, w.Changed())
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(theme, later, later))
+	assert.True(t, w.Changed())
+
+	require.NoError(t, os.Remove(file))
+	assert.True(t, w.Changed())
+}
@@ -0,0 +1,37 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Command runs a user provided program that prints the pull requests as a JSON
+// array of PullRequest objects, e.g.
+//
+//	[{"bookmark": "feature", "number": 12, "state": "open", "review_decision": "approved", "checks": "passing"}]
+//
+// The bookmarks to look up are appended to the arguments.
+type Command struct {
+	Location string
+	Args     []string
+}
+
+func (c *Command) PullRequests(ctx context.Context, bookmarks []string) (map[string]PullRequest, error) {
+	if len(c.Args) == 0 {
+		return nil, errors.New("no forge command configured")
+	}
+	if len(bookmarks) == 0 {
+		return map[string]PullRequest{}, nil
+	}
+	args := append(append([]string(nil), c.Args[1:]...), bookmarks...)
+	output, err := runProgram(ctx, c.Location, c.Args[0], args...)
+	if err != nil {
+		return nil, err
+	}
+	var prs []PullRequest
+	if err := json.Unmarshal(output, &prs); err != nil {
+		return nil, err
+	}
+	return filter(prs, bookmarks), nil
+}
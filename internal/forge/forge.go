@@ -0,0 +1,118 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/idursun/jjui/internal/config"
+)
+
+type State string
+
+const (
+	StateOpen   State = "open"
+	StateDraft  State = "draft"
+	StateMerged State = "merged"
+	StateClosed State = "closed"
+)
+
+type ReviewDecision string
+
+const (
+	ReviewNone             ReviewDecision = ""
+	ReviewRequired         ReviewDecision = "review_required"
+	ReviewApproved         ReviewDecision = "approved"
+	ReviewChangesRequested ReviewDecision = "changes_requested"
+)
+
+type CheckStatus string
+
+const (
+	ChecksNone    CheckStatus = ""
+	ChecksPending CheckStatus = "pending"
+	ChecksPassing CheckStatus = "passing"
+	ChecksFailing CheckStatus = "failing"
+)
+
+// PullRequest is the status of the pull request opened for a bookmark
+type PullRequest struct {
+	Bookmark       string         `json:"bookmark"`
+	Number         int            `json:"number"`
+	State          State          `json:"state"`
+	ReviewDecision ReviewDecision `json:"review_decision"`
+	Checks         CheckStatus    `json:"checks"`
+	URL            string         `json:"url"`
+}
+
+// Provider fetches pull request statuses from a forge
+type Provider interface {
+	// PullRequests returns the pull requests of the given bookmarks keyed by
+	// bookmark name. Bookmarks without a pull request are left out.
+	PullRequests(ctx context.Context, bookmarks []string) (map[string]PullRequest, error)
+}
+
+// Submitter is implemented by providers that can open and update pull requests
+type Submitter interface {
+	Provider
+	CreatePullRequest(ctx context.Context, head string, base string, title string, body string) (PullRequest, error)
+	UpdatePullRequest(ctx context.Context, number int, base string, body string) error
+	PullRequestBody(ctx context.Context, number int) (string, error)
+}
+
+// IsOpen reports whether the pull request can still be updated
+func (pr PullRequest) IsOpen() bool {
+	return pr.State == StateOpen || pr.State == StateDraft
+}
+
+// runProgram runs a program in dir and returns its standard output
+var runProgram = func(ctx context.Context, dir string, program string, args ...string) ([]byte, error) {
+	c := exec.CommandContext(ctx, program, args...)
+	c.Dir = dir
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	output, err := c.Output()
+	if err != nil && stderr.Len() > 0 {
+		return nil, errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return output, err
+}
+
+// New returns the provider configured in the forge section of the config, or nil
+// when the forge integration is disabled.
+func New(cfg config.ForgeConfig, location string) (Provider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "github":
+		return &GitHub{Location: location, Limit: cfg.Limit}, nil
+	case "command":
+		if len(cfg.Command) == 0 {
+			return nil, errors.New("forge.command must be set when forge.provider is \"command\"")
+		}
+		return &Command{Location: location, Args: cfg.Command}, nil
+	default:
+		return nil, fmt.Errorf("invalid value for 'forge.provider': %q (expected one of: github, command)", cfg.Provider)
+	}
+}
+
+// filter keeps the first pull request of every given bookmark
+func filter(prs []PullRequest, bookmarks []string) map[string]PullRequest {
+	wanted := make(map[string]bool, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		wanted[bookmark] = true
+	}
+	result := make(map[string]PullRequest)
+	for _, pr := range prs {
+		if !wanted[pr.Bookmark] {
+			continue
+		}
+		if _, ok := result[pr.Bookmark]; !ok {
+			result[pr.Bookmark] = pr
+		}
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeProgram(t *testing.T, output string) *[]string {
+	var called []string
+	previous := runProgram
+	runProgram = func(_ context.Context, _ string, program string, args ...string) ([]byte, error) {
+		called = append([]string{program}, args...)
+		return []byte(output), nil
+	}
+	t.Cleanup(func() { runProgram = previous })
+	return &called
+}
+
+func TestNew(t *testing.T) {
+	provider, err := New(config.ForgeConfig{}, ".")
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = New(config.ForgeConfig{Provider: "github"}, ".")
+	assert.NoError(t, err)
+	assert.IsType(t, &GitHub{}, provider)
+
+	_, err = New(config.ForgeConfig{Provider: "command"}, ".")
+	assert.Error(t, err)
+
+	_, err = New(config.ForgeConfig{Provider: "gitea"}, ".")
+	assert.Error(t, err)
+}
+
+// fakeGitHubStates answers `gh pr list` with the output given for the state it
+// lists and returns the states listed
+func fakeGitHubStates(t *testing.T, outputs map[string]string) *[]string {
+	var states []string
+	previous := runProgram
+	runProgram = func(_ context.Context, _ string, _ string, args ...string) ([]byte, error) {
+		state := args[slices.Index(args, "--state")+1]
+		states = append(states, state)
+		return []byte(outputs[state]), nil
+	}
+	t.Cleanup(func() { runProgram = previous })
+	return &states
+}
+
+func TestGitHub_PullRequests_ListsOpenPullRequestsFirst(t *testing.T) {
+	states := fakeGitHubStates(t, map[string]string{
+		"open": `[{"number": 3, "state": "OPEN", "headRefName": "feature"}]`,
+		"all":  `[{"number": 4, "state": "MERGED", "headRefName": "done"}, {"number": 2, "state": "CLOSED", "headRefName": "feature"}]`,
+	})
+
+	prs, err := (&GitHub{}).PullRequests(context.Background(), []string{"feature", "done"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"open", "all"}, *states)
+	assert.Equal(t, map[string]PullRequest{
+		"feature": {Bookmark: "feature", Number: 3, State: StateOpen, Checks: ChecksNone},
+		"done":    {Bookmark: "done", Number: 4, State: StateMerged, Checks: ChecksNone},
+	}, prs)
+
+	*states = nil
+	_, err = (&GitHub{}).PullRequests(context.Background(), []string{"feature"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"open"}, *states, "all the bookmarks have an open pull request")
+}
+
+func TestGitHub_PullRequests(t *testing.T) {
+	called := fakeProgram(t, `[
+  {"number": 3, "state": "OPEN", "isDraft": false, "reviewDecision": "APPROVED", "headRefName": "feature", "url": "https://example.com/3",
+   "statusCheckRollup": [{"__typename": "CheckRun", "status": "COMPLETED", "conclusion": "SUCCESS"}, {"__typename": "StatusContext", "state": "SUCCESS"}]},
+  {"number": 2, "state": "OPEN", "isDraft": true, "reviewDecision": "", "headRefName": "draft",
+   "statusCheckRollup": [{"__typename": "CheckRun", "status": "IN_PROGRESS", "conclusion": ""}]},
+  {"number": 1, "state": "MERGED", "isDraft": false, "reviewDecision": "CHANGES_REQUESTED", "headRefName": "feature",
+   "statusCheckRollup": [{"__typename": "CheckRun", "status": "COMPLETED", "conclusion": "FAILURE"}]},
+  {"number": 0, "state": "CLOSED", "headRefName": "other"}
+]`)
+
+	prs, err := (&GitHub{Limit: 10}).PullRequests(context.Background(), []string{"feature", "draft"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gh", "pr", "list", "--state", "open", "--limit", "10", "--json", "number,state,isDraft,reviewDecision,headRefName,url,statusCheckRollup"}, *called)
+	assert.Equal(t, map[string]PullRequest{
+		"feature": {Bookmark: "feature", Number: 3, State: StateOpen, ReviewDecision: ReviewApproved, Checks: ChecksPassing, URL: "https://example.com/3"},
+		"draft":   {Bookmark: "draft", Number: 2, State: StateDraft, Checks: ChecksPending},
+	}, prs)
+}
+
+func TestRollupChecks(t *testing.T) {
+	assert.Equal(t, ChecksNone, rollupChecks(nil))
+	assert.Equal(t, ChecksFailing, rollupChecks([]ghCheck{
+		{Status: "IN_PROGRESS"},
+		{Status: "COMPLETED", Conclusion: "TIMED_OUT"},
+	}))
+	assert.Equal(t, ChecksPending, rollupChecks([]ghCheck{
+		{Status: "COMPLETED", Conclusion: "SKIPPED"},
+		{State: "PENDING"},
+	}))
+	assert.Equal(t, ChecksPassing, rollupChecks([]ghCheck{
+		{Status: "COMPLETED", Conclusion: "NEUTRAL"},
+		{State: "SUCCESS"},
+	}))
+}
+
+func TestCommand_PullRequests(t *testing.T) {
+	called := fakeProgram(t, `[{"bookmark": "feature", "number": 7, "state": "open", "review_decision": "review_required", "checks": "failing"}, {"bookmark": "other", "number": 8}]`)
+
+	prs, err := (&Command{Args: []string{"forge-status", "--json"}}).PullRequests(context.Background(), []string{"feature"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"forge-status", "--json", "feature"}, *called)
+	assert.Equal(t, map[string]PullRequest{
+		"feature": {Bookmark: "feature", Number: 7, State: StateOpen, ReviewDecision: ReviewRequired, Checks: ChecksFailing},
+	}, prs)
+}
+
+func TestCommand_PullRequests_ReportsErrors(t *testing.T) {
+	previous := runProgram
+	runProgram = func(context.Context, string, string, ...string) ([]byte, error) {
+		return nil, errors.New("boom")
+	}
+	t.Cleanup(func() { runProgram = previous })
+
+	_, err := (&Command{Args: []string{"forge-status"}}).PullRequests(context.Background(), []string{"feature"})
+	assert.EqualError(t, err, "boom")
+}
+
+func TestGitHub_CreatePullRequest(t *testing.T) {
+	called := fakeProgram(t, "Creating pull request for feature into main\n\nhttps://github.com/owner/repo/pull/42\n")
+
+	pr, err := (&GitHub{}).CreatePullRequest(context.Background(), "feature", "main", "Add feature", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gh", "pr", "create", "--head", "feature", "--base", "main", "--title", "Add feature", "--body", ""}, *called)
+	assert.Equal(t, PullRequest{Bookmark: "feature", Number: 42, State: StateOpen, URL: "https://github.com/owner/repo/pull/42"}, pr)
+}
+
+func TestGitHub_UpdatePullRequest(t *testing.T) {
+	called := fakeProgram(t, "")
+
+	err := (&GitHub{}).UpdatePullRequest(context.Background(), 42, "feature", "body")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gh", "pr", "edit", "42", "--base", "feature", "--body", "body"}, *called)
+}
+
+func TestStackTable(t *testing.T) {
+	table := StackTable([]StackEntry{{Number: 1, Title: "bottom"}, {Number: 2, Title: "a | b"}}, 0)
+	assert.Equal(t, "| | Pull request | Title |\n|---|---|---|\n|  | #2 | a \\| b |\n| 👉 | #1 | bottom |\n", table)
+}
+
+func TestWithStackTable(t *testing.T) {
+	body := WithStackTable("Description\n", "table\n")
+	assert.Equal(t, "Description\n\n<!-- jjui stack -->\ntable\n<!-- jjui stack end -->", body)
+	assert.Equal(t, "<!-- jjui stack -->\ntable\n<!-- jjui stack end -->", WithStackTable("", "table\n"))
+
+	updated := WithStackTable(body+"\n\nfooter", "new table\n")
+	assert.Equal(t, "Description\n\n<!-- jjui stack -->\nnew table\n<!-- jjui stack end -->\n\nfooter", updated)
+}
@@ -0,0 +1,160 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const defaultGitHubLimit = 100
+
+// GitHub reads pull requests through the gh CLI
+type GitHub struct {
+	Location string
+	Limit    int
+}
+
+type ghCheck struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	State      string `json:"state"`
+}
+
+type ghPullRequest struct {
+	Number            int       `json:"number"`
+	State             string    `json:"state"`
+	IsDraft           bool      `json:"isDraft"`
+	ReviewDecision    string    `json:"reviewDecision"`
+	HeadRefName       string    `json:"headRefName"`
+	URL               string    `json:"url"`
+	StatusCheckRollup []ghCheck `json:"statusCheckRollup"`
+}
+
+// PullRequests lists the open pull requests first, so that those older than
+// the most recent ones of a busy repository are found, and then the pull
+// requests in any state for the bookmarks that have no open one.
+func (g *GitHub) PullRequests(ctx context.Context, bookmarks []string) (map[string]PullRequest, error) {
+	if len(bookmarks) == 0 {
+		return map[string]PullRequest{}, nil
+	}
+	open, err := g.list(ctx, "open")
+	if err != nil {
+		return nil, err
+	}
+	result := filter(open, bookmarks)
+	var missing []string
+	for _, bookmark := range bookmarks {
+		if _, ok := result[bookmark]; !ok {
+			missing = append(missing, bookmark)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+	all, err := g.list(ctx, "all")
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(result, filter(all, missing))
+	return result, nil
+}
+
+// list returns the most recent pull requests in the given state
+func (g *GitHub) list(ctx context.Context, state string) ([]PullRequest, error) {
+	limit := g.Limit
+	if limit <= 0 {
+		limit = defaultGitHubLimit
+	}
+	output, err := runProgram(ctx, g.Location, "gh", "pr", "list",
+		"--state", state,
+		"--limit", strconv.Itoa(limit),
+		"--json", "number,state,isDraft,reviewDecision,headRefName,url,statusCheckRollup")
+	if err != nil {
+		return nil, err
+	}
+	return parseGitHubPullRequests(output)
+}
+
+func (g *GitHub) CreatePullRequest(ctx context.Context, head string, base string, title string, body string) (PullRequest, error) {
+	output, err := runProgram(ctx, g.Location, "gh", "pr", "create", "--head", head, "--base", base, "--title", title, "--body", body)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	// gh prints the url of the new pull request, e.g. https://github.com/owner/repo/pull/12
+	url := strings.TrimSpace(string(output))
+	if lines := strings.Split(url, "\n"); len(lines) > 1 {
+		url = lines[len(lines)-1]
+	}
+	number, err := strconv.Atoi(path.Base(url))
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("unexpected output from gh pr create: %q", url)
+	}
+	return PullRequest{Bookmark: head, Number: number, State: StateOpen, URL: url}, nil
+}
+
+func (g *GitHub) UpdatePullRequest(ctx context.Context, number int, base string, body string) error {
+	_, err := runProgram(ctx, g.Location, "gh", "pr", "edit", strconv.Itoa(number), "--base", base, "--body", body)
+	return err
+}
+
+func (g *GitHub) PullRequestBody(ctx context.Context, number int) (string, error) {
+	output, err := runProgram(ctx, g.Location, "gh", "pr", "view", strconv.Itoa(number), "--json", "body", "--jq", ".body")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+// parseGitHubPullRequests converts the output of `gh pr list --json`. gh lists
+// the most recent pull requests first.
+func parseGitHubPullRequests(output []byte) ([]PullRequest, error) {
+	var raw []ghPullRequest
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, err
+	}
+	prs := make([]PullRequest, 0, len(raw))
+	for _, r := range raw {
+		pr := PullRequest{
+			Bookmark:       r.HeadRefName,
+			Number:         r.Number,
+			State:          State(strings.ToLower(r.State)),
+			ReviewDecision: ReviewDecision(strings.ToLower(r.ReviewDecision)),
+			Checks:         rollupChecks(r.StatusCheckRollup),
+			URL:            r.URL,
+		}
+		if r.IsDraft && pr.State == StateOpen {
+			pr.State = StateDraft
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// rollupChecks reduces check runs and commit statuses to a single status. A
+// failing check wins over a pending one.
+func rollupChecks(checks []ghCheck) CheckStatus {
+	if len(checks) == 0 {
+		return ChecksNone
+	}
+	status := ChecksPassing
+	for _, check := range checks {
+		// check runs have a status and a conclusion, commit statuses only a state
+		result := check.Conclusion
+		if check.State != "" {
+			result = check.State
+		} else if check.Status != "COMPLETED" {
+			result = "PENDING"
+		}
+		switch result {
+		case "FAILURE", "ERROR", "CANCELLED", "TIMED_OUT", "ACTION_REQUIRED", "STARTUP_FAILURE":
+			return ChecksFailing
+		case "PENDING", "EXPECTED":
+			status = ChecksPending
+		}
+	}
+	return status
+}
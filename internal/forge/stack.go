@@ -0,0 +1,51 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	stackStartMarker = "<!-- jjui stack -->"
+	stackEndMarker   = "<!-- jjui stack end -->"
+)
+
+// StackEntry is a pull request listed in the stack table
+type StackEntry struct {
+	Number int
+	Title  string
+}
+
+// StackTable renders the pull requests of a stack as a markdown table, from the
+// bottom of the stack to the top. The pull request at current is marked.
+func StackTable(entries []StackEntry, current int) string {
+	var b strings.Builder
+	b.WriteString("| | Pull request | Title |\n")
+	b.WriteString("|---|---|---|\n")
+	for i := len(entries) - 1; i >= 0; i-- {
+		marker := ""
+		if i == current {
+			marker = "👉"
+		}
+		title := strings.ReplaceAll(entries[i].Title, "|", "\\|")
+		fmt.Fprintf(&b, "| %s | #%d | %s |\n", marker, entries[i].Number, title)
+	}
+	return b.String()
+}
+
+// WithStackTable replaces the stack table section of a pull request body, or
+// appends one when the body doesn't have it yet. The rest of the body is kept.
+func WithStackTable(body string, table string) string {
+	section := stackStartMarker + "\n" + table + stackEndMarker
+	start := strings.Index(body, stackStartMarker)
+	if start >= 0 {
+		if end := strings.Index(body[start:], stackEndMarker); end >= 0 {
+			return body[:start] + section + body[start+end+len(stackEndMarker):]
+		}
+	}
+	body = strings.TrimRight(body, "\n")
+	if body == "" {
+		return section
+	}
+	return body + "\n\n" + section
+}
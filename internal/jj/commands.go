@@ -1,11 +1,12 @@
 package jj
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 
-	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/mergetool"
 )
 
 const (
@@ -40,7 +41,14 @@ func ConfigListAll() CommandArgs {
 	return []string{"config", "list", "--color", "never", "--include-defaults", "--ignore-working-copy"}
 }
 
-func Log(revset string, limit int, jjTemplate string) CommandArgs {
+// TemplateAliases lists the template aliases of the user and the repository,
+// without the ones jj defines
+func TemplateAliases() CommandArgs {
+	return []string{"config", "list", "--color", "never", "--ignore-working-copy", "template-aliases"}
+}
+
+// Log lists the revisions with the template prefixed by the ids jjui parses
+func Log(revset string, limit int, template string) CommandArgs {
 	args := []string{"log", "--color", "always", "--quiet"}
 	if revset != "" {
 		args = append(args, "-r", revset)
@@ -48,11 +56,6 @@ func Log(revset string, limit int, jjTemplate string) CommandArgs {
 	if limit > 0 {
 		args = append(args, "--limit", strconv.Itoa(limit))
 	}
-	template := config.Current.Revisions.Template
-	// If jjui's template is empty, fall back to jj's templates.log
-	if template == "" {
-		template = jjTemplate
-	}
 	prefix := fmt.Sprintf(
 		"stringify('%s' ++ separate('%s', change_id.shortest(), commit_id.shortest(), divergent))",
 		JJUIPrefix, JJUIPrefix)
@@ -61,6 +64,43 @@ func Log(revset string, limit int, jjTemplate string) CommandArgs {
 	return args
 }
 
+// LogGraph shows the revisions the way jj log does, without the ids jjui
+// parses. The working copy is not snapshotted.
+func LogGraph(revset string, limit int, template string) CommandArgs {
+	args := []string{"log", "--color", "always", "--quiet", "--ignore-working-copy"}
+	if revset != "" {
+		args = append(args, "-r", revset)
+	}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	if template != "" {
+		args = append(args, "-T", template)
+	}
+	return args
+}
+
+// Conflicts lists the change ids and the first lines of the descriptions of the
+// revisions with conflicts, one per line
+func Conflicts() CommandArgs {
+	return []string{"log", "-r", "conflicts()", "--no-graph", "--color", "never", "--quiet", "--ignore-working-copy",
+		"-T", `change_id.shortest(8) ++ " " ++ description.first_line() ++ "\n"`}
+}
+
+// LogNodes lists the revisions with their parents for jjui to lay out the graph
+// itself, see ParseLogNodes
+func LogNodes(revset string, limit int) CommandArgs {
+	args := []string{"log", "--no-graph", "--color", "never", "--quiet"}
+	if revset != "" {
+		args = append(args, "-r", revset)
+	}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	args = append(args, "-T", logNodesTemplate)
+	return args
+}
+
 func New(revisions SelectedRevisions, noEdit bool) CommandArgs {
 	args := []string{"new"}
 	if noEdit {
@@ -177,6 +217,57 @@ func Diff(revision string, fileName string, extraArgs ...string) CommandArgs {
 	return args
 }
 
+// DiffGit returns the uncolored git format diff of a revision, suitable for ParseGitDiff.
+func DiffGit(revision string, files ...string) CommandArgs {
+	args := []string{"diff", "-r", revision, "--git", "--color", "never", "--ignore-working-copy"}
+	for _, file := range files {
+		if file != "" {
+			args = append(args, EscapeFileName(file))
+		}
+	}
+	return args
+}
+
+func FileShow(revision string, fileName string) CommandArgs {
+	return []string{"file", "show", "-r", revision, "--ignore-working-copy", EscapeFileName(fileName)}
+}
+
+// ResolveWithFile resolves the conflicts of fileName at revision by replacing the
+// merge result with the content of source. Conflict markers left in source keep
+// the corresponding conflicts unresolved.
+func ResolveWithFile(revision string, fileName string, source string) CommandArgs {
+	args := []string{"resolve", "-r", revision}
+	args = append(args, mergeToolArgs("jjui-resolve", "merge-args", mergetool.CopyFileArgs(source, "$output"))...)
+	return append(args,
+		"--config", "merge-tools.jjui-resolve.merge-tool-edits-conflict-markers=true",
+		EscapeFileName(fileName),
+	)
+}
+
+// UsesMergeTool reports whether args make jj run jjui as the merge tool. The
+// tool copies files jjui prepared for the command and removed after it.
+func UsesMergeTool(args []string) bool {
+	for i := 1; i < len(args); i++ {
+		if args[i-1] == "--tool" && strings.HasPrefix(args[i], "jjui-") {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeToolArgs returns the arguments that make jj run jjui as the merge tool
+// with the given arguments, see mergetool.Run
+func mergeToolArgs(tool string, argsKey string, toolArgs []string) []string {
+	// JSON strings are valid TOML strings
+	program, _ := json.Marshal(mergetool.Program())
+	encoded, _ := json.Marshal(toolArgs)
+	return []string{
+		"--tool", tool,
+		"--config", "merge-tools." + tool + ".program=" + string(program),
+		"--config", "merge-tools." + tool + "." + argsKey + "=" + string(encoded),
+	}
+}
+
 func Restore(revision string, files []string, interactive bool) CommandArgs {
 	args := []string{"restore", "-c", revision}
 	if interactive {
@@ -337,6 +428,17 @@ func GitPush(flags ...string) CommandArgs {
 	return args
 }
 
+// Stack lists the changes of the revset from the bottom of the stack to the top,
+// see ParseStack
+func Stack(revset string) CommandArgs {
+	return []string{"log", "-r", revset, "--reversed", "--no-graph", "--template", stackTemplate, "--color", "never"}
+}
+
+func TrunkBookmarks() CommandArgs {
+	const template = `remote_bookmarks.map(|b| b.name()).join(",") ++ "\n"`
+	return []string{"log", "-r", "trunk()", "--no-graph", "--template", template, "--color", "never", "--ignore-working-copy"}
+}
+
 func GitRemoteList() CommandArgs {
 	return []string{"git", "remote", "list"}
 }
@@ -464,10 +566,18 @@ func OpShow(operationId string) CommandArgs {
 	return []string{"op", "show", operationId, "--color", "always", "--ignore-working-copy"}
 }
 
+func OpDiff(from string, to string) CommandArgs {
+	return []string{"op", "diff", "--from", from, "--to", to, "--color", "always", "--ignore-working-copy"}
+}
+
 func OpRestore(operationId string) CommandArgs {
 	return []string{"op", "restore", operationId}
 }
 
+func OpAbandon(operationId string) CommandArgs {
+	return []string{"op", "abandon", operationId}
+}
+
 func OpRevert(operationID string) CommandArgs {
 	return []string{"op", "revert", operationID}
 }
@@ -506,10 +616,31 @@ func GetIdsFromRevset(revset string) CommandArgs {
 	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "change_id.shortest() ++ '\n'"}
 }
 
+// GetIdsFromRevsetWithLimit lists at most limit revisions of the revset
+func GetIdsFromRevsetWithLimit(revset string, limit int) CommandArgs {
+	return append(GetIdsFromRevset(revset), "--limit", strconv.Itoa(limit))
+}
+
+// ContainedIn prints a line for every revset, 1 when the revision is in it and
+// 0 otherwise
+func ContainedIn(revision string, revsets []string) CommandArgs {
+	var template []string
+	for _, revset := range revsets {
+		revset = strings.ReplaceAll(revset, "\\", "\\\\")
+		revset = strings.ReplaceAll(revset, "\"", "\\\"")
+		template = append(template, fmt.Sprintf(`if(self.contained_in("%s"), "1", "0") ++ "\n"`, revset))
+	}
+	return []string{"log", "-r", revision, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", strings.Join(template, " ++ ")}
+}
+
 func GetFullIdsFromRevset(revset string) CommandArgs {
 	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "change_id ++ '\n'"}
 }
 
+func RevisionsInfo(revset string) CommandArgs {
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--template", revisionInfoTemplate}
+}
+
 func GetFullCommitIDFromRevision(revision string) CommandArgs {
 	return []string{"log", "-r", revision, "-n", "1", "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "commit_id ++ '\n'"}
 }
@@ -0,0 +1,273 @@
+package jj
+
+import (
+	"strings"
+)
+
+const minConflictMarkerLength = 7
+
+// ConflictSide is one of the sides or bases of a conflict, as labelled by jj.
+type ConflictSide struct {
+	Label string
+	Lines []string
+}
+
+// ConflictRegion is a part of a materialized file. Resolved regions only have
+// Lines; conflicts also carry their sides and bases while Lines keeps the
+// original text including the markers, so that an unresolved conflict can be
+// written back as is. Every line keeps its line ending.
+type ConflictRegion struct {
+	Lines []string
+	Sides []ConflictSide
+	Bases []ConflictSide
+}
+
+func (r ConflictRegion) IsConflict() bool {
+	return len(r.Sides) > 0
+}
+
+type ConflictChoice int
+
+const (
+	ConflictUnresolved ConflictChoice = iota
+	ConflictOurs
+	ConflictTheirs
+	ConflictBase
+	ConflictBoth
+)
+
+func (c ConflictChoice) String() string {
+	switch c {
+	case ConflictOurs:
+		return "ours"
+	case ConflictTheirs:
+		return "theirs"
+	case ConflictBase:
+		return "base"
+	case ConflictBoth:
+		return "both"
+	default:
+		return "unresolved"
+	}
+}
+
+// Resolve returns the lines that replace the conflict for the given choice.
+// Ours is the first side, theirs the last one and both keeps every side in order.
+func (r ConflictRegion) Resolve(choice ConflictChoice) []string {
+	if !r.IsConflict() {
+		return r.Lines
+	}
+	switch choice {
+	case ConflictOurs:
+		return r.Sides[0].Lines
+	case ConflictTheirs:
+		return r.Sides[len(r.Sides)-1].Lines
+	case ConflictBase:
+		if len(r.Bases) > 0 {
+			return r.Bases[0].Lines
+		}
+		return nil
+	case ConflictBoth:
+		var lines []string
+		for _, side := range r.Sides {
+			lines = append(lines, withLineEnding(side.Lines)...)
+		}
+		return lines
+	default:
+		return r.Lines
+	}
+}
+
+// ResolveConflicts joins the regions back into file content. choices holds one
+// entry per conflict, in order; missing entries leave the conflict unresolved.
+func ResolveConflicts(regions []ConflictRegion, choices []ConflictChoice) string {
+	var b strings.Builder
+	conflict := 0
+	for _, region := range regions {
+		choice := ConflictUnresolved
+		if region.IsConflict() {
+			if conflict < len(choices) {
+				choice = choices[conflict]
+			}
+			conflict++
+		}
+		for _, line := range region.Resolve(choice) {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// ParseConflicts splits file content materialized by jj into resolved regions
+// and conflicts. The "diff", "snapshot" and "git" conflict marker styles are
+// understood; a conflict without an end marker is kept as plain text.
+func ParseConflicts(content string) []ConflictRegion {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var regions []ConflictRegion
+	var text []string
+	flushText := func() {
+		if len(text) > 0 {
+			regions = append(regions, ConflictRegion{Lines: text})
+			text = nil
+		}
+	}
+	for i := 0; i < len(lines); {
+		length, ok := conflictMarker(lines[i], '<', 0)
+		if !ok {
+			text = append(text, lines[i])
+			i++
+			continue
+		}
+		region, end, ok := parseConflict(lines, i, length)
+		if !ok {
+			text = append(text, lines[i])
+			i++
+			continue
+		}
+		flushText()
+		regions = append(regions, region)
+		i = end
+	}
+	flushText()
+	return regions
+}
+
+type sectionKind int
+
+const (
+	sectionSide sectionKind = iota
+	sectionBase
+	sectionDiff
+)
+
+// parseConflict parses the conflict starting at lines[start] and returns the
+// index of the line after its end marker.
+func parseConflict(lines []string, start int, length int) (ConflictRegion, int, bool) {
+	region := ConflictRegion{}
+	var (
+		kind     sectionKind
+		label    string
+		body     []string
+		implicit bool
+	)
+	flush := func() {
+		switch kind {
+		case sectionSide:
+			region.Sides = append(region.Sides, ConflictSide{Label: label, Lines: body})
+		case sectionBase:
+			region.Bases = append(region.Bases, ConflictSide{Label: label, Lines: body})
+		case sectionDiff:
+			base, side := splitConflictDiff(body)
+			region.Bases = append(region.Bases, ConflictSide{Label: label, Lines: base})
+			region.Sides = append(region.Sides, ConflictSide{Label: label, Lines: side})
+		}
+		body = nil
+	}
+
+	// git style conflicts start with the first side right after the start marker
+	kind, label, implicit = sectionSide, markerLabel(lines[start], length), true
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		marker, ok := anyConflictMarker(line, length)
+		if !ok {
+			body = append(body, line)
+			continue
+		}
+		if marker == '\\' {
+			// continuation of the diff header, e.g. "\\\\\\\        to: side #1"
+			label = strings.TrimSpace(label + " " + markerLabel(line, length))
+			continue
+		}
+		// jj styles follow the start marker with a section marker
+		if !implicit || len(body) > 0 || marker == '|' || marker == '=' || marker == '>' {
+			flush()
+		}
+		implicit = false
+		switch marker {
+		case '%':
+			kind, label = sectionDiff, markerLabel(line, length)
+		case '+', '=':
+			kind, label = sectionSide, markerLabel(line, length)
+		case '-', '|':
+			kind, label = sectionBase, markerLabel(line, length)
+		case '>':
+			region.Lines = append([]string(nil), lines[start:i+1]...)
+			if len(region.Sides) == 0 {
+				return ConflictRegion{}, 0, false
+			}
+			return region, i + 1, true
+		}
+	}
+	return ConflictRegion{}, 0, false
+}
+
+// splitConflictDiff turns the body of a "%%%%%%%" section into its base and side.
+func splitConflictDiff(body []string) ([]string, []string) {
+	var base, side []string
+	for _, line := range body {
+		if line == "" {
+			continue
+		}
+		content := line[1:]
+		switch line[0] {
+		case '-':
+			base = append(base, content)
+		case '+':
+			side = append(side, content)
+		default:
+			base = append(base, content)
+			side = append(side, content)
+		}
+	}
+	return base, side
+}
+
+// conflictMarker reports whether line is a marker made of ch. With a length of 0
+// any marker of at least minConflictMarkerLength characters matches and its
+// length is returned.
+func conflictMarker(line string, ch byte, length int) (int, bool) {
+	n := 0
+	for n < len(line) && line[n] == ch {
+		n++
+	}
+	if n < minConflictMarkerLength || (length > 0 && n != length) {
+		return 0, false
+	}
+	rest := line[n:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\n' && rest[0] != '\r' {
+		return 0, false
+	}
+	return n, true
+}
+
+func anyConflictMarker(line string, length int) (byte, bool) {
+	if line == "" {
+		return 0, false
+	}
+	switch ch := line[0]; ch {
+	case '%', '\\', '+', '-', '|', '=', '>':
+		if _, ok := conflictMarker(line, ch, length); ok {
+			return ch, true
+		}
+	}
+	return 0, false
+}
+
+func markerLabel(line string, length int) string {
+	return strings.TrimSpace(line[length:])
+}
+
+// withLineEnding makes sure the last line ends with a newline so that the next
+// side starts on its own line.
+func withLineEnding(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	result := append([]string(nil), lines...)
+	result[len(result)-1] += "\n"
+	return result
+}
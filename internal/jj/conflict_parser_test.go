@@ -0,0 +1,158 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ours    string
+		theirs  string
+		base    string
+	}{
+		{
+			name: "diff style",
+			content: `before
+<<<<<<< Conflict 1 of 1
+%%%%%%% Changes from base to side #1
+ apple
+-grape
++grapefruit
++++++++ Contents of side #2
+apple
+GRAPE
+>>>>>>> Conflict 1 of 1 ends
+after
+`,
+			ours:   "apple\ngrapefruit\n",
+			theirs: "apple\nGRAPE\n",
+			base:   "apple\ngrape\n",
+		},
+		{
+			name: "diff style with continued header",
+			content: `before
+<<<<<<< conflict 1 of 1
+%%%%%%% diff from: vpxusssl 38d49363 "base"
+\\\\\\\        to: rtsqusxu 2768b0b9 "left"
+-grape
++grapefruit
++++++++ ysrnknol 7a20f389 "right"
+GRAPE
+>>>>>>> conflict 1 of 1 ends
+after
+`,
+			ours:   "grapefruit\n",
+			theirs: "GRAPE\n",
+			base:   "grape\n",
+		},
+		{
+			name: "snapshot style",
+			content: `before
+<<<<<<< Conflict 1 of 1
++++++++ Contents of side #1
+grapefruit
+------- Contents of base
+grape
++++++++ Contents of side #2
+GRAPE
+>>>>>>> Conflict 1 of 1 ends
+after
+`,
+			ours:   "grapefruit\n",
+			theirs: "GRAPE\n",
+			base:   "grape\n",
+		},
+		{
+			name: "git style",
+			content: `before
+<<<<<<< Side #1 (Conflict 1 of 1)
+grapefruit
+||||||| Base
+grape
+=======
+GRAPE
+>>>>>>> Side #2 (Conflict 1 of 1 ends)
+after
+`,
+			ours:   "grapefruit\n",
+			theirs: "GRAPE\n",
+			base:   "grape\n",
+		},
+		{
+			name: "longer markers",
+			content: `before
+<<<<<<<<<<< Conflict 1 of 1
++++++++++++ Contents of side #1
+<<<<<<< not a marker
+------------ Contents of base
++++++++++++ Contents of side #2
+GRAPE
+>>>>>>>>>>> Conflict 1 of 1 ends
+after
+`,
+			ours:   "<<<<<<< not a marker\n------------ Contents of base\n",
+			theirs: "GRAPE\n",
+			base:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regions := ParseConflicts(tt.content)
+			require.Len(t, regions, 3)
+			assert.Equal(t, []string{"before\n"}, regions[0].Lines)
+			assert.Equal(t, []string{"after\n"}, regions[2].Lines)
+			assert.True(t, regions[1].IsConflict())
+
+			assert.Equal(t, "before\n"+tt.ours+"after\n", ResolveConflicts(regions, []ConflictChoice{ConflictOurs}))
+			assert.Equal(t, "before\n"+tt.theirs+"after\n", ResolveConflicts(regions, []ConflictChoice{ConflictTheirs}))
+			assert.Equal(t, "before\n"+tt.base+"after\n", ResolveConflicts(regions, []ConflictChoice{ConflictBase}))
+			assert.Equal(t, "before\n"+tt.ours+tt.theirs+"after\n", ResolveConflicts(regions, []ConflictChoice{ConflictBoth}))
+			assert.Equal(t, tt.content, ResolveConflicts(regions, nil))
+		})
+	}
+}
+
+func TestParseConflicts_KeepsUnresolvedConflicts(t *testing.T) {
+	content := `<<<<<<< Conflict 1 of 2
++++++++ Contents of side #1
+a
+------- Contents of base
++++++++ Contents of side #2
+b
+>>>>>>> Conflict 1 of 2 ends
+middle
+<<<<<<< Conflict 2 of 2
++++++++ Contents of side #1
+c
+------- Contents of base
++++++++ Contents of side #2
+d
+>>>>>>> Conflict 2 of 2 ends`
+	regions := ParseConflicts(content)
+	require.Len(t, regions, 3)
+
+	resolved := ResolveConflicts(regions, []ConflictChoice{ConflictUnresolved, ConflictTheirs})
+	assert.Equal(t, `<<<<<<< Conflict 1 of 2
++++++++ Contents of side #1
+a
+------- Contents of base
++++++++ Contents of side #2
+b
+>>>>>>> Conflict 1 of 2 ends
+middle
+d
+`, resolved)
+}
+
+func TestParseConflicts_IgnoresUnterminatedConflicts(t *testing.T) {
+	content := "a\n<<<<<<< Conflict 1 of 1\n+++++++ Contents of side #1\nb\n"
+	regions := ParseConflicts(content)
+	require.Len(t, regions, 1)
+	assert.False(t, regions[0].IsConflict())
+	assert.Equal(t, content, ResolveConflicts(regions, nil))
+}
@@ -0,0 +1,286 @@
+package jj
+
+import (
+	"strconv"
+	"strings"
+)
+
+type DiffLineKind int
+
+const (
+	DiffLineContext DiffLineKind = iota
+	DiffLineAdded
+	DiffLineRemoved
+)
+
+// DiffLine is a single line of a hunk. OldNumber and NewNumber are 1-based and
+// zero when the line does not exist on that side.
+type DiffLine struct {
+	Kind      DiffLineKind
+	Content   string
+	OldNumber int
+	NewNumber int
+	// NoNewline is set when the line is followed by "\ No newline at end of file"
+	NoNewline bool
+}
+
+type DiffHunk struct {
+	Header   string
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	Lines    []DiffLine
+}
+
+type FileDiffStatus int
+
+const (
+	FileModified FileDiffStatus = iota
+	FileAdded
+	FileDeleted
+	FileRenamed
+	FileCopied
+)
+
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Status  FileDiffStatus
+	Binary  bool
+	Hunks   []DiffHunk
+}
+
+// Path returns the path the file has after the change, or the old path for deleted files.
+func (f FileDiff) Path() string {
+	if f.Status == FileDeleted || f.NewPath == "" {
+		return f.OldPath
+	}
+	return f.NewPath
+}
+
+// Stats returns the number of added and removed lines in the file.
+func (f FileDiff) Stats() (added int, removed int) {
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case DiffLineAdded:
+				added++
+			case DiffLineRemoved:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+// ParseGitDiff parses the output of `jj diff --git` into files and hunks.
+// Anything that is not part of a git style file diff is ignored.
+func ParseGitDiff(output string) []FileDiff {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *DiffHunk
+	oldLeft, newLeft := 0, 0
+	oldNumber, newNumber := 0, 0
+
+	flushHunk := func() {
+		if file != nil && hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if file != nil {
+			files = append(files, *file)
+		}
+		file = nil
+	}
+
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				hunk.Lines = append(hunk.Lines, DiffLine{Kind: DiffLineAdded, Content: line[1:], NewNumber: newNumber})
+				newNumber++
+				newLeft--
+				continue
+			case strings.HasPrefix(line, "-"):
+				hunk.Lines = append(hunk.Lines, DiffLine{Kind: DiffLineRemoved, Content: line[1:], OldNumber: oldNumber})
+				oldNumber++
+				oldLeft--
+				continue
+			case strings.HasPrefix(line, " "), line == "":
+				content := ""
+				if line != "" {
+					content = line[1:]
+				}
+				hunk.Lines = append(hunk.Lines, DiffLine{Kind: DiffLineContext, Content: content, OldNumber: oldNumber, NewNumber: newNumber})
+				oldNumber++
+				newNumber++
+				oldLeft--
+				newLeft--
+				continue
+			}
+		}
+
+		if strings.HasPrefix(line, `\`) {
+			if hunk != nil && len(hunk.Lines) > 0 {
+				hunk.Lines[len(hunk.Lines)-1].NoNewline = true
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "diff --git ") {
+			flushFile()
+			oldPath, newPath := splitGitDiffPaths(strings.TrimPrefix(line, "diff --git "))
+			file = &FileDiff{OldPath: oldPath, NewPath: newPath}
+			continue
+		}
+
+		if file == nil {
+			continue
+		}
+
+		if strings.HasPrefix(line, "@@ ") {
+			flushHunk()
+			h, ok := parseHunkHeader(line)
+			if !ok {
+				continue
+			}
+			hunk = &h
+			oldLeft, newLeft = h.OldCount, h.NewCount
+			oldNumber, newNumber = h.OldStart, h.NewStart
+			continue
+		}
+
+		if hunk != nil {
+			// the hunk is complete, anything else belongs to the file header
+			flushHunk()
+		}
+
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			file.Status = FileAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.Status = FileDeleted
+		case strings.HasPrefix(line, "rename from "):
+			file.Status = FileRenamed
+			file.OldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			file.Status = FileRenamed
+			file.NewPath = unquotePath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "copy from "):
+			file.Status = FileCopied
+			file.OldPath = unquotePath(strings.TrimPrefix(line, "copy from "))
+		case strings.HasPrefix(line, "copy to "):
+			file.Status = FileCopied
+			file.NewPath = unquotePath(strings.TrimPrefix(line, "copy to "))
+		case strings.HasPrefix(line, "Binary files "):
+			file.Binary = true
+		case strings.HasPrefix(line, "--- "):
+			if path := strings.TrimPrefix(line, "--- "); path != "/dev/null" {
+				file.OldPath = strings.TrimPrefix(unquotePath(path), "a/")
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if path := strings.TrimPrefix(line, "+++ "); path != "/dev/null" {
+				file.NewPath = strings.TrimPrefix(unquotePath(path), "b/")
+			}
+		}
+	}
+	flushFile()
+	return files
+}
+
+// parseHunkHeader parses lines like `@@ -1,3 +1,4 @@ func main() {`
+func parseHunkHeader(line string) (DiffHunk, bool) {
+	rest := strings.TrimPrefix(line, "@@ ")
+	end := strings.Index(rest, " @@")
+	if end < 0 {
+		return DiffHunk{}, false
+	}
+	ranges := strings.Fields(rest[:end])
+	if len(ranges) != 2 || !strings.HasPrefix(ranges[0], "-") || !strings.HasPrefix(ranges[1], "+") {
+		return DiffHunk{}, false
+	}
+	oldStart, oldCount, ok := parseHunkRange(ranges[0][1:])
+	if !ok {
+		return DiffHunk{}, false
+	}
+	newStart, newCount, ok := parseHunkRange(ranges[1][1:])
+	if !ok {
+		return DiffHunk{}, false
+	}
+	return DiffHunk{
+		Header:   line,
+		OldStart: oldStart,
+		OldCount: oldCount,
+		NewStart: newStart,
+		NewCount: newCount,
+	}, true
+}
+
+func parseHunkRange(s string) (int, int, bool) {
+	start, count, found := strings.Cut(s, ",")
+	startValue, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !found {
+		return startValue, 1, true
+	}
+	countValue, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, 0, false
+	}
+	return startValue, countValue, true
+}
+
+// splitGitDiffPaths splits `a/path b/path` into its two paths. The ---/+++ and
+// rename lines take precedence, this is only a best effort for headers without them.
+func splitGitDiffPaths(s string) (string, string) {
+	if strings.HasPrefix(s, `"`) {
+		if oldPath, rest, ok := cutQuoted(s); ok {
+			return strings.TrimPrefix(oldPath, "a/"), strings.TrimPrefix(unquotePath(strings.TrimSpace(rest)), "b/")
+		}
+	}
+	// paths are usually the same, so try splitting in the middle first
+	if len(s)%2 == 1 {
+		mid := len(s) / 2
+		oldPath, newPath := s[:mid], s[mid+1:]
+		if strings.HasPrefix(oldPath, "a/") && strings.HasPrefix(newPath, "b/") && oldPath[2:] == newPath[2:] {
+			return oldPath[2:], newPath[2:]
+		}
+	}
+	if idx := strings.LastIndex(s, " b/"); idx >= 0 {
+		return strings.TrimPrefix(s[:idx], "a/"), s[idx+3:]
+	}
+	return s, s
+}
+
+func cutQuoted(s string) (string, string, bool) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			unquoted, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			return unquoted, s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
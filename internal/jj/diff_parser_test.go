@@ -0,0 +1,107 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleGitDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,4 @@ package main
+ package main
+-func old() {}
++func new() {}
+
+ // end
+\ No newline at end of file
+diff --git a/added.txt b/added.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/added.txt
+@@ -0,0 +1 @@
++--- not a header
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/old name.txt b/new name.txt
+rename from old name.txt
+rename to new name.txt
+diff --git a/image.png b/image.png
+new file mode 100644
+index 0000000..5555555
+Binary files /dev/null and b/image.png differ
+`
+
+func TestParseGitDiff(t *testing.T) {
+	files := ParseGitDiff(sampleGitDiff)
+	assert.Len(t, files, 5)
+
+	main := files[0]
+	assert.Equal(t, "main.go", main.Path())
+	assert.Equal(t, FileModified, main.Status)
+	assert.Len(t, main.Hunks, 1)
+	hunk := main.Hunks[0]
+	assert.Equal(t, 1, hunk.OldStart)
+	assert.Equal(t, 4, hunk.OldCount)
+	assert.Equal(t, 1, hunk.NewStart)
+	assert.Equal(t, 4, hunk.NewCount)
+	assert.Equal(t, []DiffLine{
+		{Kind: DiffLineContext, Content: "package main", OldNumber: 1, NewNumber: 1},
+		{Kind: DiffLineRemoved, Content: "func old() {}", OldNumber: 2},
+		{Kind: DiffLineAdded, Content: "func new() {}", NewNumber: 2},
+		{Kind: DiffLineContext, Content: "", OldNumber: 3, NewNumber: 3},
+		{Kind: DiffLineContext, Content: "// end", OldNumber: 4, NewNumber: 4, NoNewline: true},
+	}, hunk.Lines)
+	added, removed := main.Stats()
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, removed)
+
+	assert.Equal(t, FileAdded, files[1].Status)
+	assert.Equal(t, "added.txt", files[1].Path())
+	assert.Equal(t, "--- not a header", files[1].Hunks[0].Lines[0].Content)
+
+	assert.Equal(t, FileDeleted, files[2].Status)
+	assert.Equal(t, "gone.txt", files[2].Path())
+	assert.Equal(t, DiffLineRemoved, files[2].Hunks[0].Lines[0].Kind)
+
+	assert.Equal(t, FileRenamed, files[3].Status)
+	assert.Equal(t, "old name.txt", files[3].OldPath)
+	assert.Equal(t, "new name.txt", files[3].NewPath)
+	assert.Empty(t, files[3].Hunks)
+
+	assert.True(t, files[4].Binary)
+	assert.Equal(t, "image.png", files[4].Path())
+}
+
+func TestParseGitDiff_IgnoresNonGitOutput(t *testing.T) {
+	assert.Empty(t, ParseGitDiff("Modified regular file main.go:\n   1    1: package main\n"))
+	assert.Empty(t, ParseGitDiff(""))
+}
+
+func TestSplitGitDiffPaths(t *testing.T) {
+	tests := []struct {
+		input   string
+		oldPath string
+		newPath string
+	}{
+		{"a/file.txt b/file.txt", "file.txt", "file.txt"},
+		{"a/with b/space b/with b/space", "with b/space", "with b/space"},
+		{"a/old.txt b/new.txt", "old.txt", "new.txt"},
+		{`"a/tab\there" "b/tab\there"`, "tab\there", "tab\there"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			oldPath, newPath := splitGitDiffPaths(tt.input)
+			assert.Equal(t, tt.oldPath, oldPath)
+			assert.Equal(t, tt.newPath, newPath)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package jj
+
+import (
+	"strings"
+
+	"github.com/idursun/jjui/internal/mergetool"
+)
+
+const selectionTool = "jjui-selection"
+
+// LineSelector reports whether the line at lineIndex of the hunk at hunkIndex is selected.
+type LineSelector func(hunkIndex int, lineIndex int) bool
+
+// ApplySelection rebuilds the file content with only the selected changes applied on
+// top of the old side. content is the full file content on the new side. The second
+// return value is false when the resulting file should not exist, e.g. when none of
+// the lines of an added file are selected.
+func (f FileDiff) ApplySelection(content string, selected LineSelector) (string, bool) {
+	newLines := strings.Split(content, "\n")
+	lastHasNewline := strings.HasSuffix(content, "\n")
+	if lastHasNewline || content == "" {
+		newLines = newLines[:len(newLines)-1]
+	}
+
+	var b strings.Builder
+	pendingNewline := false
+	lines := 0
+	emit := func(text string, newline bool) {
+		if pendingNewline {
+			b.WriteByte('\n')
+		}
+		b.WriteString(text)
+		pendingNewline = newline
+		lines++
+	}
+	next := 1
+	copyUntil := func(end int) {
+		for ; next < end && next <= len(newLines); next++ {
+			emit(newLines[next-1], next < len(newLines) || lastHasNewline)
+		}
+	}
+
+	for hi, hunk := range f.Hunks {
+		start := hunk.NewStart
+		if hunk.NewCount == 0 {
+			// pure deletions point at the line before the removed block
+			start++
+		}
+		copyUntil(start)
+		for li, line := range hunk.Lines {
+			switch line.Kind {
+			case DiffLineContext:
+				emit(line.Content, !line.NoNewline)
+				next++
+			case DiffLineAdded:
+				if selected(hi, li) {
+					emit(line.Content, !line.NoNewline)
+				}
+				next++
+			case DiffLineRemoved:
+				if !selected(hi, li) {
+					emit(line.Content, !line.NoNewline)
+				}
+			}
+		}
+	}
+	copyUntil(len(newLines) + 1)
+	if pendingNewline {
+		b.WriteByte('\n')
+	}
+
+	if lines == 0 && (f.Status == FileAdded || f.Status == FileDeleted) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// SelectionToolArgs returns the arguments that make jj's diff editor step copy the
+// files prepared in dir over the right side and remove the deleted paths, so that
+// `split`, `squash` and `restore` can be driven without an external diff editor.
+// The caller removes dir once the command is done.
+func SelectionToolArgs(dir string, deleted []string) CommandArgs {
+	return mergeToolArgs(selectionTool, "edit-args", mergetool.CopyDirArgs(dir, "$right", deleted...))
+}
@@ -0,0 +1,118 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const selectionDiff = `diff --git a/file.txt b/file.txt
+index 1111111..2222222 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+@@ -5,2 +5,2 @@
+ five
+-six
++seven
+`
+
+const selectionContent = "one\nTWO\nthree\nfour\nfive\nseven\n"
+
+func TestApplySelection(t *testing.T) {
+	file := ParseGitDiff(selectionDiff)[0]
+
+	tests := []struct {
+		name     string
+		selected LineSelector
+		want     string
+	}{
+		{
+			name:     "nothing selected gives the old content",
+			selected: func(int, int) bool { return false },
+			want:     "one\ntwo\nthree\nfour\nfive\nsix\n",
+		},
+		{
+			name:     "everything selected gives the new content",
+			selected: func(int, int) bool { return true },
+			want:     selectionContent,
+		},
+		{
+			name:     "first hunk only",
+			selected: func(hunk int, _ int) bool { return hunk == 0 },
+			want:     "one\nTWO\nthree\nfour\nfive\nsix\n",
+		},
+		{
+			name:     "single added line without its removal",
+			selected: func(hunk int, line int) bool { return hunk == 1 && line == 2 },
+			want:     "one\ntwo\nthree\nfour\nfive\nsix\nseven\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exists := file.ApplySelection(selectionContent, tt.selected)
+			assert.True(t, exists)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplySelection_AddedAndDeletedFiles(t *testing.T) {
+	files := ParseGitDiff(`diff --git a/new.txt b/new.txt
+new file mode 100644
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++a
++b
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1,2 +0,0 @@
+-x
+-y
+`)
+	none := func(int, int) bool { return false }
+	first := func(_ int, line int) bool { return line == 0 }
+
+	_, exists := files[0].ApplySelection("a\nb\n", none)
+	assert.False(t, exists)
+	content, exists := files[0].ApplySelection("a\nb\n", first)
+	assert.True(t, exists)
+	assert.Equal(t, "a\n", content)
+
+	content, exists = files[1].ApplySelection("", none)
+	assert.True(t, exists)
+	assert.Equal(t, "x\ny\n", content)
+	content, _ = files[1].ApplySelection("", first)
+	assert.Equal(t, "y\n", content)
+	_, exists = files[1].ApplySelection("", func(int, int) bool { return true })
+	assert.False(t, exists)
+}
+
+func TestApplySelection_KeepsMissingNewlineAtEndOfFile(t *testing.T) {
+	file := ParseGitDiff(`diff --git a/f b/f
+--- a/f
++++ b/f
+@@ -1 +1 @@
+-old
+\ No newline at end of file
++new
+\ No newline at end of file
+`)[0]
+	content, _ := file.ApplySelection("new", func(int, int) bool { return false })
+	assert.Equal(t, "old", content)
+	content, _ = file.ApplySelection("new", func(int, int) bool { return true })
+	assert.Equal(t, "new", content)
+}
+
+func TestSelectionToolArgs(t *testing.T) {
+	args := SelectionToolArgs("/tmp/selection", []string{"gone.txt"})
+	assert.Equal(t, []string{"--tool", "jjui-selection"}, []string(args[:2]))
+	assert.Contains(t, args[5], `"copy-dir","/tmp/selection","$right","gone.txt"]`)
+}
@@ -0,0 +1,92 @@
+package jj
+
+import (
+	"strings"
+)
+
+// logNodesTemplate prints one line per revision with tab separated fields, in
+// the order read by ParseLogNodes. The description is the last field so a tab
+// in it does not shift the others.
+const logNodesTemplate = `change_id.shortest() ++ "\t" ++ change_id.short(8) ++ "\t" ++ ` +
+	`commit_id.shortest() ++ "\t" ++ commit_id.short(8) ++ "\t" ++ commit_id ++ "\t" ++ ` +
+	`parents.map(|p| p.commit_id()).join(" ") ++ "\t" ++ ` +
+	`if(current_working_copy, "@") ++ if(immutable, "i") ++ if(conflict, "x") ++ ` +
+	`if(divergent, "d") ++ if(hidden, "h") ++ if(empty, "e") ++ if(root, "r") ++ "\t" ++ ` +
+	`local_bookmarks.map(|b| b.name()).join(" ") ++ "\t" ++ author.name() ++ "\t" ++ ` +
+	`committer.timestamp().ago() ++ "\t" ++ description.first_line() ++ "\n"`
+
+const logNodeFields = 11
+
+// LogNode is a revision as printed by LogNodes, with the ids of its parents
+// instead of a rendered graph
+type LogNode struct {
+	ChangeIdPrefix string
+	ChangeId       string
+	CommitIdPrefix string
+	CommitId       string
+	FullCommitId   string
+	Parents        []string
+	IsWorkingCopy  bool
+	Immutable      bool
+	Conflict       bool
+	Divergent      bool
+	Hidden         bool
+	Empty          bool
+	Root           bool
+	Bookmarks      []string
+	Author         string
+	Timestamp      string
+	Description    string
+}
+
+// Commit returns the commit the rest of jjui works with. Like the ids parsed
+// from the rendered log, the change id is the shortest unique prefix and
+// divergent changes are marked with ??.
+func (n LogNode) Commit() *Commit {
+	changeId := n.ChangeIdPrefix
+	switch {
+	case n.Root:
+		changeId = RootChangeId
+	case n.Divergent:
+		changeId += "??"
+	}
+	return &Commit{
+		ChangeId:      changeId,
+		CommitId:      n.CommitIdPrefix,
+		IsWorkingCopy: n.IsWorkingCopy,
+		Hidden:        n.Hidden,
+	}
+}
+
+// ParseLogNodes reads the output of LogNodes. Lines that do not have all the
+// fields are skipped.
+func ParseLogNodes(output string) []LogNode {
+	var nodes []LogNode
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", logNodeFields)
+		if len(fields) != logNodeFields {
+			continue
+		}
+		flags := fields[6]
+		nodes = append(nodes, LogNode{
+			ChangeIdPrefix: fields[0],
+			ChangeId:       fields[1],
+			CommitIdPrefix: fields[2],
+			CommitId:       fields[3],
+			FullCommitId:   fields[4],
+			Parents:        strings.Fields(fields[5]),
+			IsWorkingCopy:  strings.Contains(flags, "@"),
+			Immutable:      strings.Contains(flags, "i"),
+			Conflict:       strings.Contains(flags, "x"),
+			Divergent:      strings.Contains(flags, "d"),
+			Hidden:         strings.Contains(flags, "h"),
+			Empty:          strings.Contains(flags, "e"),
+			Root:           strings.Contains(flags, "r"),
+			Bookmarks:      strings.Fields(fields[7]),
+			Author:         fields[8],
+			Timestamp:      fields[9],
+			Description:    fields[10],
+		})
+	}
+	return nodes
+}
@@ -0,0 +1,40 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLogNodes(t *testing.T) {
+	output := "kx\tkxqyvtmn\t1a\t1a2b3c4d\t1a2b3c4d5e6f\tf00d beef\t@e\tmain dev\tJane\t2 days ago\tfix:\tthe parser\n" +
+		"z\tzzzzzzzz\t0\t00000000\t000000000000\t\tir\t\t\t56 years ago\t\n" +
+		"truncated\tline\n"
+
+	nodes := ParseLogNodes(output)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, LogNode{
+		ChangeIdPrefix: "kx",
+		ChangeId:       "kxqyvtmn",
+		CommitIdPrefix: "1a",
+		CommitId:       "1a2b3c4d",
+		FullCommitId:   "1a2b3c4d5e6f",
+		Parents:        []string{"f00d", "beef"},
+		IsWorkingCopy:  true,
+		Empty:          true,
+		Bookmarks:      []string{"main", "dev"},
+		Author:         "Jane",
+		Timestamp:      "2 days ago",
+		Description:    "fix:\tthe parser",
+	}, nodes[0])
+	assert.True(t, nodes[1].Root)
+	assert.True(t, nodes[1].Immutable)
+	assert.Empty(t, nodes[1].Parents)
+}
+
+func TestLogNode_Commit(t *testing.T) {
+	assert.Equal(t, &Commit{ChangeId: "kx", CommitId: "1a", IsWorkingCopy: true},
+		LogNode{ChangeIdPrefix: "kx", CommitIdPrefix: "1a", IsWorkingCopy: true}.Commit())
+	assert.Equal(t, "kx??", LogNode{ChangeIdPrefix: "kx", Divergent: true}.Commit().ChangeId)
+	assert.True(t, LogNode{ChangeIdPrefix: "z", Root: true}.Commit().IsRoot())
+}
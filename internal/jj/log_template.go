@@ -0,0 +1,76 @@
+package jj
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LogField is a part of a revision that can be turned on and off in the
+// template built by FieldsTemplate
+type LogField string
+
+const (
+	FieldAuthor      LogField = "author"
+	FieldTimestamp   LogField = "timestamp"
+	FieldBookmarks   LogField = "bookmarks"
+	FieldCommitId    LogField = "commit_id"
+	FieldMarkers     LogField = "markers"
+	FieldSignature   LogField = "signature"
+	FieldDescription LogField = "description"
+)
+
+// LogFields are the fields in the order they are shown
+var LogFields = []LogField{
+	FieldAuthor,
+	FieldTimestamp,
+	FieldBookmarks,
+	FieldCommitId,
+	FieldMarkers,
+	FieldSignature,
+	FieldDescription,
+}
+
+// DefaultLogFields are the fields shown by builtin_log_compact
+var DefaultLogFields = []LogField{
+	FieldAuthor,
+	FieldTimestamp,
+	FieldBookmarks,
+	FieldCommitId,
+	FieldMarkers,
+	FieldDescription,
+}
+
+// BuiltinLogTemplates are the log templates jj ships with
+var BuiltinLogTemplates = []string{
+	"builtin_log_compact",
+	"builtin_log_comfortable",
+	"builtin_log_compact_full_description",
+	"builtin_log_detailed",
+	"builtin_log_oneline",
+}
+
+var fieldTemplates = map[LogField]string{
+	FieldAuthor:    "format_short_signature(author)",
+	FieldTimestamp: "format_timestamp(committer.timestamp())",
+	FieldBookmarks: "bookmarks, tags, working_copies",
+	FieldCommitId:  "format_short_commit_id(commit_id)",
+	FieldMarkers:   `if(empty, label("empty", "(empty)")), if(conflict, label("conflict", "conflict"))`,
+	FieldSignature: "if(signature, format_short_cryptographic_signature(signature))",
+}
+
+// FieldsTemplate builds a log template showing the change id and the given
+// fields. The description goes on a line of its own.
+func FieldsTemplate(fields []LogField) string {
+	header := []string{"format_short_change_id_with_hidden_and_divergent_info(self)"}
+	for _, field := range LogFields {
+		if fragment, ok := fieldTemplates[field]; ok && slices.Contains(fields, field) {
+			header = append(header, fragment)
+		}
+	}
+	lines := fmt.Sprintf(`separate(" ", %s) ++ "\n"`, strings.Join(header, ", "))
+	if slices.Contains(fields, FieldDescription) {
+		lines += ` ++ if(description, description.first_line(), label(if(empty, "empty"), description_placeholder)) ++ "\n"`
+	}
+	return fmt.Sprintf(`if(root, format_root_commit(self), label(if(current_working_copy, "working_copy"), %s))`, lines)
+}
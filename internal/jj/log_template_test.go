@@ -0,0 +1,21 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsTemplate_OnlyChangeId(t *testing.T) {
+	assert.Equal(t,
+		`if(root, format_root_commit(self), label(if(current_working_copy, "working_copy"), separate(" ", format_short_change_id_with_hidden_and_divergent_info(self)) ++ "\n"))`,
+		FieldsTemplate(nil))
+}
+
+func TestFieldsTemplate_KeepsOrderOfFields(t *testing.T) {
+	template := FieldsTemplate([]LogField{FieldCommitId, FieldAuthor, FieldDescription})
+
+	assert.Contains(t, template, `format_short_change_id_with_hidden_and_divergent_info(self), format_short_signature(author), format_short_commit_id(commit_id))`)
+	assert.Contains(t, template, "description.first_line()")
+	assert.NotContains(t, template, "bookmarks")
+}
@@ -0,0 +1,115 @@
+package jj
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// OpDiffSummary counts what changed between two operations
+type OpDiffSummary struct {
+	Added     []string
+	Rewritten []string
+	Abandoned []string
+	Bookmarks []string
+}
+
+func (s OpDiffSummary) String() string {
+	parts := []string{
+		fmt.Sprintf("%d added", len(s.Added)),
+		fmt.Sprintf("%d rewritten", len(s.Rewritten)),
+		fmt.Sprintf("%d abandoned", len(s.Abandoned)),
+	}
+	if len(s.Bookmarks) == 0 {
+		parts = append(parts, "no bookmarks moved")
+	} else {
+		parts = append(parts, "bookmarks moved: "+strings.Join(s.Bookmarks, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Changes lists the change ids under the counts, one line for each kind of
+// change that has any
+func (s OpDiffSummary) Changes() string {
+	lines := []string{s.String()}
+	for _, group := range []struct {
+		kind      string
+		changeIds []string
+	}{
+		{"Added", s.Added},
+		{"Rewritten", s.Rewritten},
+		{"Abandoned", s.Abandoned},
+	} {
+		if len(group.changeIds) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", group.kind, strings.Join(group.changeIds, " ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseOpDiffSummary reads the output of `jj op diff`. In the changed commits
+// section every commit of the new operation is listed with a leading "+" and
+// its predecessors with a leading "-", so a change with both was rewritten,
+// one with only "+" was added and one with only "-" was abandoned.
+func ParseOpDiffSummary(output string) OpDiffSummary {
+	type change struct {
+		added, removed bool
+	}
+	var (
+		summary OpDiffSummary
+		order   []string
+		changes = map[string]*change{}
+		section string
+	)
+	for _, line := range strings.Split(ansi.Strip(output), "\n") {
+		if strings.HasPrefix(line, "Changed ") && strings.HasSuffix(line, ":") {
+			section = strings.TrimSuffix(strings.TrimPrefix(line, "Changed "), ":")
+			continue
+		}
+		switch section {
+		case "commits":
+			trimmed := strings.TrimLeftFunc(line, func(r rune) bool {
+				return r != '+' && r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if len(trimmed) < 2 || trimmed[1] != ' ' || (trimmed[0] != '+' && trimmed[0] != '-') {
+				continue
+			}
+			fields := strings.Fields(trimmed[2:])
+			if len(fields) == 0 {
+				continue
+			}
+			changeId, _, _ := strings.Cut(fields[0], "/")
+			c, ok := changes[changeId]
+			if !ok {
+				c = &change{}
+				changes[changeId] = c
+				order = append(order, changeId)
+			}
+			if trimmed[0] == '+' {
+				c.added = true
+			} else {
+				c.removed = true
+			}
+		case "local bookmarks", "remote bookmarks":
+			if line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+				continue
+			}
+			if name, ok := strings.CutSuffix(line, ":"); ok {
+				summary.Bookmarks = append(summary.Bookmarks, name)
+			}
+		}
+	}
+	for _, changeId := range order {
+		switch c := changes[changeId]; {
+		case c.added && c.removed:
+			summary.Rewritten = append(summary.Rewritten, changeId)
+		case c.added:
+			summary.Added = append(summary.Added, changeId)
+		default:
+			summary.Abandoned = append(summary.Abandoned, changeId)
+		}
+	}
+	return summary
+}
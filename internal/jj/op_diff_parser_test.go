@@ -0,0 +1,43 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const opDiffOutput = `From operation: 0f8a7c3b2e1d (2025-01-01 10:00:00) new empty commit
+  To operation: 9c1b2d3e4f5a (2025-01-01 10:05:00) rebase commit
+
+Changed commits:
+○  + kxqpmswn 5e6f7a8b feature
+│  - kxqpmswn/1 1a2b3c4d (hidden) feature
+○  + zzzzmpyr 9a8b7c6d (empty) (no description set)
+×  - rlvkpnrz/0 4d3c2b1a (hidden) abandoned work
+
+Changed local bookmarks:
+feature:
++ kxqpmswn 5e6f7a8b feature
+- kxqpmswn/1 1a2b3c4d (hidden) feature
+
+Changed remote bookmarks:
+feature@origin:
++ tracked kxqpmswn 5e6f7a8b feature
+- untracked (absent)
+`
+
+func TestParseOpDiffSummary(t *testing.T) {
+	summary := ParseOpDiffSummary("\x1b[1m" + opDiffOutput + "\x1b[0m")
+	assert.Equal(t, []string{"zzzzmpyr"}, summary.Added)
+	assert.Equal(t, []string{"kxqpmswn"}, summary.Rewritten)
+	assert.Equal(t, []string{"rlvkpnrz"}, summary.Abandoned)
+	assert.Equal(t, []string{"feature", "feature@origin"}, summary.Bookmarks)
+	assert.Equal(t, "1 added, 1 rewritten, 1 abandoned, bookmarks moved: feature, feature@origin", summary.String())
+	assert.Equal(t, "1 added, 1 rewritten, 1 abandoned, bookmarks moved: feature, feature@origin\nAdded: zzzzmpyr\nRewritten: kxqpmswn\nAbandoned: rlvkpnrz", summary.Changes())
+}
+
+func TestParseOpDiffSummary_NoChanges(t *testing.T) {
+	summary := ParseOpDiffSummary("From operation: a\n  To operation: b\n")
+	assert.Equal(t, "0 added, 0 rewritten, 0 abandoned, no bookmarks moved", summary.String())
+	assert.Equal(t, summary.String(), summary.Changes())
+}
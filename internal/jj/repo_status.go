@@ -0,0 +1,64 @@
+package jj
+
+import (
+	"slices"
+	"strings"
+)
+
+// repoStatusTemplate prints one line per revision with tab separated fields,
+// in the order read by ParseRepoStatus. The bookmarks of the git remote are
+// left out, they only mirror the local ones in colocated repositories.
+const repoStatusTemplate = `if(current_working_copy, "@") ++ if(conflict, "x") ++ if(divergent, "d") ++ "\t" ++ ` +
+	`change_id.shortest(8) ++ "\t" ++ local_bookmarks.map(|b| b.name()).join(" ") ++ "\t" ++ ` +
+	`remote_bookmarks.map(|b| if(b.remote() != "git", b.name())).join(" ") ++ "\t" ++ ` +
+	`description.first_line() ++ "\n"`
+
+const repoStatusFields = 5
+
+// RepoStatus lists the revisions summarised by ParseRepoStatus
+func RepoStatus() CommandArgs {
+	return []string{"log", "--no-graph", "--color", "never", "--quiet", "--ignore-working-copy",
+		"-r", "@ | conflicts() | bookmarks() | mutable()", "-T", repoStatusTemplate}
+}
+
+// RepositoryStatus is the summary of a repository shown in the dashboard
+type RepositoryStatus struct {
+	WorkingCopy            string
+	WorkingCopyDescription string
+	// Conflicts and Divergent are the change ids of the revisions
+	Conflicts []string
+	Divergent []string
+	// UnpushedBookmarks are the local bookmarks that no remote bookmark of the
+	// same name points to
+	UnpushedBookmarks []string
+}
+
+// ParseRepoStatus reads the output of RepoStatus. Lines that do not have all
+// the fields are skipped.
+func ParseRepoStatus(output string) RepositoryStatus {
+	var status RepositoryStatus
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", repoStatusFields)
+		if len(fields) != repoStatusFields {
+			continue
+		}
+		flags, changeId := fields[0], fields[1]
+		if strings.Contains(flags, "@") {
+			status.WorkingCopy = changeId
+			status.WorkingCopyDescription = fields[4]
+		}
+		if strings.Contains(flags, "x") {
+			status.Conflicts = append(status.Conflicts, changeId)
+		}
+		if strings.Contains(flags, "d") && !slices.Contains(status.Divergent, changeId) {
+			status.Divergent = append(status.Divergent, changeId)
+		}
+		remotes := strings.Fields(fields[3])
+		for _, bookmark := range strings.Fields(fields[2]) {
+			if !slices.Contains(remotes, bookmark) && !slices.Contains(status.UnpushedBookmarks, bookmark) {
+				status.UnpushedBookmarks = append(status.UnpushedBookmarks, bookmark)
+			}
+		}
+	}
+	return status
+}
@@ -0,0 +1,28 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepoStatus(t *testing.T) {
+	output := "@\tkxqyvtmn\tfeature\t\twork in progress\n" +
+		"x\tzsuskuln\t\t\tmerge:\tboth sides\n" +
+		"d\tyqosqzyt\tmain\tmain\tdiverged\n" +
+		"d\tyqosqzyt\t\t\tdiverged\n" +
+		"\trlvkpnrz\tdev\tmain\tbehind\n" +
+		"truncated\tline\n"
+
+	assert.Equal(t, RepositoryStatus{
+		WorkingCopy:            "kxqyvtmn",
+		WorkingCopyDescription: "work in progress",
+		Conflicts:              []string{"zsuskuln"},
+		Divergent:              []string{"yqosqzyt"},
+		UnpushedBookmarks:      []string{"feature", "dev"},
+	}, ParseRepoStatus(output))
+}
+
+func TestParseRepoStatus_Empty(t *testing.T) {
+	assert.Equal(t, RepositoryStatus{}, ParseRepoStatus(""))
+}
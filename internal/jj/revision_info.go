@@ -0,0 +1,74 @@
+package jj
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// revisionInfoTemplate prints every revision as a JSON object on a single line.
+// All strings go through escape_json so that descriptions and paths cannot break
+// the output.
+const revisionInfoTemplate = `"{" ++
+  "\"change_id\":" ++ stringify(change_id).escape_json() ++
+  ",\"commit_id\":" ++ stringify(commit_id).escape_json() ++
+  ",\"description\":" ++ description.escape_json() ++
+  ",\"author\":{\"name\":" ++ author.name().escape_json() ++
+    ",\"email\":" ++ stringify(author.email()).escape_json() ++
+    ",\"timestamp\":" ++ stringify(author.timestamp().format("%+")).escape_json() ++ "}" ++
+  ",\"committer\":{\"name\":" ++ committer.name().escape_json() ++
+    ",\"email\":" ++ stringify(committer.email()).escape_json() ++
+    ",\"timestamp\":" ++ stringify(committer.timestamp().format("%+")).escape_json() ++ "}" ++
+  ",\"parents\":[" ++ parents.map(|p| "{\"change_id\":" ++ stringify(p.change_id()).escape_json() ++ ",\"commit_id\":" ++ stringify(p.commit_id()).escape_json() ++ "}").join(",") ++ "]" ++
+  ",\"bookmarks\":[" ++ local_bookmarks.map(|b| stringify(b.name()).escape_json()).join(",") ++ "]" ++
+  ",\"remote_bookmarks\":[" ++ remote_bookmarks.map(|b| stringify(b).escape_json()).join(",") ++ "]" ++
+  ",\"conflict\":" ++ if(conflict, "true", "false") ++
+  ",\"empty\":" ++ if(empty, "true", "false") ++
+  ",\"immutable\":" ++ if(immutable, "true", "false") ++
+  ",\"files\":[" ++ self.diff().files().map(|f| stringify(f.path()).escape_json()).join(",") ++ "]" ++
+"}\n"`
+
+// Signature is the author or the committer of a revision
+type Signature struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ParentInfo identifies a parent of a revision
+type ParentInfo struct {
+	ChangeId string `json:"change_id"`
+	CommitId string `json:"commit_id"`
+}
+
+// RevisionInfo is the structured data of a revision as printed by RevisionsInfo
+type RevisionInfo struct {
+	ChangeId        string       `json:"change_id"`
+	CommitId        string       `json:"commit_id"`
+	Description     string       `json:"description"`
+	Author          Signature    `json:"author"`
+	Committer       Signature    `json:"committer"`
+	Parents         []ParentInfo `json:"parents"`
+	Bookmarks       []string     `json:"bookmarks"`
+	RemoteBookmarks []string     `json:"remote_bookmarks"`
+	Conflict        bool         `json:"conflict"`
+	Empty           bool         `json:"empty"`
+	Immutable       bool         `json:"immutable"`
+	Files           []string     `json:"files"`
+}
+
+// ParseRevisionsInfo parses the output of RevisionsInfo, one revision per line
+func ParseRevisionsInfo(output string) ([]RevisionInfo, error) {
+	var revisions []RevisionInfo
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var info RevisionInfo
+		if err := json.Unmarshal([]byte(line), &info); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, info)
+	}
+	return revisions, nil
+}
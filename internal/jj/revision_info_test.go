@@ -0,0 +1,37 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRevisionsInfo(t *testing.T) {
+	output := `{"change_id":"kxyz","commit_id":"c0ffee","description":"Fix \"quotes\"\n\nbody\n","author":{"name":"A","email":"a@example.com","timestamp":"2024-01-02T03:04:05+00:00"},"committer":{"name":"B","email":"b@example.com","timestamp":"2024-01-03T03:04:05+00:00"},"parents":[{"change_id":"p1","commit_id":"d1"},{"change_id":"p2","commit_id":"d2"}],"bookmarks":["feature"],"remote_bookmarks":["feature@origin"],"conflict":true,"empty":false,"immutable":false,"files":["a.go","dir/b.go"]}
+{"change_id":"zzzz","commit_id":"000000","description":"","author":{"name":"","email":"","timestamp":"1970-01-01T00:00:00+00:00"},"committer":{"name":"","email":"","timestamp":"1970-01-01T00:00:00+00:00"},"parents":[],"bookmarks":[],"remote_bookmarks":[],"conflict":false,"empty":true,"immutable":true,"files":[]}
+`
+	revisions, err := ParseRevisionsInfo(output)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, RevisionInfo{
+		ChangeId:        "kxyz",
+		CommitId:        "c0ffee",
+		Description:     "Fix \"quotes\"\n\nbody\n",
+		Author:          Signature{Name: "A", Email: "a@example.com", Timestamp: "2024-01-02T03:04:05+00:00"},
+		Committer:       Signature{Name: "B", Email: "b@example.com", Timestamp: "2024-01-03T03:04:05+00:00"},
+		Parents:         []ParentInfo{{ChangeId: "p1", CommitId: "d1"}, {ChangeId: "p2", CommitId: "d2"}},
+		Bookmarks:       []string{"feature"},
+		RemoteBookmarks: []string{"feature@origin"},
+		Conflict:        true,
+		Files:           []string{"a.go", "dir/b.go"},
+	}, revisions[0])
+	assert.True(t, revisions[1].Empty)
+	assert.True(t, revisions[1].Immutable)
+	assert.Empty(t, revisions[1].Parents)
+}
+
+func TestParseRevisionsInfo_InvalidOutput(t *testing.T) {
+	_, err := ParseRevisionsInfo("not json")
+	assert.Error(t, err)
+}
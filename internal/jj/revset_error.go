@@ -0,0 +1,67 @@
+package jj
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	revsetErrorPosition = regexp.MustCompile(`-->\s*1:(\d+)`)
+	revsetErrorMarker   = regexp.MustCompile(`^\s*\|\s*(\^-*\^?)\s*$`)
+	revsetErrorSymbol   = regexp.MustCompile("`([^`]+)`")
+)
+
+// RevsetError is the error jj reports for a revset, with the range of runes
+// [Start, End) of the revset it points at. Start is -1 when the error does not
+// point at a part of the revset.
+type RevsetError struct {
+	Message string
+	Start   int
+	End     int
+}
+
+// ParseRevsetError reads the error printed by jj for the revset. Parse errors
+// point at their position with a caret under the revset; for the others the
+// first symbol of the message found in the revset is pointed at.
+func ParseRevsetError(revset string, output string) RevsetError {
+	e := RevsetError{Start: -1}
+	var detail string
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case i == 0:
+			e.Message = strings.TrimPrefix(trimmed, "Error: ")
+		case strings.HasPrefix(trimmed, "= "):
+			detail = strings.TrimPrefix(trimmed, "= ")
+		case e.Start == -1:
+			if match := revsetErrorPosition.FindStringSubmatch(line); match != nil {
+				column, _ := strconv.Atoi(match[1])
+				e.Start, e.End = column-1, column
+			}
+		case e.End == e.Start+1:
+			if match := revsetErrorMarker.FindStringSubmatch(line); match != nil && len(match[1]) > 1 && strings.HasSuffix(match[1], "^") {
+				e.End = e.Start + len(match[1])
+			}
+		}
+	}
+	if detail != "" && !strings.Contains(e.Message, detail) {
+		e.Message += ": " + detail
+	}
+	if e.Start == -1 {
+		for _, match := range revsetErrorSymbol.FindAllStringSubmatch(e.Message, -1) {
+			if index := strings.Index(revset, match[1]); index != -1 {
+				e.Start = len([]rune(revset[:index]))
+				e.End = e.Start + len([]rune(match[1]))
+				break
+			}
+		}
+	}
+	if length := len([]rune(revset)); e.Start > length {
+		e.Start, e.End = length, length
+	} else if e.End > length {
+		e.End = length
+	}
+	return e
+}
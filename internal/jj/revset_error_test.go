@@ -0,0 +1,51 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRevsetError_SyntaxError(t *testing.T) {
+	output := "Error: Failed to parse revset: Syntax error\n" +
+		"Caused by:  --> 1:5\n" +
+		"  |\n" +
+		"1 | foo((\n" +
+		"  |     ^---\n" +
+		"  |\n" +
+		"  = expected <EOI> or <expression>\n"
+
+	assert.Equal(t, RevsetError{
+		Message: "Failed to parse revset: Syntax error: expected <EOI> or <expression>",
+		Start:   4,
+		End:     5,
+	}, ParseRevsetError("foo((", output))
+}
+
+func TestParseRevsetError_Span(t *testing.T) {
+	output := "Error: Failed to parse revset: Function `foo` doesn't exist\n" +
+		"Caused by:  --> 1:5\n" +
+		"  |\n" +
+		"1 | @ | foo()\n" +
+		"  |     ^---^\n" +
+		"  |\n" +
+		"  = Function `foo` doesn't exist\n"
+
+	assert.Equal(t, RevsetError{
+		Message: "Failed to parse revset: Function `foo` doesn't exist",
+		Start:   4,
+		End:     9,
+	}, ParseRevsetError("@ | foo()", output))
+}
+
+func TestParseRevsetError_PointsAtSymbol(t *testing.T) {
+	output := "Error: Revision `nope` doesn't exist\n"
+
+	assert.Equal(t, RevsetError{Message: "Revision `nope` doesn't exist", Start: 4, End: 8},
+		ParseRevsetError("@ | nope", output))
+}
+
+func TestParseRevsetError_WithoutPosition(t *testing.T) {
+	assert.Equal(t, RevsetError{Message: "Something went wrong", Start: -1},
+		ParseRevsetError("@", "Error: Something went wrong"))
+}
@@ -0,0 +1,50 @@
+package jj
+
+import (
+	"strings"
+)
+
+const stackTemplate = `change_id.shortest(8) ++ ";" ++ local_bookmarks.map(|b| b.name()).join(",") ++ ";" ++ description.first_line() ++ "\n"`
+
+// StackChange is a change of a stack of pull requests
+type StackChange struct {
+	ChangeId  string
+	Bookmarks []string
+	Title     string
+}
+
+// Bookmark returns the bookmark the pull request of the change is opened from
+func (c StackChange) Bookmark() string {
+	if len(c.Bookmarks) == 0 {
+		return ""
+	}
+	return c.Bookmarks[0]
+}
+
+func ParseStack(output string) []StackChange {
+	var changes []StackChange
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ";", 3)
+		if len(parts) < 3 || parts[0] == "" {
+			continue
+		}
+		change := StackChange{ChangeId: parts[0], Title: parts[2]}
+		for _, bookmark := range strings.Split(parts[1], ",") {
+			if bookmark != "" {
+				change.Bookmarks = append(change.Bookmarks, bookmark)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// ParseTrunkBookmark returns the name of the first remote bookmark pointing at trunk()
+func ParseTrunkBookmark(output string) string {
+	for _, name := range strings.Split(strings.TrimSpace(output), ",") {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,22 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStack(t *testing.T) {
+	output := "kmnopqrs;feature,feature-v2;Add feature; part 1\nzxywvuts;;\n\n"
+	assert.Equal(t, []StackChange{
+		{ChangeId: "kmnopqrs", Bookmarks: []string{"feature", "feature-v2"}, Title: "Add feature; part 1"},
+		{ChangeId: "zxywvuts", Title: ""},
+	}, ParseStack(output))
+	assert.Equal(t, "feature", ParseStack(output)[0].Bookmark())
+	assert.Equal(t, "", ParseStack(output)[1].Bookmark())
+}
+
+func TestParseTrunkBookmark(t *testing.T) {
+	assert.Equal(t, "main", ParseTrunkBookmark("main,master\n"))
+	assert.Equal(t, "", ParseTrunkBookmark("\n"))
+}
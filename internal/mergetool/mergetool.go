@@ -0,0 +1,105 @@
+// Package mergetool lets jj run jjui as the merge tool that copies the content
+// jjui prepared, so that editing a diff or resolving a conflict without an
+// external tool doesn't depend on a shell or on cp.
+package mergetool
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Flag is the first argument jjui is run with as a merge tool
+const Flag = "--merge-tool"
+
+// Program returns the path of the jjui executable for jj to run
+func Program() string {
+	if path, err := os.Executable(); err == nil {
+		return path
+	}
+	return os.Args[0]
+}
+
+// CopyDirArgs copies the files in src over dst and removes the deleted paths,
+// given relative to dst with forward slashes
+func CopyDirArgs(src string, dst string, deleted ...string) []string {
+	return append([]string{Flag, "copy-dir", src, dst}, deleted...)
+}
+
+// CopyFileArgs replaces dst with src
+func CopyFileArgs(src string, dst string) []string {
+	return []string{Flag, "copy-file", src, dst}
+}
+
+// Run runs the merge tool when jjui is started as one and returns whether it
+// was, with the exit code
+func Run(args []string) (int, bool) {
+	if len(args) == 0 || args[0] != Flag {
+		return 0, false
+	}
+	if err := run(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "jjui merge tool: %v\n", err)
+		return 1, true
+	}
+	return 0, true
+}
+
+func run(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("expected an action, a source and a destination, got %q", args)
+	}
+	action, src, dst := args[0], args[1], args[2]
+	switch action {
+	case "copy-file":
+		return copyFile(src, dst)
+	case "copy-dir":
+		if err := copyDir(src, dst); err != nil {
+			return err
+		}
+		for _, path := range args[3:] {
+			if err := os.Remove(filepath.Join(dst, filepath.FromSlash(path))); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+}
+
+func copyDir(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(path, target)
+	})
+}
+
+// copyFile replaces the content of dst, keeping its permissions when it exists
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,60 @@
+package mergetool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func write(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func read(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRun_NotAMergeTool(t *testing.T) {
+	_, ok := Run([]string{"-r", "@"})
+	assert.False(t, ok)
+}
+
+func TestRun_CopyDir(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	write(t, filepath.Join(src, "dir", "file.txt"), "selected")
+	write(t, filepath.Join(dst, "dir", "file.txt"), "original")
+	write(t, filepath.Join(dst, "dir", "gone.txt"), "original")
+	write(t, filepath.Join(dst, "kept.txt"), "original")
+
+	code, ok := Run(CopyDirArgs(src, dst, "dir/gone.txt"))
+	assert.True(t, ok)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "selected", read(t, filepath.Join(dst, "dir", "file.txt")))
+	assert.Equal(t, "original", read(t, filepath.Join(dst, "kept.txt")))
+	assert.NoFileExists(t, filepath.Join(dst, "dir", "gone.txt"))
+	assert.DirExists(t, src)
+}
+
+func TestRun_CopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "resolved"), filepath.Join(dir, "output")
+	write(t, src, "resolved")
+	write(t, dst, "<<<<<<< conflict")
+
+	code, ok := Run(CopyFileArgs(src, dst))
+	assert.True(t, ok)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "resolved", read(t, dst))
+}
+
+func TestRun_UnknownAction(t *testing.T) {
+	code, ok := Run([]string{Flag, "move", "a", "b"})
+	assert.True(t, ok)
+	assert.Equal(t, 1, code)
+}
@@ -21,6 +21,10 @@ type Row struct {
 	IsAffected bool
 	Indent     int
 	Previous   *Row
+	// Hidden rows are folded away in the revisions view, and Folded is the
+	// number of rows folded under this one
+	Hidden bool
+	Folded int
 }
 
 func NewGraphRow() Row {
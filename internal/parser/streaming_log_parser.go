@@ -45,6 +45,9 @@ func ParseRowsStreaming(reader io.Reader, controlChannel <-chan ControlMsg, batc
 				row = NewGraphRow()
 				if previousRow.Commit != nil {
 					rows = append(rows, previousRow)
+					// only the row right above is used, cutting the chain here
+					// lets the rows dropped by the revisions view be collected
+					previousRow.Previous = nil
 					row.Previous = &previousRow
 				}
 				for j := range changeIDIdx {
@@ -0,0 +1,308 @@
+package scripting
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/idursun/jjui/internal/jj"
+	uicontext "github.com/idursun/jjui/internal/ui/context"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Headless holds the standard streams of a script that runs without the UI
+type Headless struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	reader *bufio.Reader
+}
+
+// RunHeadless runs a script outside the Bubble Tea program. The script gets the
+// same jjui API, with flash messages written to stderr and choose, input and
+// the widgets of jjui.ui reading from stdin. Functions that drive the UI raise
+// an error. The extra arguments are available as jjui.args and as the varargs
+// of the script. A script can return a number to set the exit code.
+func RunHeadless(ctx *uicontext.MainContext, src string, args []string, h Headless) (int, error) {
+	if ctx.SelectedItem == nil {
+		ctx.SelectedItem = workingCopy(ctx)
+	}
+
+	L := lua.NewState()
+	defer L.Close()
+	r := &Runner{ctx: ctx, main: L}
+	registerAPI(L, r)
+	registerHeadlessAPI(L, r, &h)
+
+	argsTable := L.NewTable()
+	luaArgs := make([]lua.LValue, 0, len(args))
+	for _, arg := range args {
+		argsTable.Append(lua.LString(arg))
+		luaArgs = append(luaArgs, lua.LString(arg))
+	}
+	L.GetGlobal("jjui").(*lua.LTable).RawSetString("args", argsTable)
+
+	fn, err := L.LoadString(src)
+	if err != nil {
+		return 1, fmt.Errorf("lua: %w", err)
+	}
+	L.Push(fn)
+	for _, arg := range luaArgs {
+		L.Push(arg)
+	}
+	if err := L.PCall(len(luaArgs), 1, nil); err != nil {
+		return 1, err
+	}
+	ret := L.Get(-1)
+	L.Pop(1)
+	switch ret := ret.(type) {
+	case lua.LNumber:
+		return int(ret), nil
+	case lua.LBool:
+		if !ret {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// workingCopy selects the working copy revision so that the context functions
+// have something to return
+func workingCopy(ctx *uicontext.MainContext) uicontext.SelectedItem {
+	changeId, err := ctx.RunCommandImmediate(jj.GetFullIdsFromRevset("@"))
+	if err != nil {
+		return nil
+	}
+	commitId, err := ctx.RunCommandImmediate(jj.GetFullCommitIDFromRevision("@"))
+	if err != nil {
+		return nil
+	}
+	return uicontext.SelectedRevision{
+		ChangeId: strings.TrimSpace(string(changeId)),
+		CommitId: strings.TrimSpace(string(commitId)),
+	}
+}
+
+// registerHeadlessAPI replaces the functions of the jjui API that need the UI
+func registerHeadlessAPI(L *lua.LState, runner *Runner, h *Headless) {
+	h.reader = bufio.NewReader(h.Stdin)
+	root := L.GetGlobal("jjui").(*lua.LTable)
+	setFunction := func(name string, fn lua.LGFunction) {
+		f := L.NewFunction(fn)
+		root.RawSetString(name, f)
+		L.SetGlobal(name, f)
+	}
+
+	unavailable := func(name string) *lua.LFunction {
+		return L.NewFunction(func(L *lua.LState) int {
+			L.RaiseError("%s is not available when running without the UI", name)
+			return 0
+		})
+	}
+	revisionsTable := root.RawGetString("revisions").(*lua.LTable)
+	for _, name := range []string{"refresh", "navigate", "start_squash", "start_rebase", "open_details", "start_inline_describe", "fold", "unfold_all"} {
+		revisionsTable.RawSetString(name, unavailable("revisions."+name))
+	}
+	revsetTable := root.RawGetString("revset").(*lua.LTable)
+	for _, name := range []string{"set", "reset", "view", "save_view"} {
+		revsetTable.RawSetString(name, unavailable("revset."+name))
+	}
+
+	runJJ := func(L *lua.LState) int {
+		if _, err := runner.ctx.RunCommandImmediate(argsFromLua(L)); err != nil {
+			L.RaiseError("%s", err.Error())
+		}
+		return 0
+	}
+	setFunction("jj_async", runJJ)
+	setFunction("jj_interactive", func(L *lua.LState) int {
+		c := exec.Command("jj", argsFromLua(L)...)
+		c.Dir = runner.ctx.Location
+		c.Stdin, c.Stdout, c.Stderr = h.reader, h.Stdout, h.Stderr
+		if err := c.Run(); err != nil {
+			L.RaiseError("%s", err.Error())
+		}
+		return 0
+	})
+	setFunction("exec_shell", func(L *lua.LState) int {
+		c := exec.Command("sh", "-c", L.CheckString(1))
+		c.Dir = runner.ctx.Location
+		c.Stdin, c.Stdout, c.Stderr = h.reader, h.Stdout, h.Stderr
+		if err := c.Run(); err != nil {
+			L.RaiseError("%s", err.Error())
+		}
+		return 0
+	})
+	setFunction("flash", func(L *lua.LState) int {
+		text, isError := "", false
+		switch v := L.Get(1).(type) {
+		case *lua.LTable:
+			payload := luaTableToMap(v)
+			text = stringVal(payload, "text")
+			isError = boolVal(payload, "error")
+		default:
+			text = L.CheckString(1)
+		}
+		if isError {
+			text = "Error: " + text
+		}
+		fmt.Fprintln(h.Stderr, text)
+		return 0
+	})
+	setFunction("choose", func(L *lua.LState) int {
+		var options []string
+		title := ""
+		if tbl, ok := L.Get(1).(*lua.LTable); ok && L.GetTop() == 1 {
+			if optTbl, ok := tbl.RawGetString("options").(*lua.LTable); ok {
+				options = stringSliceFromTable(optTbl)
+			} else if s, ok := tbl.RawGetString("options").(lua.LString); ok {
+				options = []string{s.String()}
+			}
+			if titleVal := tbl.RawGetString("title"); titleVal != lua.LNil {
+				title = titleVal.String()
+			}
+			if options == nil {
+				options = stringSliceFromTable(tbl)
+			}
+		} else {
+			options = argsFromLua(L)
+		}
+		if title != "" {
+			fmt.Fprintln(h.Stderr, title)
+		}
+		for i, option := range options {
+			fmt.Fprintf(h.Stderr, "%d) %s\n", i+1, option)
+		}
+		fmt.Fprint(h.Stderr, "> ")
+		line, err := h.readLine()
+		if err != nil {
+			L.RaiseError("choose: %s", err.Error())
+		}
+		if line == "" {
+			L.Push(lua.LNil)
+			return 1
+		}
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(options) {
+			L.Push(lua.LString(options[n-1]))
+			return 1
+		}
+		for _, option := range options {
+			if option == line {
+				L.Push(lua.LString(option))
+				return 1
+			}
+		}
+		L.RaiseError("choose: %q is not one of the options", line)
+		return 0
+	})
+	setFunction("input", func(L *lua.LState) int {
+		title, prompt := "", ""
+		if tbl, ok := L.Get(1).(*lua.LTable); ok {
+			if titleVal := tbl.RawGetString("title"); titleVal != lua.LNil {
+				title = titleVal.String()
+			}
+			if promptVal := tbl.RawGetString("prompt"); promptVal != lua.LNil {
+				prompt = promptVal.String()
+			}
+		}
+		if title != "" {
+			fmt.Fprintln(h.Stderr, title)
+		}
+		fmt.Fprint(h.Stderr, prompt)
+		line, err := h.readLine()
+		if err != nil {
+			L.RaiseError("input: %s", err.Error())
+		}
+		L.Push(lua.LString(line))
+		return 1
+	})
+
+	uiTable := root.RawGetString("ui").(*lua.LTable)
+	uiTable.RawSetString("panel", L.NewFunction(func(L *lua.LState) int {
+		if tbl, ok := L.Get(1).(*lua.LTable); ok {
+			payload := luaTableToMap(tbl)
+			if title := stringVal(payload, "title"); title != "" {
+				fmt.Fprintln(h.Stdout, title)
+			}
+			fmt.Fprintln(h.Stdout, stringVal(payload, "text"))
+			return 0
+		}
+		fmt.Fprintln(h.Stdout, L.CheckString(1))
+		return 0
+	}))
+	uiTable.RawSetString("select", L.NewFunction(func(L *lua.LState) int {
+		payload := luaTableToMap(L.CheckTable(1))
+		options := stringSlice(payload, "options")
+		if title := stringVal(payload, "title"); title != "" {
+			fmt.Fprintln(h.Stderr, title)
+		}
+		for i, option := range options {
+			fmt.Fprintf(h.Stderr, "%d) %s\n", i+1, option)
+		}
+		fmt.Fprint(h.Stderr, "> ")
+		line, err := h.readLine()
+		if err != nil {
+			L.RaiseError("select: %s", err.Error())
+		}
+		if strings.TrimSpace(line) == "" {
+			L.Push(lua.LNil)
+			return 1
+		}
+		picked := L.NewTable()
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(options) {
+				L.RaiseError("select: %q is not the number of an option", field)
+			}
+			picked.Append(lua.LString(options[n-1]))
+		}
+		L.Push(picked)
+		return 1
+	}))
+	uiTable.RawSetString("form", L.NewFunction(func(L *lua.LState) int {
+		title, fields := formFromLua(L)
+		if title != "" {
+			fmt.Fprintln(h.Stderr, title)
+		}
+		values := L.NewTable()
+		for _, field := range fields {
+			label := field.Label
+			if label == "" {
+				label = field.Name
+			}
+			if field.Value != "" {
+				fmt.Fprintf(h.Stderr, "%s [%s]: ", label, field.Value)
+			} else {
+				fmt.Fprintf(h.Stderr, "%s: ", label)
+			}
+			line, err := h.readLine()
+			if err != nil {
+				L.RaiseError("form: %s", err.Error())
+			}
+			if line == "" {
+				line = field.Value
+			}
+			values.RawSetString(field.Name, lua.LString(line))
+		}
+		L.Push(values)
+		return 1
+	}))
+}
+
+// readLine reads a line from stdin. Reaching the end of the input before a line
+// is read is an error since the script cannot get an answer.
+func (h *Headless) readLine() (string, error) {
+	line, err := h.reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("no input available on stdin")
+		}
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,107 @@
+package scripting
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	uicontext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runHeadless(t *testing.T, ctx *uicontext.MainContext, script string, stdin string, args ...string) (int, string, error) {
+	var stdout, stderr bytes.Buffer
+	code, err := RunHeadless(ctx, script, args, Headless{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	return code, stderr.String(), err
+}
+
+func TestRunHeadless_SelectsWorkingCopy(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetFullIdsFromRevset("@")).SetOutput([]byte("abcdef\n"))
+	commandRunner.Expect(jj.GetFullCommitIDFromRevision("@")).SetOutput([]byte("123456\n"))
+	commandRunner.Expect(jj.Args("log", "-r", "abcdef")).SetOutput([]byte("log output"))
+	defer commandRunner.Verify()
+
+	code, stderr, err := runHeadless(t, test.NewTestContext(commandRunner), `
+local out = jjui.jj("log", "-r", jjui.context.change_id())
+flash(out .. " " .. context.commit_id())
+flash({text = "boom", error = true})
+`, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "log output 123456\nError: boom\n", stderr)
+}
+
+func TestRunHeadless_ArgumentsAndExitCode(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+
+	code, _, err := runHeadless(t, ctx, `
+local first, second = ...
+if first ~= jjui.args[1] or second ~= "b" then return 2 end
+return 3
+`, "", "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, 3, code)
+
+	code, _, err = runHeadless(t, ctx, `return false`, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, code)
+}
+
+func TestRunHeadless_ChooseAndInputReadStdin(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+
+	code, stderr, err := runHeadless(t, ctx, `
+local picked = choose({title = "Pick", options = {"one", "two"}})
+local typed = input({prompt = "Name: "})
+local named = choose("one", "two")
+flash(picked .. "," .. typed .. "," .. named)
+`, "2\nalice\none\n")
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasSuffix(stderr, "two,alice,one\n"), stderr)
+	assert.Contains(t, stderr, "Pick\n1) one\n2) two\n> ")
+}
+
+func TestRunHeadless_FailsWithoutInput(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+
+	code, _, err := runHeadless(t, ctx, `input({prompt = "Name: "})`, "")
+	assert.Equal(t, 1, code)
+	assert.ErrorContains(t, err, "no input available on stdin")
+}
+
+func TestRunHeadless_UIFunctionsAreUnavailable(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+
+	code, _, err := runHeadless(t, ctx, `revisions.refresh()`, "")
+	assert.Equal(t, 1, code)
+	assert.ErrorContains(t, err, "revisions.refresh is not available when running without the UI")
+
+	for _, name := range []string{"fold", "unfold_all"} {
+		code, _, err = runHeadless(t, ctx, "revisions."+name+"()", "")
+		assert.Equal(t, 1, code)
+		assert.ErrorContains(t, err, "revisions."+name+" is not available when running without the UI")
+	}
+}
+
+func TestRunHeadless_WidgetsReadStdin(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+
+	code, stderr, err := runHeadless(t, ctx, `
+local picked = ui.select({options = {"main", "dev", "feature"}})
+local values = ui.form({title = "Push", fields = {{name = "remote", value = "origin"}, {name = "force", label = "Force"}}})
+flash(table.concat(picked, ",") .. " " .. values.remote .. " " .. values.force)
+`, "1, 3\n\nyes\n")
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stderr, "Push\nremote [origin]: Force: ")
+	assert.True(t, strings.HasSuffix(stderr, "main,feature origin yes\n"), stderr)
+}
@@ -0,0 +1,203 @@
+package scripting
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	uicontext "github.com/idursun/jjui/internal/ui/context"
+	lua "github.com/yuin/gopher-lua"
+)
+
+var (
+	_ uicontext.CommandHooks          = (*Hooks)(nil)
+	_ uicontext.StatusSegmentProvider = (*Hooks)(nil)
+)
+
+// Hooks runs the Lua functions registered with jjui.on around jj commands.
+//
+// Every command fires before_command and before_<name>, then after_<name> and
+// after_command once it completes. The name is the jj subcommand, or the git
+// subcommand for `jj git`, e.g. before_push. A handler receives a table with
+// name, args and, in after hooks, error. A before handler vetoes the command by
+// returning false (optionally followed by a reason) or by raising an error, and
+// amends it by returning a new list of arguments. Messages flashed by handlers
+// are shown with the result of the command.
+//
+// The functions added with jjui.ui.status_segment(name, fn) give the segments
+// of the status line. They are called without arguments whenever the status
+// line is updated and return the text of the segment, nil hides it.
+type Hooks struct {
+	mutex    sync.Mutex
+	state    *lua.LState
+	handlers map[string][]*lua.LFunction
+	segments []segment
+	output   bytes.Buffer
+}
+
+type segment struct {
+	name string
+	fn   *lua.LFunction
+}
+
+func LoadHooks(ctx *uicontext.MainContext, src string) (*Hooks, error) {
+	L := lua.NewState()
+	h := &Hooks{state: L, handlers: make(map[string][]*lua.LFunction)}
+	r := &Runner{ctx: ctx, main: L}
+	registerAPI(L, r)
+	registerHeadlessAPI(L, r, &Headless{Stdin: strings.NewReader(""), Stdout: &h.output, Stderr: &h.output})
+
+	onFn := L.NewFunction(func(L *lua.LState) int {
+		event := L.CheckString(1)
+		fn := L.CheckFunction(2)
+		h.handlers[event] = append(h.handlers[event], fn)
+		return 0
+	})
+	L.GetGlobal("jjui").(*lua.LTable).RawSetString("on", onFn)
+	L.SetGlobal("on", onFn)
+	registerStatusSegment(L, &h.segments)
+
+	if err := L.DoString(src); err != nil {
+		L.Close()
+		return nil, fmt.Errorf("lua: %w", err)
+	}
+	return h, nil
+}
+
+func (h *Hooks) Before(args []string) ([]string, string, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.output.Reset()
+
+	name := commandName(args)
+	for _, event := range []string{"before_command", "before_" + name} {
+		for _, fn := range h.handlers[event] {
+			ret, reason, err := h.call(fn, name, args, nil)
+			if err != nil {
+				return nil, h.messages(), fmt.Errorf("%s hook failed: %w", event, err)
+			}
+			switch ret := ret.(type) {
+			case lua.LBool:
+				if !ret {
+					if reason == lua.LNil {
+						return nil, h.messages(), fmt.Errorf("%s was vetoed by a %s hook", name, event)
+					}
+					return nil, h.messages(), errors.New(reason.String())
+				}
+			case *lua.LTable:
+				args = stringSliceFromTable(ret)
+			}
+		}
+	}
+	return args, h.messages(), nil
+}
+
+func (h *Hooks) After(args []string, commandErr error) string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.output.Reset()
+
+	name := commandName(args)
+	for _, event := range []string{"after_" + name, "after_command"} {
+		for _, fn := range h.handlers[event] {
+			if _, _, err := h.call(fn, name, args, commandErr); err != nil {
+				fmt.Fprintf(&h.output, "%s hook failed: %v\n", event, err)
+			}
+		}
+	}
+	return h.messages()
+}
+
+// StatusSegments returns the texts of the status segments of init.lua
+func (h *Hooks) StatusSegments() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return callSegments(h.state, h.segments)
+}
+
+// registerStatusSegment makes jjui.ui.status_segment add to segments
+func registerStatusSegment(L *lua.LState, segments *[]segment) {
+	L.GetGlobal("jjui").(*lua.LTable).RawGetString("ui").(*lua.LTable).RawSetString("status_segment", L.NewFunction(func(L *lua.LState) int {
+		*segments = append(*segments, segment{name: L.CheckString(1), fn: L.CheckFunction(2)})
+		return 0
+	}))
+}
+
+// callSegments calls the functions of the segments in L and returns their texts.
+// A segment that fails shows its error.
+func callSegments(L *lua.LState, segments []segment) []string {
+	var texts []string
+	for _, s := range segments {
+		if err := L.CallByParam(lua.P{Fn: s.fn, NRet: 1, Protect: true}); err != nil {
+			var apiErr *lua.ApiError
+			if errors.As(err, &apiErr) {
+				err = errors.New(apiErr.Object.String())
+			}
+			texts = append(texts, fmt.Sprintf("%s: %v", s.name, err))
+			continue
+		}
+		if ret := L.Get(-1); ret != lua.LNil {
+			texts = append(texts, lua.LVAsString(ret))
+		}
+		L.Pop(1)
+	}
+	return texts
+}
+
+func (h *Hooks) call(fn *lua.LFunction, name string, args []string, commandErr error) (lua.LValue, lua.LValue, error) {
+	L := h.state
+	event := L.NewTable()
+	event.RawSetString("name", lua.LString(name))
+	argsTable := L.NewTable()
+	for _, arg := range args {
+		argsTable.Append(lua.LString(arg))
+	}
+	event.RawSetString("args", argsTable)
+	if commandErr != nil {
+		event.RawSetString("error", lua.LString(commandErr.Error()))
+	}
+
+	if err := L.CallByParam(lua.P{Fn: fn, NRet: 2, Protect: true}, event); err != nil {
+		var apiErr *lua.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, nil, errors.New(apiErr.Object.String())
+		}
+		return nil, nil, err
+	}
+	ret, reason := L.Get(-2), L.Get(-1)
+	L.Pop(2)
+	return ret, reason, nil
+}
+
+func (h *Hooks) messages() string {
+	return strings.TrimSpace(h.output.String())
+}
+
+// Close releases the Lua state of the hooks
+func (h *Hooks) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.state.Close()
+}
+
+// commandName returns the name used in the hook events of a jj command
+func commandName(args []string) string {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+		if len(positional) == 2 {
+			break
+		}
+	}
+	if len(positional) == 0 {
+		return ""
+	}
+	if positional[0] == "git" && len(positional) > 1 {
+		return positional[1]
+	}
+	return positional[0]
+}
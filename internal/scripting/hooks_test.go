@@ -0,0 +1,111 @@
+package scripting
+
+import (
+	"errors"
+	"testing"
+
+	uicontext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadHooks(t *testing.T, src string) *Hooks {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+	hooks, err := LoadHooks(ctx, src)
+	require.NoError(t, err)
+	t.Cleanup(hooks.Close)
+	return hooks
+}
+
+func TestHooks_BeforeVetoes(t *testing.T) {
+	hooks := loadHooks(t, `
+jjui.on("before_push", function(event)
+  flash("checking " .. event.name)
+  if event.args[3] == "wip" then
+    return false, "refusing to push a WIP change"
+  end
+end)
+`)
+
+	args, messages, err := hooks.Before([]string{"git", "push", "wip"})
+	assert.Nil(t, args)
+	assert.Equal(t, "checking push", messages)
+	assert.EqualError(t, err, "refusing to push a WIP change")
+
+	args, _, err = hooks.Before([]string{"git", "push", "feature"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git", "push", "feature"}, args)
+}
+
+func TestHooks_BeforeVetoesOnError(t *testing.T) {
+	hooks := loadHooks(t, `on("before_command", function() error("no way", 0) end)`)
+
+	_, _, err := hooks.Before([]string{"new"})
+	assert.EqualError(t, err, "before_command hook failed: no way")
+}
+
+func TestHooks_BeforeAmendsArgs(t *testing.T) {
+	hooks := loadHooks(t, `
+jjui.on("before_command", function(event)
+  local args = event.args
+  table.insert(args, "--quiet")
+  return args
+end)
+jjui.on("before_rebase", function(event)
+  return false
+end)
+`)
+
+	args, _, err := hooks.Before([]string{"new", "-r", "@"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "-r", "@", "--quiet"}, args)
+
+	_, _, err = hooks.Before([]string{"rebase", "-r", "@"})
+	assert.EqualError(t, err, "rebase was vetoed by a before_rebase hook")
+}
+
+func TestHooks_After(t *testing.T) {
+	hooks := loadHooks(t, `
+jjui.on("after_squash", function(event)
+  if event.error then
+    flash({text = "squash failed: " .. event.error, error = true})
+  else
+    flash("squashed " .. context.change_id())
+  end
+end)
+`)
+
+	assert.Equal(t, "squashed abc", hooks.After([]string{"squash"}, nil))
+	assert.Equal(t, "Error: squash failed: conflict", hooks.After([]string{"squash"}, errors.New("conflict")))
+	assert.Equal(t, "", hooks.After([]string{"new"}, nil))
+}
+
+func TestLoadHooks_ReportsErrors(t *testing.T) {
+	_, err := LoadHooks(&uicontext.MainContext{}, `jjui.on(`)
+	assert.Error(t, err)
+}
+
+func TestCommandName(t *testing.T) {
+	assert.Equal(t, "push", commandName([]string{"git", "push", "--bookmark", "main"}))
+	assert.Equal(t, "rebase", commandName([]string{"rebase", "-r", "@"}))
+	assert.Equal(t, "", commandName(nil))
+}
+
+func TestHooks_StatusSegments(t *testing.T) {
+	hooks := loadHooks(t, `
+local count = 0
+jjui.ui.status_segment("counter", function()
+  count = count + 1
+  return "count " .. count
+end)
+ui.status_segment("hidden", function() return nil end)
+ui.status_segment("broken", function() error("no clock") end)
+`)
+
+	segments := hooks.StatusSegments()
+	require.Len(t, segments, 2)
+	assert.Equal(t, "count 1", segments[0])
+	assert.Contains(t, segments[1], "broken: ")
+	assert.Contains(t, segments[1], "no clock")
+	assert.Equal(t, "count 2", hooks.StatusSegments()[0])
+}
@@ -3,10 +3,14 @@ package scripting
 import (
 	stdcontext "context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
 	uicontext "github.com/idursun/jjui/internal/ui/context"
@@ -15,6 +19,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/revisions"
 	"github.com/idursun/jjui/internal/ui/revset"
+	"github.com/idursun/jjui/internal/ui/widgets"
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -158,6 +163,28 @@ func registerAPI(L *lua.LState, runner *Runner) {
 		L.Push(tbl)
 		return 1
 	}))
+	revisionsTable.RawSetString("get", L.NewFunction(func(L *lua.LState) int {
+		revset := L.CheckString(1)
+		out, err := runner.ctx.RunCommandImmediate(jj.RevisionsInfo(revset))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		infos, err := jj.ParseRevisionsInfo(string(out))
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		tbl := L.NewTable()
+		for _, info := range infos {
+			tbl.Append(revisionInfoToLua(L, info))
+		}
+		L.Push(tbl)
+		L.Push(lua.LNil)
+		return 2
+	}))
 	revisionsTable.RawSetString("refresh", L.NewFunction(func(L *lua.LState) int {
 		payload := payloadFromTop(L)
 		intent := intents.Refresh{
@@ -188,6 +215,18 @@ func registerAPI(L *lua.LState, runner *Runner) {
 		}
 		return yieldStep(L, step{cmd: revisions.RevisionsCmd(intent)})
 	}))
+	revisionsTable.RawSetString("fold", L.NewFunction(func(L *lua.LState) int {
+		payload := payloadFromTop(L)
+		intent := intents.Fold{
+			Target:   parseFoldTarget(stringVal(payload, "target")),
+			Action:   parseFoldAction(stringVal(payload, "action")),
+			ChangeID: stringVal(payload, "to"),
+		}
+		return yieldStep(L, step{cmd: revisions.RevisionsCmd(intent)})
+	}))
+	revisionsTable.RawSetString("unfold_all", L.NewFunction(func(L *lua.LState) int {
+		return yieldStep(L, step{cmd: revisions.RevisionsCmd(intents.UnfoldAll{})})
+	}))
 	revisionsTable.RawSetString("start_squash", L.NewFunction(func(L *lua.LState) int {
 		payload := payloadFromTop(L)
 		intent := intents.StartSquash{
@@ -226,6 +265,49 @@ func registerAPI(L *lua.LState, runner *Runner) {
 		L.Push(lua.LString(runner.ctx.DefaultRevset))
 		return 1
 	}))
+	revsetTable.RawSetString("view", L.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+		return yieldStep(L, step{cmd: intents.Invoke(intents.ApplyView{Name: name})})
+	}))
+	revsetTable.RawSetString("save_view", L.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+		return yieldStep(L, step{cmd: intents.Invoke(intents.SaveView{Name: name})})
+	}))
+	revsetTable.RawSetString("current_view", L.NewFunction(func(L *lua.LState) int {
+		if runner.ctx.View == nil {
+			return 0
+		}
+		L.Push(lua.LString(runner.ctx.View.Name))
+		return 1
+	}))
+	revsetTable.RawSetString("views", L.NewFunction(func(L *lua.LState) int {
+		available, err := runner.ctx.Views()
+		if err != nil {
+			L.Push(lua.LNil)
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		tbl := L.NewTable()
+		for _, name := range slices.Sorted(maps.Keys(available)) {
+			view := available[name]
+			item := L.NewTable()
+			item.RawSetString("name", lua.LString(view.Name))
+			item.RawSetString("revset", lua.LString(view.Revset))
+			if view.Template != "" {
+				item.RawSetString("template", lua.LString(view.Template))
+			}
+			if view.Limit > 0 {
+				item.RawSetString("limit", lua.LNumber(view.Limit))
+			}
+			if view.Preview != nil {
+				item.RawSetString("preview", lua.LBool(*view.Preview))
+			}
+			tbl.Append(item)
+		}
+		L.Push(tbl)
+		L.Push(lua.LNil)
+		return 2
+	}))
 
 	contextTable := L.NewTable()
 	contextTable.RawSetString("change_id", L.NewFunction(func(L *lua.LState) int {
@@ -419,6 +501,45 @@ func registerAPI(L *lua.LState, runner *Runner) {
 		return yieldStep(L, step{cmd: input.ShowWithTitle("", ""), matcher: matchInput})
 	})
 
+	uiTable := L.NewTable()
+	uiTable.RawSetString("panel", L.NewFunction(func(L *lua.LState) int {
+		title, text := "", ""
+		if tbl, ok := L.Get(1).(*lua.LTable); ok {
+			payload := luaTableToMap(tbl)
+			title, text = stringVal(payload, "title"), stringVal(payload, "text")
+		} else {
+			text = L.CheckString(1)
+		}
+		return yieldStep(L, step{cmd: widgets.Show("panel", widgets.NewPanel(title, text)), matcher: matchWidget(L)})
+	}))
+	uiTable.RawSetString("select", L.NewFunction(func(L *lua.LState) int {
+		payload := luaTableToMap(L.CheckTable(1))
+		list := widgets.NewList(stringVal(payload, "title"), stringSlice(payload, "options"), stringSlice(payload, "selected"))
+		return yieldStep(L, step{cmd: widgets.Show("select", list), matcher: matchWidget(L)})
+	}))
+	uiTable.RawSetString("form", L.NewFunction(func(L *lua.LState) int {
+		title, fields := formFromLua(L)
+		return yieldStep(L, step{cmd: widgets.Show("form", widgets.NewForm(title, fields)), matcher: matchWidget(L)})
+	}))
+	uiTable.RawSetString("status_segment", L.NewFunction(func(L *lua.LState) int {
+		L.RaiseError("status segments can only be added from init.lua or a plugin")
+		return 0
+	}))
+
+	commandsTable := L.NewTable()
+	registerCommandFn := L.NewFunction(func(L *lua.LState) int {
+		spec := L.CheckTable(1)
+		name, ok := spec.RawGetString("name").(lua.LString)
+		if !ok || name == "" {
+			L.ArgError(1, "the command needs a name")
+		}
+		if _, ok := spec.RawGetString("run").(*lua.LFunction); !ok {
+			L.ArgError(1, fmt.Sprintf("the command %s needs a run function", name))
+		}
+		commandsTable.RawSetString(string(name), spec)
+		return 0
+	})
+
 	// make sure we have a `jjui` namespace
 	root := L.NewTable()
 	root.RawSetString("revisions", revisionsTable)
@@ -433,7 +554,11 @@ func registerAPI(L *lua.LState, runner *Runner) {
 	root.RawSetString("split_lines", splitLinesFn)
 	root.RawSetString("choose", chooseFn)
 	root.RawSetString("input", inputFn)
+	root.RawSetString("ui", uiTable)
+	root.RawSetString("commands", commandsTable)
+	root.RawSetString("register_command", registerCommandFn)
 	L.SetGlobal("jjui", root)
+	addPackagePath(L, config.PluginDirs(runner.ctx.Location))
 
 	// but also expose at the top level for convenience
 	L.SetGlobal("revisions", revisionsTable)
@@ -448,6 +573,47 @@ func registerAPI(L *lua.LState, runner *Runner) {
 	L.SetGlobal("split_lines", splitLinesFn)
 	L.SetGlobal("choose", chooseFn)
 	L.SetGlobal("input", inputFn)
+	L.SetGlobal("ui", uiTable)
+}
+
+func revisionInfoToLua(L *lua.LState, info jj.RevisionInfo) *lua.LTable {
+	signature := func(s jj.Signature) *lua.LTable {
+		tbl := L.NewTable()
+		tbl.RawSetString("name", lua.LString(s.Name))
+		tbl.RawSetString("email", lua.LString(s.Email))
+		tbl.RawSetString("timestamp", lua.LString(s.Timestamp))
+		return tbl
+	}
+	stringList := func(values []string) *lua.LTable {
+		tbl := L.NewTable()
+		for _, v := range values {
+			tbl.Append(lua.LString(v))
+		}
+		return tbl
+	}
+
+	parents := L.NewTable()
+	for _, parent := range info.Parents {
+		p := L.NewTable()
+		p.RawSetString("change_id", lua.LString(parent.ChangeId))
+		p.RawSetString("commit_id", lua.LString(parent.CommitId))
+		parents.Append(p)
+	}
+
+	tbl := L.NewTable()
+	tbl.RawSetString("change_id", lua.LString(info.ChangeId))
+	tbl.RawSetString("commit_id", lua.LString(info.CommitId))
+	tbl.RawSetString("description", lua.LString(info.Description))
+	tbl.RawSetString("author", signature(info.Author))
+	tbl.RawSetString("committer", signature(info.Committer))
+	tbl.RawSetString("parents", parents)
+	tbl.RawSetString("bookmarks", stringList(info.Bookmarks))
+	tbl.RawSetString("remote_bookmarks", stringList(info.RemoteBookmarks))
+	tbl.RawSetString("conflict", lua.LBool(info.Conflict))
+	tbl.RawSetString("empty", lua.LBool(info.Empty))
+	tbl.RawSetString("immutable", lua.LBool(info.Immutable))
+	tbl.RawSetString("files", stringList(info.Files))
+	return tbl
 }
 
 func payloadFromTop(L *lua.LState) map[string]any {
@@ -609,6 +775,26 @@ func parseNavigateTarget(val string) intents.NavigationTarget {
 	}
 }
 
+func parseFoldTarget(val string) intents.FoldTarget {
+	switch strings.ToLower(val) {
+	case "branch":
+		return intents.FoldBranch
+	default:
+		return intents.FoldRun
+	}
+}
+
+func parseFoldAction(val string) intents.FoldAction {
+	switch strings.ToLower(val) {
+	case "collapse", "fold":
+		return intents.FoldCollapse
+	case "expand", "unfold":
+		return intents.FoldExpand
+	default:
+		return intents.FoldToggle
+	}
+}
+
 func matchUpdateRevisionsSuccess(msg tea.Msg) (bool, []lua.LValue) {
 	switch msg.(type) {
 	case common.UpdateRevisionsSuccessMsg, common.UpdateRevisionsFailedMsg:
@@ -660,6 +846,59 @@ func matchChoose(msg tea.Msg) (bool, []lua.LValue) {
 	}
 }
 
+// matchWidget returns the value of a widget: nothing for a panel, a list of
+// the options picked from a list and a table of the values of a form. A
+// cancelled widget returns nil.
+func matchWidget(L *lua.LState) func(tea.Msg) (bool, []lua.LValue) {
+	return func(msg tea.Msg) (bool, []lua.LValue) {
+		done, ok := msg.(widgets.DoneMsg)
+		if !ok {
+			return false, nil
+		}
+		switch value := done.Value.(type) {
+		case []string:
+			tbl := L.NewTable()
+			for _, v := range value {
+				tbl.Append(lua.LString(v))
+			}
+			return true, []lua.LValue{tbl}
+		case map[string]string:
+			tbl := L.NewTable()
+			for k, v := range value {
+				tbl.RawSetString(k, lua.LString(v))
+			}
+			return true, []lua.LValue{tbl}
+		default:
+			return true, []lua.LValue{lua.LNil}
+		}
+	}
+}
+
+// formFromLua reads the title and the fields of the form given as the first
+// argument. A field is a table with a name and optionally a label and a value.
+func formFromLua(L *lua.LState) (string, []widgets.Field) {
+	tbl := L.CheckTable(1)
+	title := lua.LVAsString(tbl.RawGetString("title"))
+	var fields []widgets.Field
+	if fieldsTbl, ok := tbl.RawGetString("fields").(*lua.LTable); ok {
+		fieldsTbl.ForEach(func(_, value lua.LValue) {
+			fieldTbl, ok := value.(*lua.LTable)
+			if !ok {
+				return
+			}
+			fields = append(fields, widgets.Field{
+				Name:  lua.LVAsString(fieldTbl.RawGetString("name")),
+				Label: lua.LVAsString(fieldTbl.RawGetString("label")),
+				Value: lua.LVAsString(fieldTbl.RawGetString("value")),
+			})
+		})
+	}
+	if len(fields) == 0 {
+		L.ArgError(1, "the form needs fields")
+	}
+	return title, fields
+}
+
 func matchInput(msg tea.Msg) (bool, []lua.LValue) {
 	switch msg := msg.(type) {
 	case input.SelectedMsg:
@@ -1,13 +1,20 @@
 package scripting
 
 import (
+	"errors"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	lua "github.com/yuin/gopher-lua"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/idursun/jjui/internal/jj"
 	uicontext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/widgets"
+	"github.com/idursun/jjui/test"
 )
 
 // runScriptAndGetGlobal runs a Lua script and returns the value of a global variable
@@ -261,3 +268,102 @@ func TestContext_AccessViaJjuiNamespace(t *testing.T) {
 	assert.Equal(t, "ns_change", vals[0].String())
 	assert.Equal(t, "ns_commit", vals[1].String())
 }
+
+func TestRevisions_Get(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.RevisionsInfo("@-")).SetOutput([]byte(`{"change_id":"kxyz","commit_id":"c0ffee","description":"Add feature\n","author":{"name":"A","email":"a@example.com","timestamp":"2024-01-02T03:04:05+00:00"},"committer":{"name":"B","email":"b@example.com","timestamp":"2024-01-03T03:04:05+00:00"},"parents":[{"change_id":"p1","commit_id":"d1"}],"bookmarks":["feature"],"remote_bookmarks":[],"conflict":false,"empty":false,"immutable":true,"files":["a.go"]}`))
+	defer commandRunner.Verify()
+
+	results := runScriptAndGetGlobals(t, test.NewTestContext(commandRunner), `
+local revs, err = jjui.revisions.get("@-")
+count = #revs
+rev = revs[1]
+failed = err
+`, "count", "rev", "failed")
+	assert.Equal(t, lua.LNumber(1), results[0])
+	rev := results[1].(*lua.LTable)
+	assert.Equal(t, "kxyz", rev.RawGetString("change_id").String())
+	assert.Equal(t, "Add feature\n", rev.RawGetString("description").String())
+	assert.Equal(t, "A", rev.RawGetString("author").(*lua.LTable).RawGetString("name").String())
+	assert.Equal(t, "d1", rev.RawGetString("parents").(*lua.LTable).RawGetInt(1).(*lua.LTable).RawGetString("commit_id").String())
+	assert.Equal(t, "feature", rev.RawGetString("bookmarks").(*lua.LTable).RawGetInt(1).String())
+	assert.Equal(t, lua.LTrue, rev.RawGetString("immutable"))
+	assert.Equal(t, lua.LFalse, rev.RawGetString("conflict"))
+	assert.Equal(t, "a.go", rev.RawGetString("files").(*lua.LTable).RawGetInt(1).String())
+	assert.Equal(t, lua.LNil, results[2])
+}
+
+func TestRevisions_Get_ReturnsError(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.RevisionsInfo("bad(")).SetError(errors.New("invalid revset"))
+	defer commandRunner.Verify()
+
+	results := runScriptAndGetGlobals(t, test.NewTestContext(commandRunner), `revs, err = jjui.revisions.get("bad(")`, "revs", "err")
+	assert.Equal(t, lua.LNil, results[0])
+	assert.Equal(t, "invalid revset", results[1].String())
+}
+
+func TestUI_SelectAndFormResumeWithTheirValues(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+	runner, cmd, err := RunScript(ctx, `
+local picked = ui.select({title = "Bookmarks", options = {"main", "dev"}})
+local values = jjui.ui.form({fields = {{name = "remote", value = "origin"}}})
+flash(table.concat(picked, ",") .. " " .. values.remote)
+`)
+	require.NoError(t, err)
+
+	var shown widgets.ShowMsg
+	test.SimulateModel(noopModel{}, cmd, func(msg tea.Msg) {
+		if got, ok := msg.(widgets.ShowMsg); ok {
+			shown = got
+		}
+	})
+	assert.Equal(t, "select", shown.Name)
+
+	test.SimulateModel(noopModel{}, runner.HandleMsg(widgets.DoneMsg{Value: []string{"main", "dev"}}), func(msg tea.Msg) {
+		if got, ok := msg.(widgets.ShowMsg); ok {
+			shown = got
+		}
+	})
+	assert.Equal(t, "form", shown.Name)
+
+	var flashed intents.AddMessage
+	test.SimulateModel(noopModel{}, runner.HandleMsg(widgets.DoneMsg{Value: map[string]string{"remote": "upstream"}}), func(msg tea.Msg) {
+		if got, ok := msg.(intents.AddMessage); ok {
+			flashed = got
+		}
+	})
+	assert.Equal(t, "main,dev upstream", flashed.Text)
+	assert.True(t, runner.Done())
+}
+
+func TestUI_CancelledSelectReturnsNil(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+	runner, _, err := RunScript(ctx, `
+if ui.select({options = {"main"}}) == nil then flash("cancelled") end
+`)
+	require.NoError(t, err)
+
+	var flashed intents.AddMessage
+	test.SimulateModel(noopModel{}, runner.HandleMsg(widgets.DoneMsg{}), func(msg tea.Msg) {
+		if got, ok := msg.(intents.AddMessage); ok {
+			flashed = got
+		}
+	})
+	assert.Equal(t, "cancelled", flashed.Text)
+}
+
+func TestUI_StatusSegmentNeedsInitLua(t *testing.T) {
+	ctx := &uicontext.MainContext{SelectedItem: uicontext.SelectedRevision{ChangeId: "abc"}}
+	runner, cmd, err := RunScript(ctx, `ui.status_segment("clock", function() return "now" end)`)
+	require.NoError(t, err)
+	assert.True(t, runner.Done())
+
+	var flashed intents.AddMessage
+	test.SimulateModel(noopModel{}, cmd, func(msg tea.Msg) {
+		if got, ok := msg.(intents.AddMessage); ok {
+			flashed = got
+		}
+	})
+	assert.Contains(t, flashed.Text, "status segments can only be added from init.lua")
+}
@@ -0,0 +1,128 @@
+package scripting
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	uicontext "github.com/idursun/jjui/internal/ui/context"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// addPackagePath lets require find the modules in dirs before the default
+// locations
+func addPackagePath(L *lua.LState, dirs []string) {
+	pkg, ok := L.GetGlobal("package").(*lua.LTable)
+	if !ok {
+		return
+	}
+	var paths []string
+	for _, dir := range dirs {
+		paths = append(paths, filepath.Join(dir, "?.lua"), filepath.Join(dir, "?", "init.lua"))
+	}
+	if current := lua.LVAsString(pkg.RawGetString("path")); current != "" {
+		paths = append(paths, current)
+	}
+	pkg.RawSetString("path", lua.LString(strings.Join(paths, ";")))
+}
+
+var _ uicontext.StatusSegmentProvider = (*pluginSegments)(nil)
+
+// pluginSegments keeps the state of the plugins loaded to call the functions
+// they add with jjui.ui.status_segment
+type pluginSegments struct {
+	mutex    sync.Mutex
+	state    *lua.LState
+	segments []segment
+}
+
+// StatusSegments returns the texts of the segments, none once closed
+func (p *pluginSegments) StatusSegments() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.state == nil {
+		return nil
+	}
+	return callSegments(p.state, p.segments)
+}
+
+// Close releases the Lua state of the plugins
+func (p *pluginSegments) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.state != nil {
+		p.state.Close()
+		p.state = nil
+	}
+}
+
+// LoadPlugins requires the Lua modules and adds the commands they register
+// with jjui.register_command to customCommands, and their leader keys to
+// leader. A command runs by requiring its module again in the state of the
+// script, so the plugins are only kept loaded while they register. Commands
+// of the config are not replaced.
+//
+// A command is a table with a name and a run function, and optionally desc,
+// key, key_sequence and leader, the keys pressed after the leader key.
+//
+// The segments the plugins add with jjui.ui.status_segment are called in the
+// state the plugins were loaded in, which is then kept until the returned
+// provider is closed. The provider is nil when no plugin adds a segment.
+func LoadPlugins(ctx *uicontext.MainContext, modules []string, customCommands map[string]uicontext.CustomCommand, leader uicontext.LeaderMap) (uicontext.StatusSegmentProvider, error) {
+	L := lua.NewState()
+	segments := &pluginSegments{state: L}
+	r := &Runner{ctx: ctx, main: L}
+	registerAPI(L, r)
+	registerHeadlessAPI(L, r, &Headless{Stdin: strings.NewReader(""), Stdout: io.Discard, Stderr: io.Discard})
+	registerStatusSegment(L, &segments.segments)
+
+	commands := L.GetGlobal("jjui").(*lua.LTable).RawGetString("commands").(*lua.LTable)
+	registeredBy := make(map[string]string)
+	for _, module := range modules {
+		if err := L.DoString(fmt.Sprintf("require(%q)", module)); err != nil {
+			L.Close()
+			return nil, fmt.Errorf("%s: %w", module, err)
+		}
+		commands.ForEach(func(name lua.LValue, _ lua.LValue) {
+			if _, ok := registeredBy[name.String()]; !ok {
+				registeredBy[name.String()] = module
+			}
+		})
+	}
+
+	commands.ForEach(func(name lua.LValue, spec lua.LValue) {
+		payload := luaTableToMap(spec.(*lua.LTable))
+		command := uicontext.CustomLuaCommand{
+			CustomCommandBase: uicontext.CustomCommandBase{
+				Name:        name.String(),
+				Desc:        stringVal(payload, "desc"),
+				Key:         keys(payload, "key"),
+				KeySequence: keys(payload, "key_sequence"),
+			},
+			Script: fmt.Sprintf("require(%q)\nreturn jjui.commands[%q].run()", registeredBy[name.String()], name.String()),
+			Plugin: registeredBy[name.String()],
+		}
+		if _, ok := customCommands[command.Name]; ok {
+			return
+		}
+		customCommands[command.Name] = command
+		if keys := stringVal(payload, "leader"); keys != "" {
+			uicontext.AddLeaderCommand(leader, keys, command.Label(), command.Name)
+		}
+	})
+	if len(segments.segments) == 0 {
+		L.Close()
+		return nil, nil
+	}
+	return segments, nil
+}
+
+// keys reads a list of keys that can also be given as a single string
+func keys(payload map[string]any, key string) []string {
+	if s := stringVal(payload, key); s != "" {
+		return []string{s}
+	}
+	return stringSlice(payload, key)
+}
@@ -0,0 +1,132 @@
+package scripting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/idursun/jjui/internal/ui/common"
+	uicontext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+)
+
+// withPlugins writes the plugins to the plugins directory of a temporary
+// config directory
+func withPlugins(t *testing.T, plugins map[string]string) {
+	configDir := t.TempDir()
+	t.Setenv("JJUI_CONFIG_DIR", configDir)
+	for name, src := range plugins {
+		file := filepath.Join(configDir, "plugins", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(src), 0o644))
+	}
+}
+
+func TestRequire_FindsModulesInPluginDirectories(t *testing.T) {
+	withPlugins(t, map[string]string{
+		"team/init.lua":  `return { greeting = require("team.words").hello }`,
+		"team/words.lua": `return { hello = "hello from the team" }`,
+	})
+
+	val := runScriptAndGetGlobal(t, &uicontext.MainContext{}, `result = require("team").greeting`, "result")
+	assert.Equal(t, "hello from the team", val.String())
+}
+
+func TestLoadPlugins_RegistersCommandsAndLeaderKeys(t *testing.T) {
+	withPlugins(t, map[string]string{
+		"stack.lua": `
+jjui.register_command{
+  name = "stack push",
+  desc = "Push the stack",
+  key = "ctrl+g",
+  leader = "sp",
+  run = function() flash("pushed") end,
+}
+`,
+	})
+	ctx := &uicontext.MainContext{}
+	commands := map[string]uicontext.CustomCommand{}
+	leader := uicontext.LeaderMap{}
+
+	segments, err := LoadPlugins(ctx, []string{"stack"}, commands, leader)
+	require.NoError(t, err)
+	assert.Nil(t, segments, "the state isn't kept for commands")
+
+	command, ok := commands["stack push"].(uicontext.CustomLuaCommand)
+	require.True(t, ok)
+	assert.Equal(t, "stack", command.Plugin)
+	assert.Equal(t, []string{"ctrl+g"}, command.Key)
+	assert.Equal(t, "stack push", leader["s"].Nest["p"].Command)
+	assert.Equal(t, "Push the stack", leader["s"].Nest["p"].Bind.Help().Desc)
+
+	msg := command.Prepare(ctx)()
+	script, ok := msg.(common.RunLuaScriptMsg)
+	require.True(t, ok)
+	_, cmd, err := RunScript(ctx, script.Script)
+	require.NoError(t, err)
+	var flashed intents.AddMessage
+	test.SimulateModel(noopModel{}, cmd, func(msg tea.Msg) {
+		if got, ok := msg.(intents.AddMessage); ok {
+			flashed = got
+		}
+	})
+	assert.Equal(t, "pushed", flashed.Text)
+}
+
+func TestLoadPlugins_KeepsCommandsOfConfig(t *testing.T) {
+	withPlugins(t, map[string]string{
+		"stack.lua": `jjui.register_command{ name = "push", run = function() end }`,
+	})
+	commands := map[string]uicontext.CustomCommand{"push": uicontext.CustomRunCommand{Args: []string{"git", "push"}}}
+
+	_, err := LoadPlugins(&uicontext.MainContext{}, []string{"stack"}, commands, uicontext.LeaderMap{})
+	require.NoError(t, err)
+
+	assert.IsType(t, uicontext.CustomRunCommand{}, commands["push"])
+}
+
+func TestLoadPlugins_FailsOnMissingModule(t *testing.T) {
+	withPlugins(t, nil)
+
+	_, err := LoadPlugins(&uicontext.MainContext{}, []string{"missing"}, map[string]uicontext.CustomCommand{}, uicontext.LeaderMap{})
+	assert.ErrorContains(t, err, "missing")
+}
+
+func TestRegisterCommand_NeedsRunFunction(t *testing.T) {
+	withPlugins(t, map[string]string{
+		"broken.lua": `jjui.register_command{ name = "broken" }`,
+	})
+
+	_, err := LoadPlugins(&uicontext.MainContext{}, []string{"broken"}, map[string]uicontext.CustomCommand{}, uicontext.LeaderMap{})
+	assert.ErrorContains(t, err, "needs a run function")
+}
+
+func TestLoadPlugins_AddsStatusSegments(t *testing.T) {
+	withPlugins(t, map[string]string{
+		"ci.lua": `
+local runs = 0
+jjui.ui.status_segment("ci", function()
+  runs = runs + 1
+  return "ci: " .. runs
+end)
+`,
+	})
+
+	segments, err := LoadPlugins(&uicontext.MainContext{}, []string{"ci"}, map[string]uicontext.CustomCommand{}, uicontext.LeaderMap{})
+	require.NoError(t, err)
+	require.NotNil(t, segments)
+	assert.Equal(t, []string{"ci: 1"}, segments.StatusSegments())
+	assert.Equal(t, []string{"ci: 2"}, segments.StatusSegments(), "the state of the plugin is kept")
+
+	segments.(interface{ Close() }).Close()
+	assert.Empty(t, segments.StatusSegments())
+}
+
+type noopModel struct{}
+
+func (noopModel) Update(tea.Msg) tea.Cmd { return nil }
@@ -69,7 +69,18 @@ type (
 	RestoreOperationMsg struct {
 		Operation any
 	}
-	StartAceJumpMsg struct{}
+	StartAceJumpMsg   struct{}
+	ShowHunkPickerMsg struct {
+		Revision *jj.Commit
+		Diff     string
+	}
+	ShowConflictsMsg struct {
+		Revision *jj.Commit
+		Files    []string
+	}
+	ConfigReloadedMsg struct{}
+	// ShowDashboardMsg leaves the repository for the dashboard it was opened from
+	ShowDashboardMsg struct{}
 )
 
 type State int
@@ -84,6 +95,10 @@ func Close() tea.Msg {
 	return CloseViewMsg{}
 }
 
+func ShowDashboard() tea.Msg {
+	return ShowDashboardMsg{}
+}
+
 func CloseApplied() tea.Msg {
 	return CloseViewMsg{Applied: true}
 }
@@ -63,7 +63,15 @@ func (p *Palette) get(fields ...string) lipgloss.Style {
 	return current.style
 }
 
+// Reset removes all the styles so that the palette can be rebuilt
+func (p *Palette) Reset() {
+	p.root = nil
+	clear(p.cache)
+}
+
 func (p *Palette) Update(styleMap map[string]config.Color) {
+	// styles resolved before may inherit from the updated ones
+	clear(p.cache)
 	for key, color := range styleMap {
 		p.add(key, createStyleFrom(color))
 	}
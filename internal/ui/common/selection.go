@@ -40,6 +40,20 @@ func (s SelectedOperation) Equal(other SelectedItem) bool {
 	return false
 }
 
+// SelectedOperationRange is selected when two operations are checked in the op
+// log. From is the older operation.
+type SelectedOperationRange struct {
+	From string
+	To   string
+}
+
+func (s SelectedOperationRange) Equal(other SelectedItem) bool {
+	if o, ok := other.(SelectedOperationRange); ok {
+		return s.From == o.From && s.To == o.To
+	}
+	return false
+}
+
 type SelectedCommit struct {
 	CommitId string
 }
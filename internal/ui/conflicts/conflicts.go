@@ -0,0 +1,462 @@
+package conflicts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ help.KeyMap           = (*Model)(nil)
+)
+
+// makeTempDir creates the directory resolved files of non working copy revisions are written to
+var makeTempDir = func() (string, error) {
+	return os.MkdirTemp("", "jjui-resolve-")
+}
+
+type rowKind int
+
+const (
+	rowFile rowKind = iota
+	rowConflict
+	rowSide
+	rowLine
+	rowMessage
+)
+
+type row struct {
+	kind     rowKind
+	file     int
+	conflict int
+	side     int
+	text     string
+}
+
+// sideView is a side of a conflict as it is shown to the user
+type sideView struct {
+	name   string
+	choice jj.ConflictChoice
+	side   jj.ConflictSide
+}
+
+type conflictView struct {
+	region int
+	sides  []sideView
+}
+
+type conflictFile struct {
+	path      string
+	regions   []jj.ConflictRegion
+	conflicts []conflictView
+	choices   []jj.ConflictChoice
+	err       error
+}
+
+func (f *conflictFile) resolved() int {
+	count := 0
+	for _, choice := range f.choices {
+		if choice != jj.ConflictUnresolved {
+			count++
+		}
+	}
+	return count
+}
+
+type loadedMsg struct {
+	files []*conflictFile
+}
+
+type rowClickedMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type styles struct {
+	border   lipgloss.Style
+	title    lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+	file     lipgloss.Style
+	label    lipgloss.Style
+	chosen   lipgloss.Style
+	err      lipgloss.Style
+}
+
+// Model lists the conflicts of the conflicted files of a revision and lets the
+// user pick a side for each of them.
+type Model struct {
+	context             *context.MainContext
+	revision            *jj.Commit
+	paths               []string
+	files               []*conflictFile
+	loading             bool
+	rows                []row
+	cursor              int
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.Up,
+		m.keymap.Down,
+		m.keymap.Conflicts.Ours,
+		m.keymap.Conflicts.Theirs,
+		m.keymap.Conflicts.Base,
+		m.keymap.Conflicts.Both,
+		m.keymap.Conflicts.Reset,
+		m.keymap.Conflicts.Write,
+		m.keymap.Conflicts.Close,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	revision := m.revision.GetChangeId()
+	paths := m.paths
+	return func() tea.Msg {
+		var files []*conflictFile
+		for _, path := range paths {
+			file := &conflictFile{path: path}
+			content, err := m.context.ReadFile(revision, path)
+			if err != nil {
+				file.err = err
+			} else {
+				file.regions = jj.ParseConflicts(content)
+			}
+			files = append(files, file)
+		}
+		return loadedMsg{files: files}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loading = false
+		m.files = msg.files
+		for _, file := range m.files {
+			file.conflicts = conflictViews(file.regions)
+			file.choices = make([]jj.ConflictChoice, len(file.conflicts))
+		}
+		m.buildRows()
+		m.cursor = -1
+		m.move(1)
+	case tea.KeyMsg:
+		km := m.keymap.Conflicts
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.move(1)
+		case key.Matches(msg, km.Ours):
+			m.choose(jj.ConflictOurs)
+		case key.Matches(msg, km.Theirs):
+			m.choose(jj.ConflictTheirs)
+		case key.Matches(msg, km.Base):
+			m.choose(jj.ConflictBase)
+		case key.Matches(msg, km.Both):
+			m.choose(jj.ConflictBoth)
+		case key.Matches(msg, km.Reset):
+			m.choose(jj.ConflictUnresolved)
+		case key.Matches(msg, km.Write):
+			return m.write()
+		case key.Matches(msg, km.Close), key.Matches(msg, m.keymap.Cancel):
+			return common.Close
+		}
+	case rowClickedMsg:
+		if msg.Index >= 0 && msg.Index < len(m.rows) && m.rows[msg.Index].kind == rowConflict {
+			m.cursor = msg.Index
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.ensureCursorVisible = false
+		m.listRenderer.SetScrollOffset(max(0, m.listRenderer.GetScrollOffset()+msg.Delta))
+	}
+	return nil
+}
+
+// move jumps to the previous or the next conflict
+func (m *Model) move(delta int) {
+	for i := m.cursor + delta; i >= 0 && i < len(m.rows); i += delta {
+		if m.rows[i].kind == rowConflict {
+			m.cursor = i
+			m.ensureCursorVisible = true
+			return
+		}
+	}
+}
+
+func (m *Model) choose(choice jj.ConflictChoice) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return
+	}
+	r := m.rows[m.cursor]
+	if r.kind != rowConflict {
+		return
+	}
+	file := m.files[r.file]
+	if choice == jj.ConflictBase && len(file.regions[file.conflicts[r.conflict].region].Bases) == 0 {
+		return
+	}
+	file.choices[r.conflict] = choice
+	m.move(1)
+}
+
+// write stores the resolved content of every file with at least one resolved
+// conflict. The working copy is written in place and snapshotted; other
+// revisions are resolved through jj resolve.
+func (m *Model) write() tea.Cmd {
+	var files []*conflictFile
+	for _, file := range m.files {
+		if file.resolved() > 0 {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	if m.revision.IsWorkingCopy {
+		for _, file := range files {
+			target := filepath.Join(m.context.Location, filepath.FromSlash(file.path))
+			if err := writeFile(target, jj.ResolveConflicts(file.regions, file.choices)); err != nil {
+				return failed(err)
+			}
+		}
+		return tea.Batch(common.Close, m.context.RunCommand(jj.Snapshot(), common.Refresh))
+	}
+
+	dir, err := makeTempDir()
+	if err != nil {
+		return failed(err)
+	}
+	var cmds []tea.Cmd
+	for i, file := range files {
+		source := filepath.Join(dir, fmt.Sprintf("%d-%s", i, filepath.Base(file.path)))
+		if err := writeFile(source, jj.ResolveConflicts(file.regions, file.choices)); err != nil {
+			_ = os.RemoveAll(dir)
+			return failed(err)
+		}
+		cmds = append(cmds, m.context.RunCommand(jj.ResolveWithFile(m.revision.GetChangeId(), file.path, source)))
+	}
+	cmds = append(cmds, func() tea.Msg {
+		_ = os.RemoveAll(dir)
+		return nil
+	}, common.Refresh)
+	return tea.Batch(common.Close, tea.Sequence(cmds...))
+}
+
+// writeFile replaces the content of the file while keeping its permissions
+func writeFile(path string, content string) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(path, []byte(content), mode)
+}
+
+func failed(err error) tea.Cmd {
+	return func() tea.Msg {
+		return common.CommandCompletedMsg{Err: errors.Join(errors.New("failed to write resolved files"), err)}
+	}
+}
+
+func conflictViews(regions []jj.ConflictRegion) []conflictView {
+	var views []conflictView
+	for ri, region := range regions {
+		if !region.IsConflict() {
+			continue
+		}
+		view := conflictView{region: ri}
+		last := len(region.Sides) - 1
+		for si, side := range region.Sides {
+			switch si {
+			case 0:
+				view.sides = append(view.sides, sideView{name: "ours", choice: jj.ConflictOurs, side: side})
+				if len(region.Bases) > 0 {
+					view.sides = append(view.sides, sideView{name: "base", choice: jj.ConflictBase, side: region.Bases[0]})
+				}
+			case last:
+				view.sides = append(view.sides, sideView{name: "theirs", choice: jj.ConflictTheirs, side: side})
+			default:
+				view.sides = append(view.sides, sideView{name: fmt.Sprintf("side #%d", si+1), choice: jj.ConflictBoth, side: side})
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+func (m *Model) buildRows() {
+	m.rows = nil
+	for fi, file := range m.files {
+		m.rows = append(m.rows, row{kind: rowFile, file: fi})
+		switch {
+		case file.err != nil:
+			m.rows = append(m.rows, row{kind: rowMessage, file: fi, text: file.err.Error()})
+			continue
+		case len(file.conflicts) == 0:
+			m.rows = append(m.rows, row{kind: rowMessage, file: fi, text: "no conflict markers found, use jj resolve for this file"})
+			continue
+		}
+		for ci, conflict := range file.conflicts {
+			m.rows = append(m.rows, row{kind: rowConflict, file: fi, conflict: ci})
+			for si, side := range conflict.sides {
+				m.rows = append(m.rows, row{kind: rowSide, file: fi, conflict: ci, side: si})
+				for _, line := range side.side.Lines {
+					m.rows = append(m.rows, row{kind: rowLine, file: fi, conflict: ci, side: si, text: line})
+				}
+			}
+		}
+	}
+}
+
+// isChosen reports whether the side ends up in the file with the current choice
+func isChosen(side sideView, choice jj.ConflictChoice) bool {
+	switch choice {
+	case jj.ConflictUnresolved:
+		return false
+	case jj.ConflictBoth:
+		return side.choice != jj.ConflictBase
+	default:
+		return side.choice == choice
+	}
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	frame := box.Center(max(box.R.Dx()-4, 0), max(box.R.Dy()-2, 0))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 3 {
+		return
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	contentBox := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	window.AddDraw(frame.R, m.styles.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(contentBox.R, ' ', m.styles.text, render.ZDialogs)
+
+	titleBox, listBox := contentBox.CutTop(1)
+	window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).
+		Styled(fmt.Sprintf("Resolve conflicts of %s", m.revision.GetChangeId()), m.styles.title).
+		Done()
+
+	if m.loading {
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled("Loading...", m.styles.dimmed).Done()
+		return
+	}
+
+	m.listRenderer.Render(
+		window,
+		listBox,
+		len(m.rows),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			m.renderRow(dl, index, rect)
+		},
+		func(index int) tea.Msg { return rowClickedMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(window, listBox)
+	m.ensureCursorVisible = false
+}
+
+func (m *Model) renderRow(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+	r := m.rows[index]
+	file := m.files[r.file]
+	tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs)
+	switch r.kind {
+	case rowFile:
+		tb.Styled(file.path, m.styles.file)
+		if len(file.conflicts) > 0 {
+			tb.Space(1).Styled(fmt.Sprintf("(%d of %d resolved)", file.resolved(), len(file.conflicts)), m.styles.dimmed)
+		}
+	case rowMessage:
+		tb.Space(2).Styled(r.text, m.styles.err)
+	case rowConflict:
+		choice := file.choices[r.conflict]
+		tb.Space(2).Styled(fmt.Sprintf("Conflict %d of %d", r.conflict+1, len(file.conflicts)), m.styles.label)
+		style := m.styles.dimmed
+		if choice != jj.ConflictUnresolved {
+			style = m.styles.chosen
+		}
+		tb.Styled(": ", m.styles.label).Styled(choice.String(), style)
+	case rowSide:
+		side := file.conflicts[r.conflict].sides[r.side]
+		tb.Space(4).Styled(side.name, m.styles.label)
+		if side.side.Label != "" {
+			tb.Space(1).Styled(side.side.Label, m.styles.dimmed)
+		}
+	case rowLine:
+		side := file.conflicts[r.conflict].sides[r.side]
+		choice := file.choices[r.conflict]
+		style := m.styles.text
+		if isChosen(side, choice) {
+			style = m.styles.chosen
+		} else if choice != jj.ConflictUnresolved {
+			style = m.styles.dimmed
+		}
+		line := strings.ReplaceAll(strings.TrimRight(r.text, "\r\n"), "\t", "    ")
+		tb.Space(6).Styled(line, style)
+	}
+	tb.Done()
+	if index == m.cursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZDialogs+1)
+	}
+}
+
+func New(context *context.MainContext, revision *jj.Commit, files []string) *Model {
+	return &Model{
+		context:      context,
+		revision:     revision,
+		paths:        files,
+		loading:      true,
+		keymap:       config.Current.GetKeyMap(),
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+		styles: styles{
+			border:   common.DefaultPalette.GetBorder("conflicts border", lipgloss.RoundedBorder()),
+			title:    common.DefaultPalette.Get("conflicts title"),
+			text:     common.DefaultPalette.Get("conflicts text"),
+			dimmed:   common.DefaultPalette.Get("conflicts dimmed"),
+			selected: common.DefaultPalette.Get("conflicts selected"),
+			file:     common.DefaultPalette.Get("diff file"),
+			label:    common.DefaultPalette.Get("diff hunk"),
+			chosen:   common.DefaultPalette.Get("diff added"),
+			err:      common.DefaultPalette.Get("error"),
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package conflicts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	Revision = "revision"
+	Content  = `before
+<<<<<<< Conflict 1 of 1
++++++++ Contents of side #1
+grapefruit
+------- Contents of base
+grape
++++++++ Contents of side #2
+GRAPE
+>>>>>>> Conflict 1 of 1 ends
+after
+`
+)
+
+func TestInit_ListsSidesOfConflicts(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: Revision}, []string{"file.txt"})
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "file.txt (0 of 1 resolved)")
+	assert.Contains(t, rendered, "Conflict 1 of 1: unresolved")
+	assert.Contains(t, rendered, "ours Contents of side #1")
+	assert.Contains(t, rendered, "base Contents of base")
+	assert.Contains(t, rendered, "theirs Contents of side #2")
+	assert.Contains(t, rendered, "GRAPE")
+}
+
+func TestInit_ReportsFilesWithoutMarkers(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte("content\n"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: Revision}, []string{"file.txt"})
+	test.SimulateModel(model, model.Init())
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "no conflict markers found")
+}
+
+func TestWrite_WritesWorkingCopyAndSnapshots(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	commandRunner.Expect(jj.Snapshot())
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Location = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(ctx.Location, "file.txt"), []byte(Content), 0o600))
+
+	model := New(ctx, &jj.Commit{ChangeId: Revision, IsWorkingCopy: true}, []string{"file.txt"})
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("t"))
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "Conflict 1 of 1: theirs")
+	test.SimulateModel(model, test.Type("w"))
+
+	content, err := os.ReadFile(filepath.Join(ctx.Location, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "before\nGRAPE\nafter\n", string(content))
+	info, err := os.Stat(filepath.Join(ctx.Location, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestWrite_ResolvesOtherRevisions(t *testing.T) {
+	dir := t.TempDir()
+	previous := makeTempDir
+	makeTempDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { makeTempDir = previous })
+
+	source := filepath.Join(dir, "0-file.txt")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	commandRunner.Expect(jj.ResolveWithFile(Revision, "file.txt", source))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: Revision}, []string{"file.txt"})
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("B"))
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	content, err := os.ReadFile(source)
+	require.NoError(t, err)
+	assert.Equal(t, "before\ngrapefruit\nGRAPE\nafter\n", string(content))
+
+	test.SimulateModel(model, cmd)
+	assert.NoDirExists(t, dir)
+}
+
+func TestWrite_NothingResolved(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: Revision}, []string{"file.txt"})
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("w"))
+}
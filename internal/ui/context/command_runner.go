@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -12,9 +13,12 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/askpass"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 )
 
@@ -28,9 +32,89 @@ type CommandRunner interface {
 	RunProgramInteractiveCommand(program string, args []string, continuation tea.Cmd) tea.Cmd
 }
 
+// CommandHooks are run around the jj commands started by RunCommand,
+// RunCommandWithInput and RunInteractiveCommand.
+type CommandHooks interface {
+	// Before returns the arguments to run the command with and the messages
+	// the hooks want to show. A non-nil error vetoes the command.
+	Before(args []string) ([]string, string, error)
+	// After is called with the result of the command and returns the messages
+	// the hooks want to show.
+	After(args []string, err error) string
+}
+
 type MainCommandRunner struct {
 	Location string
 	Askpass  *askpass.Server
+	Hooks    CommandHooks
+	Journal  *config.Journal
+}
+
+// currentOperation returns the id of the latest operation so that the journal
+// can record what a command changed
+func (a *MainCommandRunner) currentOperation() string {
+	if a.Journal == nil {
+		return ""
+	}
+	output, err := a.RunCommandImmediate(jj.OpLogId(false))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// record appends a command run by RunCommand, RunCommandWithInput or
+// RunInteractiveCommand to the journal. The entry holds how the command was run
+// and its result is added here.
+func (a *MainCommandRunner) record(entry config.JournalEntry, err error) {
+	if a.Journal == nil {
+		return
+	}
+	if err != nil {
+		entry.ExitCode = -1
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			entry.ExitCode = exitError.ExitCode()
+		}
+	}
+	entry.Time = time.Now()
+	entry.OpAfter = a.currentOperation()
+	if err := a.Journal.Append(entry); err != nil {
+		log.Println("failed to write the journal:", err)
+	}
+}
+
+// runBeforeHooks returns the arguments amended by the hooks, or the message to
+// report when a hook vetoes the command
+func (a *MainCommandRunner) runBeforeHooks(args []string) ([]string, string, tea.Msg) {
+	if a.Hooks == nil {
+		return args, "", nil
+	}
+	args, messages, err := a.Hooks.Before(args)
+	if err != nil {
+		if messages != "" {
+			err = fmt.Errorf("%s\n%w", messages, err)
+		}
+		return nil, "", common.CommandCompletedMsg{Err: err}
+	}
+	return args, messages, nil
+}
+
+func (a *MainCommandRunner) runAfterHooks(args []string, err error) string {
+	if a.Hooks == nil {
+		return ""
+	}
+	return a.Hooks.After(args, err)
+}
+
+func joinMessages(messages ...string) string {
+	var nonEmpty []string
+	for _, message := range messages {
+		if message = strings.TrimSpace(message); message != "" {
+			nonEmpty = append(nonEmpty, message)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
 }
 
 func (a *MainCommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
@@ -73,6 +157,12 @@ func (a *MainCommandRunner) runCommandWithInput(args []string, input *string, co
 	commands := make([]tea.Cmd, 0)
 	commands = append(commands,
 		func() tea.Msg {
+			args, hookMessages, vetoed := a.runBeforeHooks(args)
+			if vetoed != nil {
+				return vetoed
+			}
+			hookArgs := args
+			opBefore := a.currentOperation()
 			started, cancel, env := a.Askpass.NewSubprocess(strings.Join(args, " "))
 			defer cancel()
 			if !slices.Contains(args, "--color") {
@@ -95,8 +185,9 @@ func (a *MainCommandRunner) runCommandWithInput(args []string, input *string, co
 				}()
 			}
 
-			var output bytes.Buffer
-			c.Stderr = &output
+			// jj writes its messages to stderr, the output of the command is dropped
+			var stderr bytes.Buffer
+			c.Stderr = &stderr
 			if err := c.Start(); err != nil {
 				return common.CommandCompletedMsg{
 					Err: err,
@@ -105,18 +196,23 @@ func (a *MainCommandRunner) runCommandWithInput(args []string, input *string, co
 			started(c.Process.Pid)
 
 			err := c.Wait()
+			a.record(config.JournalEntry{Args: hookArgs, Input: input, Stderr: stderr.String(), OpBefore: opBefore}, err)
 			if err != nil {
 				var exitError *exec.ExitError
 				if errors.As(err, &exitError) {
-					msg := output.String()
+					msg := stderr.String()
 					if len(env) == 0 && slices.Contains([]string{"linux", "darwin"}, runtime.GOOS) {
 						msg += "\nHint: enable ssh.hijack_askpass if you expected a password prompt (e.g. ssh passphrase)"
 					}
 					err = errors.New(msg)
 				}
 			}
+			text := stderr.String()
+			if a.Hooks != nil {
+				text = joinMessages(hookMessages, text, a.runAfterHooks(hookArgs, err))
+			}
 			return common.CommandCompletedMsg{
-				Output: output.String(),
+				Output: text,
 				Err:    err,
 			}
 		})
@@ -178,24 +274,45 @@ func (a *MainCommandRunner) RunCommand(args []string, continuations ...tea.Cmd)
 	return a.runCommandWithInput(args, nil, continuations)
 }
 
+// RunInteractiveCommand hands the terminal over to jj. Like the continuations
+// of RunCommand, the continuation runs whether the command succeeds or not.
 func (a *MainCommandRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
-	c := exec.Command("jj", args...)
-	errBuffer := &bytes.Buffer{}
-	c.Stderr = errBuffer
-	c.Dir = a.Location
 	return tea.Batch(
 		common.CommandRunning(args),
-		tea.ExecProcess(c, func(err error) tea.Msg {
-			if err != nil {
-				return common.CommandCompletedMsg{Err: errors.New(errBuffer.String())}
+		func() tea.Msg {
+			// the hooks and the operation are run here rather than in Update so
+			// that the UI doesn't wait for them
+			args, hookMessages, vetoed := a.runBeforeHooks(args)
+			if vetoed != nil {
+				return tea.Batch(continuation, func() tea.Msg { return vetoed })()
 			}
-			return tea.Batch(continuation, func() tea.Msg {
-				return common.CommandCompletedMsg{Err: nil}
+			opBefore := a.currentOperation()
+			c := exec.Command("jj", args...)
+			errBuffer := &bytes.Buffer{}
+			c.Stderr = errBuffer
+			c.Dir = a.Location
+			return tea.ExecProcess(c, func(err error) tea.Msg {
+				// the callback runs on the event loop, so the journal, which
+				// reads the operation, and the after hooks run in a command
+				return tea.Batch(continuation, func() tea.Msg {
+					a.record(config.JournalEntry{Args: args, Interactive: true, Stderr: errBuffer.String(), OpBefore: opBefore}, err)
+					return a.interactiveCompleted(args, hookMessages, errBuffer.String(), err)
+				})()
 			})()
-		}),
+		},
 	)
 }
 
+// interactiveCompleted runs the after hooks of an interactive command and
+// reports its result with the messages of the hooks
+func (a *MainCommandRunner) interactiveCompleted(args []string, hookMessages string, stderr string, err error) tea.Msg {
+	if err != nil {
+		err = errors.New(stderr)
+	}
+	afterMessages := a.runAfterHooks(args, err)
+	return common.CommandCompletedMsg{Output: joinMessages(hookMessages, afterMessages), Err: err}
+}
+
 func (a *MainCommandRunner) RunProgramCommand(program string, args []string, continuations ...tea.Cmd) tea.Cmd {
 	return a.runProgramWithInput(program, args, nil, continuations)
 }
@@ -231,6 +348,10 @@ func (c *StreamingCommand) Close() error {
 	c.once.Do(func() {
 		log.Println("closing streaming command")
 		pipeErr := c.ReadCloser.Close()
+		if c.cmd == nil {
+			err = pipeErr
+			return
+		}
 
 		if c.ctx.Err() != nil {
 			log.Println("killing process due to context cancellation")
@@ -0,0 +1,86 @@
+package context
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type vetoHooks struct {
+	calls *int
+}
+
+func (h vetoHooks) Before(args []string) ([]string, string, error) {
+	if h.calls != nil {
+		*h.calls++
+	}
+	if args[0] == "abandon" {
+		return nil, "checked abandon", errors.New("abandon is not allowed")
+	}
+	return append(args, "--quiet"), "checked " + args[0], nil
+}
+
+func (vetoHooks) After(args []string, err error) string {
+	if err != nil {
+		return "after " + args[0] + " failed"
+	}
+	return ""
+}
+
+// runCmd runs cmd and the commands of the batches it returns, and returns the
+// other messages
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, cmd := range batch {
+			msgs = append(msgs, runCmd(cmd)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestMainCommandRunner_BeforeHooks(t *testing.T) {
+	runner := &MainCommandRunner{Hooks: vetoHooks{}}
+
+	args, messages, vetoed := runner.runBeforeHooks([]string{"new"})
+	assert.Nil(t, vetoed)
+	assert.Equal(t, []string{"new", "--quiet"}, args)
+	assert.Equal(t, "checked new", messages)
+
+	_, _, vetoed = runner.runBeforeHooks([]string{"abandon"})
+	if assert.IsType(t, common.CommandCompletedMsg{}, vetoed) {
+		assert.EqualError(t, vetoed.(common.CommandCompletedMsg).Err, "checked abandon\nabandon is not allowed")
+	}
+}
+
+func TestMainCommandRunner_RunInteractiveCommand_Vetoed(t *testing.T) {
+	calls := 0
+	runner := &MainCommandRunner{Hooks: vetoHooks{calls: &calls}}
+
+	cmd := runner.RunInteractiveCommand([]string{"abandon"}, nil)
+	assert.Zero(t, calls, "the hooks don't run while the command is built")
+	msgs := runCmd(cmd)
+	assert.Equal(t, 1, calls)
+	if assert.Len(t, msgs, 2) && assert.IsType(t, common.CommandCompletedMsg{}, msgs[1]) {
+		assert.EqualError(t, msgs[1].(common.CommandCompletedMsg).Err, "checked abandon\nabandon is not allowed")
+	}
+}
+
+func TestMainCommandRunner_InteractiveCompleted_ShowsHookMessagesOnFailure(t *testing.T) {
+	runner := &MainCommandRunner{Hooks: vetoHooks{}}
+
+	msg := runner.interactiveCompleted([]string{"split"}, "checked split", "split failed", errors.New("exit status 1"))
+	assert.Equal(t, common.CommandCompletedMsg{Output: "checked split\nafter split failed", Err: errors.New("split failed")}, msg)
+}
+
+func TestJoinMessages(t *testing.T) {
+	assert.Equal(t, "a\nb", joinMessages("a\n", "", "  ", "b"))
+}
@@ -0,0 +1,239 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/forge"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// ConfigLoader loads the configuration of the app from the embedded defaults,
+// config.toml, the trusted repository config, the themes and the jj config. It
+// runs at startup and again whenever one of the watched files changes, so a
+// failed load leaves the current configuration untouched.
+type ConfigLoader struct {
+	// Revset is the default revset given on the command line
+	Revset string
+	// DarkBackground selects the themes to load. The terminal is asked once at
+	// startup since it cannot be queried while the UI is running.
+	DarkBackground bool
+	// Overrides applies the command line flags over the loaded config
+	Overrides func(c *config.Config)
+	// RepoConfig is the repository config seen at startup and RepoConfigTrusted
+	// tells whether the user trusted it
+	RepoConfig        *config.RepoConfig
+	RepoConfigTrusted bool
+	// Warnings are the problems ValidateConfig found in the loaded files
+	Warnings []string
+	// Plugins requires the Lua plugins listed in the config, adds the commands
+	// and leader keys they register and returns the status segments they add.
+	// It is set by the scripting package, which depends on this one.
+	Plugins func(ctx *MainContext, names []string, customCommands map[string]CustomCommand, leader LeaderMap) (StatusSegmentProvider, error)
+
+	watcher *config.Watcher
+}
+
+// Watch starts tracking the files the configuration is loaded from
+func (l *ConfigLoader) Watch(location string) {
+	l.watcher = config.NewWatcher(config.WatchedFiles(location)...)
+}
+
+// Changed reports whether any of the watched files changed since the last call
+func (l *ConfigLoader) Changed() bool {
+	return l.watcher != nil && l.watcher.Changed()
+}
+
+// ApplyConfig decodes data over c and adds its custom commands and leader keys
+// to the given ones, replacing those with the same name
+func ApplyConfig(c *config.Config, customCommands map[string]CustomCommand, leader LeaderMap, data string) error {
+	if err := c.Load(data); err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+	registry, err := LoadCustomCommands(data)
+	if err != nil {
+		return fmt.Errorf("loading custom commands: %w", err)
+	}
+	maps.Copy(customCommands, registry)
+	if err := LoadLeaderInto(leader, data); err != nil {
+		return fmt.Errorf("loading leader keys: %w", err)
+	}
+	return nil
+}
+
+// LoadedConfig is a configuration read by Read and not installed yet
+type LoadedConfig struct {
+	config         *config.Config
+	customCommands map[string]CustomCommand
+	leader         LeaderMap
+	jjConfig       *config.JJConfig
+	defaultRevset  string
+	theme          map[string]config.Color
+	forge          forge.Provider
+	segments       StatusSegmentProvider
+	warnings       []string
+}
+
+// Load reads the configuration and installs it in config.Current, ctx and
+// common.DefaultPalette. The forge provider is built again so that changes to
+// the forge section apply without a restart.
+func (l *ConfigLoader) Load(ctx *MainContext) error {
+	loaded, err := l.Read(ctx)
+	if err != nil {
+		return err
+	}
+	l.Apply(ctx, loaded)
+	return nil
+}
+
+// Read reads the configuration without installing it. It runs jj and the
+// plugins, so the UI calls it outside of Update and applies the result there.
+func (l *ConfigLoader) Read(ctx *MainContext) (loaded *LoadedConfig, err error) {
+	c := config.Default()
+	customCommands := make(map[string]CustomCommand)
+	leader := LeaderMap{}
+	var warnings []string
+
+	if output, err := config.LoadConfigFile(); err == nil {
+		if err := ApplyConfig(c, customCommands, leader, string(output)); err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, validationWarnings(config.ConfigFile(), string(output))...)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if repoConfig, err := config.LoadRepoConfig(ctx.Location); err == nil {
+		seen := l.RepoConfig != nil && bytes.Equal(repoConfig.Data, l.RepoConfig.Data)
+		switch {
+		case seen && l.RepoConfigTrusted, repoConfig.IsTrusted():
+			if err := ApplyConfig(c, customCommands, leader, string(repoConfig.Data)); err != nil {
+				return nil, fmt.Errorf("%s: %w", repoConfig.File, err)
+			}
+			warnings = append(warnings, validationWarnings(repoConfig.File, string(repoConfig.Data))...)
+		case !seen:
+			return nil, fmt.Errorf("%s changed and is not trusted, restart jjui to trust it", repoConfig.File)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if l.Overrides != nil {
+		l.Overrides(c)
+	}
+
+	var segments StatusSegmentProvider
+	if l.Plugins != nil && len(c.Plugins) > 0 {
+		if segments, err = l.Plugins(ctx, c.Plugins, customCommands, leader); err != nil {
+			return nil, fmt.Errorf("loading plugins: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				closeSegments(segments)
+			}
+		}()
+	}
+
+	theme, err := l.loadTheme(c)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := forge.New(c.Forge, ctx.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	jjConfig := &config.JJConfig{}
+	if output, err := ctx.RunCommandImmediate(jj.ConfigListAll()); err == nil {
+		jjConfig, _ = config.DefaultConfig(output)
+	}
+
+	defaultRevset := l.Revset
+	if defaultRevset == "" {
+		defaultRevset = c.Revisions.Revset
+	}
+	if defaultRevset == "" {
+		defaultRevset = jjConfig.Revsets.Log
+	}
+
+	return &LoadedConfig{
+		config:         c,
+		customCommands: customCommands,
+		leader:         leader,
+		jjConfig:       jjConfig,
+		defaultRevset:  defaultRevset,
+		theme:          theme,
+		forge:          provider,
+		segments:       segments,
+		warnings:       warnings,
+	}, nil
+}
+
+// Apply installs the configuration in config.Current, ctx and
+// common.DefaultPalette
+func (l *ConfigLoader) Apply(ctx *MainContext, loaded *LoadedConfig) {
+	config.Current = loaded.config
+	l.Warnings = loaded.warnings
+	ctx.CustomCommands = loaded.customCommands
+	ctx.Leader = loaded.leader
+	ctx.JJConfig = loaded.jjConfig
+	ctx.Forge = loaded.forge
+	closeSegments(ctx.PluginSegments)
+	ctx.PluginSegments = loaded.segments
+	if ctx.CurrentRevset == "" || ctx.CurrentRevset == ctx.DefaultRevset {
+		ctx.CurrentRevset = loaded.defaultRevset
+	}
+	ctx.DefaultRevset = loaded.defaultRevset
+
+	common.DefaultPalette.Reset()
+	common.DefaultPalette.Update(loaded.theme)
+	common.DefaultPalette.Update(loaded.jjConfig.GetApplicableColors())
+	common.DefaultPalette.Update(loaded.config.UI.Colors)
+}
+
+// closeSegments releases the status segments of the plugins
+func closeSegments(segments StatusSegmentProvider) {
+	if closer, ok := segments.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// validationWarnings formats the problems in a config file as file:line: message
+func validationWarnings(file string, data string) []string {
+	problems, err := ValidateConfig(data)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", file, err)}
+	}
+	var warnings []string
+	for _, problem := range problems {
+		warnings = append(warnings, problem.In(file))
+	}
+	return warnings
+}
+
+func (l *ConfigLoader) loadTheme(c *config.Config) (map[string]config.Color, error) {
+	defaultThemeName := "default_light"
+	userThemeName := c.UI.Theme.Light
+	if l.DarkBackground {
+		defaultThemeName = "default_dark"
+		userThemeName = c.UI.Theme.Dark
+	}
+
+	theme, err := config.LoadEmbeddedTheme(defaultThemeName)
+	if err != nil {
+		return nil, fmt.Errorf("loading default theme '%s': %w", defaultThemeName, err)
+	}
+	if userThemeName != "" {
+		theme, err = config.LoadTheme(userThemeName, theme)
+		if err != nil {
+			return nil, fmt.Errorf("loading user theme '%s': %w", userThemeName, err)
+		}
+	}
+	return theme, nil
+}
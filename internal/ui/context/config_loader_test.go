@@ -0,0 +1,114 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigLoader_Load(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("JJUI_CONFIG_DIR", configDir)
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+
+	location := t.TempDir()
+	ctx := &MainContext{CommandRunner: &MainCommandRunner{Location: location}, Location: location}
+	loader := &ConfigLoader{Overrides: func(c *config.Config) { c.Limit = 5 }}
+
+	configFile := filepath.Join(configDir, "config.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+[revisions]
+revset = "::@"
+[custom_commands.hello]
+args = ["log"]
+[leader.h]
+help = "hello"
+send = ["x"]
+`), 0644))
+	require.NoError(t, loader.Load(ctx))
+	assert.Equal(t, 5, config.Current.Limit)
+	assert.Equal(t, "::@", ctx.DefaultRevset)
+	assert.Equal(t, "::@", ctx.CurrentRevset)
+	assert.Contains(t, ctx.CustomCommands, "hello")
+	assert.Contains(t, ctx.Leader, "h")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+[revisions]
+revset = "mine()"
+`), 0644))
+	require.NoError(t, loader.Load(ctx))
+	assert.Equal(t, "mine()", ctx.CurrentRevset)
+	assert.Empty(t, ctx.CustomCommands)
+	assert.Empty(t, ctx.Leader)
+	assert.Empty(t, loader.Warnings)
+
+	loaded := config.Current
+	require.NoError(t, os.WriteFile(configFile, []byte("[revisions\n"), 0644))
+	assert.ErrorContains(t, loader.Load(ctx), "loading configuration")
+	assert.Same(t, loaded, config.Current)
+	assert.Equal(t, "mine()", ctx.CurrentRevset)
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[revisions]\nrevest = \"mine()\"\n"), 0644))
+	require.NoError(t, loader.Load(ctx))
+	assert.Equal(t, []string{configFile + `:2: unknown key "revisions.revest"`}, loader.Warnings)
+	assert.Nil(t, ctx.Forge)
+
+	require.NoError(t, os.WriteFile(configFile, []byte("[forge]\nprovider = \"github\"\n"), 0644))
+	require.NoError(t, loader.Load(ctx))
+	assert.NotNil(t, ctx.Forge)
+}
+
+func TestConfigLoader_Load_RepoConfig(t *testing.T) {
+	t.Setenv("JJUI_CONFIG_DIR", t.TempDir())
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+
+	location := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(location, ".jj"), 0755))
+	repoConfigFile := filepath.Join(location, ".jj", "jjui.toml")
+	require.NoError(t, os.WriteFile(repoConfigFile, []byte("[revisions]\nrevset = \"trunk()..@\"\n"), 0644))
+	repoConfig, err := config.LoadRepoConfig(location)
+	require.NoError(t, err)
+
+	ctx := &MainContext{CommandRunner: &MainCommandRunner{Location: location}, Location: location}
+
+	declined := &ConfigLoader{RepoConfig: repoConfig}
+	require.NoError(t, declined.Load(ctx))
+	assert.NotEqual(t, "trunk()..@", ctx.DefaultRevset)
+
+	trusted := &ConfigLoader{RepoConfig: repoConfig, RepoConfigTrusted: true}
+	require.NoError(t, trusted.Load(ctx))
+	assert.Equal(t, "trunk()..@", ctx.DefaultRevset)
+
+	require.NoError(t, os.WriteFile(repoConfigFile, []byte("[revisions]\nrevset = \"all()\"\n"), 0644))
+	assert.ErrorContains(t, trusted.Load(ctx), "is not trusted")
+	assert.Equal(t, "trunk()..@", ctx.DefaultRevset)
+}
+
+func TestConfigLoader_LoadsPluginsOfConfig(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("JJUI_CONFIG_DIR", configDir)
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+
+	location := t.TempDir()
+	ctx := &MainContext{CommandRunner: &MainCommandRunner{Location: location}, Location: location}
+	var loaded []string
+	loader := &ConfigLoader{Plugins: func(_ *MainContext, names []string, customCommands map[string]CustomCommand, leader LeaderMap) (StatusSegmentProvider, error) {
+		loaded = names
+		customCommands["push"] = CustomLuaCommand{Plugin: "stack"}
+		AddLeaderCommand(leader, "p", "push", "push")
+		return nil, nil
+	}}
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(`plugins = ["stack"]`), 0644))
+	require.NoError(t, loader.Load(ctx))
+	assert.Equal(t, []string{"stack"}, loaded)
+	assert.Contains(t, ctx.CustomCommands, "push")
+	assert.Equal(t, "push", ctx.Leader["p"].Command)
+}
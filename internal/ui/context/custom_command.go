@@ -80,7 +80,9 @@ func LoadCustomCommands(output string) (map[string]CustomCommand, error) {
 			return nil, fmt.Errorf("failed to decode custom command %s: %w", name, err)
 		}
 
-		if _, hasLua := tempMap["lua"]; hasLua {
+		_, hasLua := tempMap["lua"]
+		_, hasLuaFile := tempMap["lua_file"]
+		if hasLua || hasLuaFile {
 			var cmd CustomLuaCommand
 			if err := metadata.PrimitiveDecode(primitive, &cmd); err != nil {
 				return nil, fmt.Errorf("failed to decode lua command %s: %w", name, err)
@@ -1,10 +1,15 @@
 package context
 
 import (
+	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoad_CustomCommands(t *testing.T) {
@@ -99,3 +104,27 @@ func TestLoad_CustomCommands(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_LuaFileCommand(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("JJUI_CONFIG_DIR", configDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "plugins"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "plugins", "stack.lua"), []byte(`flash("stack")`), 0o644))
+
+	registry, err := LoadCustomCommands(`
+[custom_commands]
+"stack" = { key = ["ctrl+s"], lua_file = "stack.lua" }
+"missing" = { key = ["ctrl+m"], lua_file = "missing.lua" }
+`)
+	require.NoError(t, err)
+
+	command, ok := registry["stack"].(CustomLuaCommand)
+	require.True(t, ok)
+	ctx := &MainContext{}
+	assert.Equal(t, "lua: stack.lua", command.Description(ctx))
+	assert.Equal(t, common.RunLuaScriptMsg{Script: `flash("stack")`}, command.Prepare(ctx)())
+
+	msg, ok := registry["missing"].Prepare(ctx)().(common.CommandCompletedMsg)
+	require.True(t, ok)
+	assert.ErrorIs(t, msg.Err, fs.ErrNotExist)
+}
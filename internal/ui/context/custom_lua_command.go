@@ -2,15 +2,22 @@ package context
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
 )
 
-// CustomLuaCommand executes a Lua script via the capability bridge.
+// CustomLuaCommand executes a Lua script via the capability bridge. The script
+// is given inline, read from a file in the plugin directories, or registered
+// by a plugin.
 type CustomLuaCommand struct {
 	CustomCommandBase
 	Script string `toml:"lua"`
+	File   string `toml:"lua_file"`
+	// Plugin is the module that registered the command with jjui.register_command
+	Plugin string `toml:"-"`
 }
 
 func (c CustomLuaCommand) IsApplicableTo(item SelectedItem) bool {
@@ -18,11 +25,31 @@ func (c CustomLuaCommand) IsApplicableTo(item SelectedItem) bool {
 }
 
 func (c CustomLuaCommand) Description(ctx *MainContext) string {
+	switch {
+	case c.Plugin != "":
+		return fmt.Sprintf("plugin: %s", c.Plugin)
+	case c.File != "":
+		return fmt.Sprintf("lua: %s", c.File)
+	}
 	return fmt.Sprintf("lua: %s", c.Script)
 }
 
 func (c CustomLuaCommand) Prepare(ctx *MainContext) tea.Cmd {
+	if c.File == "" {
+		return func() tea.Msg {
+			return common.RunLuaScriptMsg{Script: c.Script}
+		}
+	}
+	location := ctx.Location
 	return func() tea.Msg {
-		return common.RunLuaScriptMsg{Script: c.Script}
+		file, err := config.FindPlugin(location, c.File)
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		script, err := os.ReadFile(file)
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		return common.RunLuaScriptMsg{Script: string(script)}
 	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/idursun/jjui/internal/ui/common"
 )
 
 type LeaderMap = map[string]*Leader
@@ -14,13 +15,58 @@ type Leader struct {
 	Send    []string
 	Context []string
 	Nest    LeaderMap
+	// Command is the name of the custom command run by the entry
+	Command string
+	// Lua is the script run by the entry
+	Lua string
+	// When hides the entry unless its conditions hold
+	When LeaderCondition
+}
+
+// LeaderCondition shows a leader entry only when the selected item is of the
+// given type ("revision", "file", "operation" or "commit"), in the given
+// operation mode ("normal", "rebase", ...) and when the selected revision is
+// in the revset. Empty conditions always hold.
+type LeaderCondition struct {
+	Item   string
+	Mode   string
+	Revset string
+}
+
+// ItemType returns the name the item is matched with in LeaderCondition.Item
+func ItemType(item SelectedItem) string {
+	switch item.(type) {
+	case SelectedRevision:
+		return "revision"
+	case SelectedFile:
+		return "file"
+	case common.SelectedOperation, common.SelectedOperationRange:
+		return "operation"
+	case SelectedCommit:
+		return "commit"
+	default:
+		return ""
+	}
 }
 
 func LoadLeader(content string) (LeaderMap, error) {
+	res := LeaderMap{}
+	if err := LoadLeaderInto(res, content); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LoadLeaderInto adds the leader keys in content to res, replacing the entries
+// bound to the same keys
+func LoadLeaderInto(res LeaderMap, content string) error {
 	type leaderTomlEntry struct {
 		Help    string
 		Send    []string
 		Context []string
+		Command string
+		Lua     string
+		When    LeaderCondition
 	}
 	type leaderToml struct {
 		Leader map[string]leaderTomlEntry
@@ -28,9 +74,8 @@ func LoadLeader(content string) (LeaderMap, error) {
 	dec := leaderToml{}
 	_, err := toml.Decode(content, &dec)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	res := LeaderMap{}
 	for name, v := range dec.Leader {
 		ks := strings.Split(name, "")
 		at := res
@@ -39,6 +84,9 @@ func LoadLeader(content string) (LeaderMap, error) {
 			if i == len(ks)-1 {
 				m.Send = v.Send
 				m.Context = v.Context
+				m.Command = v.Command
+				m.Lua = v.Lua
+				m.When = v.When
 				if len(v.Help) > 0 {
 					m.Bind.SetHelp(k, v.Help)
 				}
@@ -46,7 +94,22 @@ func LoadLeader(content string) (LeaderMap, error) {
 			at = m.Nest
 		}
 	}
-	return res, nil
+	return nil
+}
+
+// AddLeaderCommand binds the keys pressed after the leader to the custom
+// command with the given name
+func AddLeaderCommand(res LeaderMap, keys string, help string, command string) {
+	at := res
+	ks := strings.Split(keys, "")
+	for i, k := range ks {
+		m := checkExists(at, k)
+		if i == len(ks)-1 {
+			m.Command = command
+			m.Bind.SetHelp(k, help)
+		}
+		at = m.Nest
+	}
 }
 
 func checkExists(at LeaderMap, k string) *Leader {
@@ -79,3 +79,52 @@ func TestLoadLeader(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadLeaderInto_OverridesExistingKeys(t *testing.T) {
+	lm, err := LoadLeader(exampleLeaderToml)
+	if err != nil {
+		t.Fatalf("LoadLeader failed: %v", err)
+	}
+
+	overlay := `
+[leader.gff]
+help = "Git Fetch Upstream"
+send = ["g/fetch --remote upstream", "down", "enter"]
+`
+	if err := LoadLeaderInto(lm, overlay); err != nil {
+		t.Fatalf("LoadLeaderInto failed: %v", err)
+	}
+
+	gff := lm["g"].Nest["f"].Nest["f"]
+	if gff.Bind.Help().Desc != "Git Fetch Upstream" || gff.Send[0] != "g/fetch --remote upstream" {
+		t.Errorf("leader.gff was not overridden: got %q %v", gff.Bind.Help().Desc, gff.Send)
+	}
+	if lm["g"].Nest["f"].Nest["a"] == nil || lm["M"] == nil {
+		t.Error("keys missing from the overlay were removed")
+	}
+}
+
+func TestLoadLeader_CommandLuaAndConditions(t *testing.T) {
+	lm, err := LoadLeader(`
+[leader.p]
+help = "Push"
+command = "push"
+
+[leader.n]
+lua = "jj('new')"
+when = { item = "revision", mode = "normal", revset = "mutable()" }
+`)
+	if err != nil {
+		t.Fatalf("LoadLeader failed: %v", err)
+	}
+	if lm["p"].Command != "push" {
+		t.Errorf("leader.p command mismatch: got %q", lm["p"].Command)
+	}
+	if lm["n"].Lua != "jj('new')" {
+		t.Errorf("leader.n lua mismatch: got %q", lm["n"].Lua)
+	}
+	expected := LeaderCondition{Item: "revision", Mode: "normal", Revset: "mutable()"}
+	if lm["n"].When != expected {
+		t.Errorf("leader.n when mismatch: got %+v", lm["n"].When)
+	}
+}
@@ -1,12 +1,17 @@
 package context
 
 import (
+	"context"
+	"errors"
+	"io"
 	"reflect"
 	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/idursun/jjui/internal/askpass"
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/forge"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 
@@ -19,6 +24,7 @@ type SelectedRevision = common.SelectedRevision
 type SelectedCommit = common.SelectedCommit
 type SelectedFile = common.SelectedFile
 type SelectedOperation = common.SelectedOperation
+type SelectedOperationRange = common.SelectedOperationRange
 
 type MainContext struct {
 	CommandRunner
@@ -31,17 +37,41 @@ type MainContext struct {
 	DefaultRevset  string
 	CurrentRevset  string
 	Histories      *config.Histories
+	Forge          forge.Provider // nil when the forge integration is disabled
+	Journal        *config.Journal
+	Folds          *config.Folds // nil when the folds are not persisted
+	ConfigLoader   *ConfigLoader // nil when the configuration is not reloaded on changes
+	InDashboard    bool          // quitting goes back to the dashboard of repositories
+	SavedViews     *config.SavedViews
+	View           *config.ViewConfig    // the view shown, nil when the revset is not from a view
+	Template       string                // the template picked while jjui runs, overrides the one of the view
+	StatusSegments StatusSegmentProvider // nil when init.lua adds no segments to the status line
+	PluginSegments StatusSegmentProvider // nil when no plugin adds segments to the status line
+	// Previewing is set while a command runs to preview its result, the
+	// repository is not at the operation the UI shows until it is restored
+	Previewing atomic.Bool
+}
+
+// StatusSegmentProvider gives the texts shown at the right of the status line.
+// It is polled while the UI runs.
+type StatusSegmentProvider interface {
+	StatusSegments() []string
 }
 
 func NewAppContext(location string, aps *askpass.Server) *MainContext {
+	journal := config.NewJournal(location)
 	m := &MainContext{
 		CommandRunner: &MainCommandRunner{
 			Location: location,
 			Askpass:  aps,
+			Journal:  journal,
 		},
 		Location:  location,
 		Histories: config.NewHistories(),
+		Journal:   journal,
+		Folds:     config.NewFolds(location),
 	}
+	m.SavedViews = config.NewSavedViews(location)
 
 	m.JJConfig = &config.JJConfig{}
 	if output, err := m.RunCommandImmediate(jj.ConfigListAll()); err == nil {
@@ -50,6 +80,81 @@ func NewAppContext(location string, aps *askpass.Server) *MainContext {
 	return m
 }
 
+// Views returns the views of the config and the views saved in the
+// repository by name. Saved views replace the ones of the config.
+func (ctx *MainContext) Views() (map[string]config.ViewConfig, error) {
+	views := make(map[string]config.ViewConfig)
+	for name, view := range config.Current.Views {
+		view.Name = name
+		views[name] = view
+	}
+	saved, err := ctx.SavedViews.Load()
+	for name, view := range saved {
+		views[name] = view
+	}
+	return views, err
+}
+
+// LogLimit is the number of revisions to show
+func (ctx *MainContext) LogLimit() int {
+	if ctx.View != nil && ctx.View.Limit > 0 {
+		return ctx.View.Limit
+	}
+	return config.Current.Limit
+}
+
+// LogTemplate is the template the revisions are shown with, the one picked
+// or the default one
+func (ctx *MainContext) LogTemplate() string {
+	if ctx.Template != "" {
+		return ctx.Template
+	}
+	return ctx.DefaultLogTemplate()
+}
+
+// DefaultLogTemplate is the template of the view, then the one of the config,
+// then jj's templates.log
+func (ctx *MainContext) DefaultLogTemplate() string {
+	switch {
+	case ctx.View != nil && ctx.View.Template != "":
+		return ctx.View.Template
+	case config.Current.Revisions.Template != "":
+		return config.Current.Revisions.Template
+	case ctx.JJConfig != nil:
+		return ctx.JJConfig.Templates.Log
+	}
+	return ""
+}
+
+// SetCommandHooks installs hooks around the jj commands run by the app
+func (ctx *MainContext) SetCommandHooks(hooks CommandHooks) {
+	if runner, ok := ctx.CommandRunner.(*MainCommandRunner); ok {
+		runner.Hooks = hooks
+	}
+}
+
+// ReadFile returns the content of the file at the given revision byte for byte.
+// RunCommandImmediate trims its output, which would drop leading and trailing
+// blank lines of the file.
+func (ctx *MainContext) ReadFile(revision string, fileName string) (string, error) {
+	command, err := ctx.RunCommandStreaming(context.Background(), jj.FileShow(revision, fileName))
+	if err != nil {
+		return "", err
+	}
+	content, readErr := io.ReadAll(command)
+	var stderr []byte
+	if command.ErrPipe != nil {
+		stderr, _ = io.ReadAll(command.ErrPipe)
+	}
+	if err := command.Close(); err != nil {
+		if len(stderr) > 0 {
+			return "", errors.New(string(stderr))
+		}
+		return "", err
+	}
+	return string(content), readErr
+}
+
 func (ctx *MainContext) ClearCheckedItems(ofType reflect.Type) {
 	ctx.CheckedItems = slices.DeleteFunc(ctx.CheckedItems, func(i SelectedItem) bool {
 		return ofType == nil || ofType == reflect.TypeOf(i)
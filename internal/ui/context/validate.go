@@ -0,0 +1,120 @@
+package context
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+)
+
+var (
+	customCommandKeys    = []string{"desc", "key", "key_sequence", "args", "show", "revset", "lua", "lua_file"}
+	leaderKeys           = []string{"help", "send", "context", "command", "lua", "when"}
+	leaderConditionKeys  = []string{"item", "mode", "revset"}
+	leaderItemTypes      = []string{"revision", "file", "operation", "commit"}
+	revisionPlaceholders = []string{jj.ChangeIdPlaceholder, jj.CommitIdPlaceholder, jj.FilePlaceholder}
+)
+
+// ValidateConfig checks a config file like config.Validate, and the custom
+// commands and leader keys in it
+func ValidateConfig(data string) ([]config.Problem, error) {
+	problems, err := config.Validate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables struct {
+		CustomCommands map[string]map[string]any `toml:"custom_commands"`
+		Leader         map[string]map[string]any `toml:"leader"`
+	}
+	if _, err := toml.Decode(data, &tables); err != nil {
+		return nil, err
+	}
+	lines := config.FindKeyLines(data)
+	unknownKeys := func(table string, name string, fields map[string]any, allowed []string) {
+		for _, field := range slices.Sorted(maps.Keys(fields)) {
+			if !slices.Contains(allowed, field) {
+				problems = append(problems, config.Problem{
+					Line:    lines.Line(table, name, field),
+					Message: fmt.Sprintf("unknown key %q in %s.%s", field, table, name),
+				})
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(tables.CustomCommands)) {
+		fields := tables.CustomCommands[name]
+		unknownKeys("custom_commands", name, fields, customCommandKeys)
+		if show, ok := fields["show"]; ok {
+			var option config.ShowOption
+			if err := option.UnmarshalText([]byte(fmt.Sprint(show))); err != nil {
+				problems = append(problems, config.Problem{Line: lines.Line("custom_commands", name, "show"), Message: err.Error()})
+			}
+		}
+		if message := neverApplies(fields); message != "" {
+			problems = append(problems, config.Problem{
+				Line:    lines.Line("custom_commands", name),
+				Message: fmt.Sprintf("custom command %s %s", name, message),
+			})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(tables.Leader)) {
+		fields := tables.Leader[name]
+		unknownKeys("leader", name, fields, leaderKeys)
+		when, _ := fields["when"].(map[string]any)
+		unknownKeys("leader", name+".when", when, leaderConditionKeys)
+		if item, ok := when["item"]; ok && !slices.Contains(leaderItemTypes, fmt.Sprint(item)) {
+			problems = append(problems, config.Problem{
+				Line:    lines.Line("leader", name, "when", "item"),
+				Message: fmt.Sprintf("unknown item %q in leader.%s.when, expected one of %s", item, name, strings.Join(leaderItemTypes, ", ")),
+			})
+		}
+	}
+
+	config.SortProblems(problems)
+	return problems, nil
+}
+
+// neverApplies explains why the placeholders of a custom command can never be
+// replaced together, see the IsApplicableTo methods of the custom commands
+func neverApplies(fields map[string]any) string {
+	if _, ok := fields["lua"]; ok {
+		return ""
+	}
+	if _, ok := fields["lua_file"]; ok {
+		return ""
+	}
+	uses := func(text string, placeholders ...string) []string {
+		var used []string
+		for _, placeholder := range placeholders {
+			if strings.Contains(text, placeholder) {
+				used = append(used, placeholder)
+			}
+		}
+		return used
+	}
+
+	if revset, ok := fields["revset"]; ok {
+		if used := uses(fmt.Sprint(revset), jj.FilePlaceholder, jj.OperationIdPlaceholder); len(used) > 0 {
+			return fmt.Sprintf("changes the revset from a revision and can never use %s", strings.Join(used, ", "))
+		}
+		return ""
+	}
+
+	args, _ := fields["args"].([]any)
+	var joined []string
+	for _, arg := range args {
+		joined = append(joined, fmt.Sprint(arg))
+	}
+	text := strings.Join(joined, " ")
+	if len(uses(text, jj.OperationIdPlaceholder)) > 0 {
+		if used := uses(text, revisionPlaceholders...); len(used) > 0 {
+			return fmt.Sprintf("uses %s together with %s and can never apply to a selection", jj.OperationIdPlaceholder, strings.Join(used, ", "))
+		}
+	}
+	return ""
+}
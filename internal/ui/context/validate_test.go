@@ -0,0 +1,67 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig_CustomCommands(t *testing.T) {
+	problems, err := ValidateConfig(`
+[custom_commands.show_file]
+args = ["file", "show", "$file"]
+show = "diff"
+
+[custom_commands.bad]
+args = ["op", "show", "$operation_id", "-r", "$change_id"]
+shw = "interactive"
+
+[custom_commands.filter]
+revset = "files($file)"
+
+[custom_commands.popup]
+args = ["log"]
+show = "popup"
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []config.Problem{
+		{Line: 6, Message: "custom command bad uses $operation_id together with $change_id and can never apply to a selection"},
+		{Line: 8, Message: `unknown key "shw" in custom_commands.bad`},
+		{Line: 10, Message: "custom command filter changes the revset from a revision and can never use $file"},
+		{Line: 15, Message: `invalid value for 'show': "popup". Allowed: none, interactive and diff`},
+	}, problems)
+}
+
+func TestValidateConfig_Leader(t *testing.T) {
+	problems, err := ValidateConfig(`
+[leader.g]
+help = "Git"
+sned = ["gf"]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []config.Problem{{Line: 4, Message: `unknown key "sned" in leader.g`}}, problems)
+}
+
+func TestValidateConfig_LeaderConditions(t *testing.T) {
+	problems, err := ValidateConfig(`
+[leader.n]
+lua = "jj('new')"
+when = { item = "change", mod = "normal" }
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []config.Problem{
+		{Line: 4, Message: `unknown key "mod" in leader.n.when`},
+		{Line: 4, Message: `unknown item "change" in leader.n.when, expected one of revision, file, operation, commit`},
+	}, problems)
+}
+
+func TestValidateConfig_LuaCommandsAreNotChecked(t *testing.T) {
+	problems, err := ValidateConfig(`
+[custom_commands.lua]
+lua = "jj('op', 'show', '$operation_id', '$change_id')"
+`)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
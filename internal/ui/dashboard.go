@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dashboard"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// repositoryMsg is a message of the commands of the UI of a repository. It is
+// only delivered while that UI is shown, so the UIs left for the dashboard
+// stop refreshing in the background. The messages arriving meanwhile, like the
+// completion of a command that was still running, are queued until the UI is
+// opened again.
+type repositoryMsg struct {
+	index int
+	msg   tea.Msg
+}
+
+// dashboardFrame shows the dashboard or the full UI of one of its
+// repositories. The UIs are kept with their contexts while another one is
+// shown, and the configuration of a repository is loaded again when it is
+// opened.
+type dashboardFrame struct {
+	context        *context.MainContext
+	loader         *context.ConfigLoader
+	dashboard      *dashboard.Model
+	uis            map[int]*Model
+	pending        map[int][]tea.Msg // the messages of the hidden UIs
+	active         int
+	width          int
+	height         int
+	displayContext *render.DisplayContext
+}
+
+func (d *dashboardFrame) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("jjui - dashboard"), d.dashboard.Init())
+}
+
+func (d *dashboardFrame) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.width, d.height = msg.Width, msg.Height
+	case dashboard.OpenMsg:
+		return d.open(msg.Index)
+	case repositoryMsg:
+		_, showDashboard := msg.msg.(common.ShowDashboardMsg)
+		if msg.index != d.active {
+			if !showDashboard {
+				d.pending[msg.index] = append(d.pending[msg.index], msg.msg)
+			}
+			return nil
+		}
+		if showDashboard {
+			return d.close()
+		}
+		return tagCmd(d.active, d.uis[d.active].Update(msg.msg))
+	}
+
+	if ui, ok := d.uis[d.active]; ok {
+		cmd := tagCmd(d.active, ui.Update(msg))
+		if common.IsInputMessage(msg) {
+			return cmd
+		}
+		return tea.Batch(cmd, d.dashboard.Update(msg))
+	}
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		if d.displayContext != nil {
+			if interactionMsg, handled := d.displayContext.ProcessMouseEvent(msg); handled && interactionMsg != nil {
+				return func() tea.Msg { return interactionMsg }
+			}
+		}
+		return nil
+	}
+	return d.dashboard.Update(msg)
+}
+
+func (d *dashboardFrame) open(index int) tea.Cmd {
+	repository := d.dashboard.Repository(index)
+	if err := repository.Loader.Load(repository.Context); err != nil {
+		d.dashboard.SetError(index, fmt.Errorf("couldn't load the configuration: %w", err))
+		return nil
+	}
+	d.active = index
+	size := tea.WindowSizeMsg{Width: d.width, Height: d.height}
+	ui, ok := d.uis[index]
+	if !ok {
+		ui = NewUI(repository.Context)
+		d.uis[index] = ui
+		ui.Update(size)
+		return tagCmd(index, ui.Init())
+	}
+	ui.Update(size)
+	cmds := []tea.Cmd{tagCmd(index, ui.resume())}
+	// the ticks of the previous visit are dropped since resume started a new
+	// generation of them
+	for _, msg := range d.pending[index] {
+		cmds = append(cmds, tagCmd(index, ui.Update(msg)))
+	}
+	delete(d.pending, index)
+	return tea.Batch(cmds...)
+}
+
+func (d *dashboardFrame) close() tea.Cmd {
+	index := d.active
+	d.active = -1
+	// the dashboard is shown with the configuration it was started with
+	_ = d.loader.Load(d.context)
+	return tea.Batch(tea.SetWindowTitle("jjui - dashboard"), d.dashboard.Refresh(index))
+}
+
+func (d *dashboardFrame) View() string {
+	if ui, ok := d.uis[d.active]; ok {
+		return ui.View()
+	}
+	if d.width == 0 || d.height == 0 {
+		return ""
+	}
+	d.displayContext = render.NewDisplayContext()
+	box := layout.NewBox(cellbuf.Rect(0, 0, d.width, d.height))
+	d.dashboard.ViewRect(d.displayContext, box)
+	screenBuf := cellbuf.NewBuffer(d.width, d.height)
+	d.displayContext.Render(screenBuf)
+	return strings.ReplaceAll(cellbuf.Render(screenBuf), "\r", "")
+}
+
+var cmdType = reflect.TypeOf(tea.Cmd(nil))
+
+// tagCmd marks the messages of cmd as coming from the UI at index
+func tagCmd(index int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return tagMsg(index, cmd())
+	}
+}
+
+func tagMsg(index int, msg tea.Msg) tea.Msg {
+	switch msg := msg.(type) {
+	case nil:
+		return nil
+	case tea.BatchMsg:
+		cmds := make(tea.BatchMsg, len(msg))
+		for i, cmd := range msg {
+			cmds[i] = tagCmd(index, cmd)
+		}
+		return cmds
+	}
+	value := reflect.ValueOf(msg)
+	// tea.Sequence wraps its commands in an unexported slice type
+	if value.Kind() == reflect.Slice && value.Type().Elem() == cmdType {
+		cmds := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := range value.Len() {
+			cmds.Index(i).Set(reflect.ValueOf(tagCmd(index, value.Index(i).Interface().(tea.Cmd))))
+		}
+		return cmds.Interface()
+	}
+	// the messages of bubbletea itself, like setting the window title, are
+	// for the program
+	if value.Type().PkgPath() == cmdType.PkgPath() {
+		return msg
+	}
+	return repositoryMsg{index: index, msg: msg}
+}
+
+// NewDashboard returns the program showing the dashboard of the repositories.
+// c and loader are the context and configuration of the dashboard itself.
+func NewDashboard(c *context.MainContext, loader *context.ConfigLoader, repositories []dashboard.Repository) tea.Model {
+	for _, repository := range repositories {
+		if repository.Context != nil {
+			repository.Context.InDashboard = true
+		}
+	}
+	return &wrapper{ui: &dashboardFrame{
+		context:   c,
+		loader:    loader,
+		dashboard: dashboard.New(repositories),
+		uis:       make(map[int]*Model),
+		pending:   make(map[int][]tea.Msg),
+		active:    -1,
+	}}
+}
@@ -0,0 +1,294 @@
+package dashboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+// rowHeight is the number of lines every repository takes in the list
+const rowHeight = 2
+
+// Repository is a repository listed in the dashboard. Context and Loader are
+// nil when the repository couldn't be opened, Err tells why.
+type Repository struct {
+	Name    string
+	Context *context.MainContext
+	Loader  *context.ConfigLoader
+	Err     error
+}
+
+func (r Repository) title() string {
+	if r.Context == nil {
+		return r.Name
+	}
+	return filepath.Base(r.Context.Location)
+}
+
+// OpenMsg asks for the full UI of the repository at Index
+type OpenMsg struct {
+	Index int
+}
+
+type statusMsg struct {
+	index  int
+	status jj.RepositoryStatus
+	err    error
+}
+
+type rowClickedMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type repositoryStatus struct {
+	status  jj.RepositoryStatus
+	loading bool
+	err     error
+}
+
+type styles struct {
+	title    lipgloss.Style
+	name     lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+	changeId lipgloss.Style
+	conflict lipgloss.Style
+	bookmark lipgloss.Style
+	err      lipgloss.Style
+}
+
+// Model lists the repositories with their working copy, conflicts, divergent
+// changes and bookmarks that are not pushed.
+type Model struct {
+	repositories        []Repository
+	statuses            []repositoryStatus
+	cursor              int
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+func (m *Model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.repositories {
+		cmds = append(cmds, m.Refresh(i))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Refresh loads the status of the repository at index again
+func (m *Model) Refresh(index int) tea.Cmd {
+	if index < 0 || index >= len(m.repositories) {
+		return nil
+	}
+	repository := m.repositories[index]
+	if repository.Context == nil {
+		m.statuses[index] = repositoryStatus{err: repository.Err}
+		return nil
+	}
+	m.statuses[index].loading = true
+	return func() tea.Msg {
+		output, err := repository.Context.RunCommandImmediate(jj.RepoStatus())
+		if err != nil {
+			return statusMsg{index: index, err: err}
+		}
+		return statusMsg{index: index, status: jj.ParseRepoStatus(string(output))}
+	}
+}
+
+// SetError shows err in place of the status of the repository at index
+func (m *Model) SetError(index int, err error) {
+	if index >= 0 && index < len(m.statuses) {
+		m.statuses[index] = repositoryStatus{err: err}
+	}
+}
+
+func (m *Model) Repository(index int) Repository {
+	return m.repositories[index]
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case statusMsg:
+		if msg.index >= 0 && msg.index < len(m.statuses) {
+			m.statuses[msg.index] = repositoryStatus{status: msg.status, err: msg.err}
+		}
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.move(1)
+		case key.Matches(msg, m.keymap.Apply):
+			return m.open(m.cursor)
+		case key.Matches(msg, m.keymap.Refresh):
+			return m.Init()
+		case key.Matches(msg, m.keymap.Quit):
+			return tea.Quit
+		}
+	case rowClickedMsg:
+		if msg.Index == m.cursor {
+			return m.open(msg.Index)
+		}
+		if msg.Index >= 0 && msg.Index < len(m.repositories) {
+			m.cursor = msg.Index
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.ensureCursorVisible = false
+		m.listRenderer.SetScrollOffset(max(0, m.listRenderer.GetScrollOffset()+msg.Delta))
+	}
+	return nil
+}
+
+func (m *Model) move(delta int) {
+	next := m.cursor + delta
+	if next < 0 || next >= len(m.repositories) {
+		return
+	}
+	m.cursor = next
+	m.ensureCursorVisible = true
+}
+
+func (m *Model) open(index int) tea.Cmd {
+	if index < 0 || index >= len(m.repositories) || m.repositories[index].Context == nil {
+		return nil
+	}
+	return func() tea.Msg { return OpenMsg{Index: index} }
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	if box.R.Dx() <= 2 || box.R.Dy() <= 3 {
+		return
+	}
+	dl.AddFill(box.R, ' ', m.styles.text, render.ZBase)
+	titleBox, listBox := box.CutTop(1)
+	listBox, helpBox := listBox.CutBottom(1)
+	dl.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZBase).
+		Styled(fmt.Sprintf("Repositories (%d)", len(m.repositories)), m.styles.title).
+		Done()
+
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.repositories),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return rowHeight },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			m.renderRow(dl, index, rect)
+		},
+		func(index int) tea.Msg { return rowClickedMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+
+	help := []string{
+		m.keymap.Apply.Help().Key + " open",
+		m.keymap.Refresh.Help().Key + " refresh",
+		m.keymap.Quit.Help().Key + " quit",
+	}
+	dl.Text(helpBox.R.Min.X, helpBox.R.Min.Y, render.ZBase).Styled(strings.Join(help, " • "), m.styles.dimmed).Done()
+}
+
+func (m *Model) renderRow(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+	repository := m.repositories[index]
+	tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZBase).Styled(repository.title(), m.styles.name)
+	if repository.Context != nil {
+		tb.Space(2).Styled(repository.Context.Location, m.styles.dimmed)
+	}
+	tb.Done()
+
+	tb = dl.Text(rect.Min.X+2, rect.Min.Y+1, render.ZBase)
+	switch s := m.statuses[index]; {
+	case s.err != nil:
+		tb.Styled(firstLine(s.err.Error()), m.styles.err)
+	case s.loading:
+		tb.Styled("Loading...", m.styles.dimmed)
+	default:
+		m.renderStatus(tb, s.status)
+	}
+	tb.Done()
+
+	if index == m.cursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZBase+1)
+	}
+}
+
+func (m *Model) renderStatus(tb *render.TextBuilder, status jj.RepositoryStatus) {
+	tb.Styled("@ ", m.styles.text).Styled(status.WorkingCopy, m.styles.changeId).Space(1)
+	if status.WorkingCopyDescription == "" {
+		tb.Styled("(no description set)", m.styles.dimmed)
+	} else {
+		tb.Styled(status.WorkingCopyDescription, m.styles.text)
+	}
+	if n := len(status.Conflicts); n > 0 {
+		tb.Space(2).Styled(plural(n, "conflict"), m.styles.conflict)
+	}
+	if n := len(status.Divergent); n > 0 {
+		tb.Space(2).Styled(fmt.Sprintf("%d divergent", n), m.styles.conflict)
+	}
+	if len(status.UnpushedBookmarks) > 0 {
+		tb.Space(2).Styled("unpushed: ", m.styles.dimmed).Styled(strings.Join(status.UnpushedBookmarks, ", "), m.styles.bookmark)
+	}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+func New(repositories []Repository) *Model {
+	return &Model{
+		repositories: repositories,
+		statuses:     make([]repositoryStatus, len(repositories)),
+		keymap:       config.Current.GetKeyMap(),
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+		styles: styles{
+			title:    common.DefaultPalette.Get("dashboard title"),
+			name:     common.DefaultPalette.Get("dashboard name"),
+			text:     common.DefaultPalette.Get("dashboard text"),
+			dimmed:   common.DefaultPalette.Get("dashboard dimmed"),
+			selected: common.DefaultPalette.Get("dashboard selected"),
+			changeId: common.DefaultPalette.Get("dashboard change_id"),
+			conflict: common.DefaultPalette.Get("dashboard conflict"),
+			bookmark: common.DefaultPalette.Get("dashboard bookmark"),
+			err:      common.DefaultPalette.Get("dashboard error"),
+		},
+	}
+}
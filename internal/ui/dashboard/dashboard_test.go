@@ -0,0 +1,57 @@
+package dashboard
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit_ShowsStatusOfRepositories(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.RepoStatus()).
+		SetOutput([]byte("@\tkxqyvtmn\t\t\twork in progress\nx\tzsuskuln\tfeature\t\tmerge\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Location = "/src/jjui"
+
+	model := New([]Repository{
+		{Name: "~/src/jjui", Context: ctx},
+		{Name: "~/src/missing", Err: errors.New("There is no jj repo in \"~/src/missing\"")},
+	})
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 120, 10))
+	assert.Contains(t, rendered, "Repositories (2)")
+	assert.Contains(t, rendered, "jjui  /src/jjui")
+	assert.Contains(t, rendered, "@ kxqyvtmn work in progress  1 conflict  unpushed: feature")
+	assert.Contains(t, rendered, "~/src/missing")
+	assert.Contains(t, rendered, "There is no jj repo")
+}
+
+func TestApply_OpensSelectedRepository(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.RepoStatus())
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New([]Repository{{Name: "a", Context: ctx}, {Name: "b", Context: ctx}})
+	test.SimulateModel(model, model.Init())
+
+	var opened tea.Msg
+	test.SimulateModel(model, test.Type("j"))
+	test.SimulateModel(model, model.Update(tea.KeyMsg{Type: tea.KeyEnter}), func(msg tea.Msg) {
+		opened = msg
+	})
+	assert.Equal(t, OpenMsg{Index: 1}, opened)
+}
+
+func TestApply_IgnoresRepositoryThatCouldNotBeOpened(t *testing.T) {
+	model := New([]Repository{{Name: "missing", Err: errors.New("no repo")}})
+	test.SimulateModel(model, model.Init())
+
+	assert.Nil(t, model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/dashboard"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Update_QuitInDashboardShowsDashboard(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	ctx.InDashboard = true
+
+	model := NewUI(ctx)
+	var msgs []tea.Msg
+	test.SimulateModel(model, test.Press(tea.KeyEsc), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+
+	assert.Contains(t, msgs, common.ShowDashboardMsg{})
+	assert.NotContains(t, msgs, tea.QuitMsg{})
+}
+
+func Test_TagMsg(t *testing.T) {
+	assert.Equal(t, repositoryMsg{index: 2, msg: common.RefreshMsg{}}, tagMsg(2, common.RefreshMsg{}))
+	assert.Equal(t, tea.QuitMsg{}, tagMsg(2, tea.QuitMsg{}), "program messages are not tagged")
+	assert.Nil(t, tagMsg(2, nil))
+
+	batch, ok := tagMsg(2, tea.BatchMsg{common.Close}).(tea.BatchMsg)
+	assert.True(t, ok)
+	assert.Equal(t, repositoryMsg{index: 2, msg: common.CloseViewMsg{}}, batch[0]())
+
+	sequence := tagMsg(2, tea.Sequence(common.Close, common.Close)())
+	var msgs []tea.Msg
+	test.SimulateModel(&recorder{}, func() tea.Msg { return sequence }, func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Equal(t, []tea.Msg{
+		repositoryMsg{index: 2, msg: common.CloseViewMsg{}},
+		repositoryMsg{index: 2, msg: common.CloseViewMsg{}},
+	}, msgs)
+}
+
+func Test_DashboardFrame_QueuesMessagesOfHiddenRepositories(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	frame := &dashboardFrame{
+		dashboard: dashboard.New(nil),
+		uis:       map[int]*Model{0: NewUI(ctx)},
+		pending:   make(map[int][]tea.Msg),
+		active:    -1,
+	}
+	completed := common.CommandCompletedMsg{Output: "done"}
+	assert.Nil(t, frame.Update(repositoryMsg{index: 0, msg: completed}))
+	assert.Nil(t, frame.Update(repositoryMsg{index: 0, msg: common.ShowDashboardMsg{}}))
+	assert.Equal(t, []tea.Msg{completed}, frame.pending[0])
+}
+
+func Test_Resume_DropsTicksOfPreviousVisit(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := NewUI(ctx)
+
+	stale := triggerAutoRefreshMsg{generation: model.generation}
+	model.resume()
+	assert.Nil(t, model.Update(stale))
+	assert.Nil(t, model.Update(checkConfigMsg{generation: stale.generation}))
+}
+
+type recorder struct{}
+
+func (r *recorder) Update(tea.Msg) tea.Cmd { return nil }
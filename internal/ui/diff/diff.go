@@ -1,12 +1,17 @@
 package diff
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
@@ -15,9 +20,89 @@ import (
 
 var _ common.ImmediateModel = (*Model)(nil)
 
+const (
+	horizontalStep = 6
+	tabWidth       = 4
+)
+
+type rowKind int
+
+const (
+	// rowRaw is a line of output that couldn't be parsed as a git diff
+	rowRaw rowKind = iota
+	rowFile
+	rowHunk
+	rowLine
+)
+
+// row is a single line on the screen. left and right are indexes into the
+// lines of the hunk; in unified mode only left is used.
+type row struct {
+	kind  rowKind
+	file  int
+	hunk  int
+	left  int
+	right int
+	raw   string
+}
+
+type lineSide int
+
+const (
+	sideBoth lineSide = iota
+	sideOld
+	sideNew
+)
+
+type fileView struct {
+	jj.FileDiff
+	// spans holds the intra-line changes for each line of each hunk
+	spans  [][][]span
+	folded bool
+}
+
+type styles struct {
+	text        lipgloss.Style
+	dimmed      lipgloss.Style
+	file        lipgloss.Style
+	hunk        lipgloss.Style
+	added       lipgloss.Style
+	removed     lipgloss.Style
+	modified    lipgloss.Style
+	addedWord   lipgloss.Style
+	removedWord lipgloss.Style
+}
+
+func newStyles() styles {
+	return styles{
+		text:        common.DefaultPalette.Get("diff text"),
+		dimmed:      common.DefaultPalette.Get("diff dimmed"),
+		file:        common.DefaultPalette.Get("diff file"),
+		hunk:        common.DefaultPalette.Get("diff hunk"),
+		added:       common.DefaultPalette.Get("diff added"),
+		removed:     common.DefaultPalette.Get("diff removed"),
+		modified:    common.DefaultPalette.Get("diff modified"),
+		addedWord:   common.DefaultPalette.Get("diff added word"),
+		removedWord: common.DefaultPalette.Get("diff removed word"),
+	}
+}
+
 type Model struct {
-	view   viewport.Model
-	keymap config.KeyMappings[key.Binding]
+	files       []fileView
+	raw         []string
+	rows        []row
+	sideBySide  bool
+	yOffset     int
+	xOffset     int
+	height      int
+	numberWidth int
+	keymap      config.KeyMappings[key.Binding]
+	styles      styles
+}
+
+// FileClickedMsg toggles the fold of a file when its header is clicked
+type FileClickedMsg struct {
+	Index int
 }
 
 func (m *Model) ShortHelp() []key.Binding {
@@ -28,6 +113,12 @@ func (m *Model) ShortHelp() []key.Binding {
 		m.keymap.DiffView.HalfPageUp,
 		m.keymap.DiffView.PageDown,
 		m.keymap.DiffView.PageUp,
+		m.keymap.DiffView.NextFile,
+		m.keymap.DiffView.PrevFile,
+		m.keymap.DiffView.NextHunk,
+		m.keymap.DiffView.PrevHunk,
+		m.keymap.DiffView.ToggleFold,
+		m.keymap.DiffView.SideBySide,
 		m.keymap.Quit,
 		m.keymap.DiffView.Close,
 	}
@@ -42,18 +133,25 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) SetHeight(h int) {
-	m.view.Height = h
+	m.height = h
+	m.clampOffset()
 }
 
 func (m *Model) Scroll(delta int) tea.Cmd {
-	if delta > 0 {
-		m.view.ScrollDown(delta)
-	} else if delta < 0 {
-		m.view.ScrollUp(-delta)
-	}
+	m.yOffset += delta
+	m.clampOffset()
 	return nil
 }
 
+func (m *Model) ScrollHorizontal(delta int) {
+	m.xOffset = max(0, m.xOffset+delta)
+}
+
+func (m *Model) clampOffset() {
+	m.yOffset = min(m.yOffset, len(m.rows)-m.height)
+	m.yOffset = max(m.yOffset, 0)
+}
+
 type ScrollMsg struct {
 	Delta      int
 	Horizontal bool
@@ -67,53 +165,326 @@ func (s ScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
+	case common.ConfigReloadedMsg:
+		m.keymap = config.Current.GetKeyMap()
+		m.styles = newStyles()
 	case tea.KeyMsg:
+		km := m.keymap.DiffView
 		switch {
-		case key.Matches(msg, m.keymap.DiffView.Close):
+		case key.Matches(msg, km.Close):
 			return common.Close
 		case key.Matches(msg, m.keymap.Quit):
 			return tea.Quit
 		case key.Matches(msg, m.keymap.ExpandStatus):
 			return func() tea.Msg { return intents.ExpandStatusToggle{} }
+		case key.Matches(msg, km.ScrollUp):
+			return m.Scroll(-1)
+		case key.Matches(msg, km.ScrollDown):
+			return m.Scroll(1)
+		case key.Matches(msg, km.HalfPageUp):
+			return m.Scroll(-max(1, m.height/2))
+		case key.Matches(msg, km.HalfPageDown):
+			return m.Scroll(max(1, m.height/2))
+		case key.Matches(msg, km.PageUp):
+			return m.Scroll(-max(1, m.height))
+		case key.Matches(msg, km.PageDown):
+			return m.Scroll(max(1, m.height))
+		case key.Matches(msg, km.Left):
+			m.ScrollHorizontal(-horizontalStep)
+		case key.Matches(msg, km.Right):
+			m.ScrollHorizontal(horizontalStep)
+		case key.Matches(msg, km.NextFile):
+			m.jumpTo(rowFile, 1)
+		case key.Matches(msg, km.PrevFile):
+			m.jumpTo(rowFile, -1)
+		case key.Matches(msg, km.NextHunk):
+			m.jumpTo(rowHunk, 1)
+		case key.Matches(msg, km.PrevHunk):
+			m.jumpTo(rowHunk, -1)
+		case key.Matches(msg, km.ToggleFold):
+			if len(m.rows) > 0 && m.rows[m.yOffset].kind != rowRaw {
+				m.toggleFold(m.rows[m.yOffset].file)
+			}
+		case key.Matches(msg, km.SideBySide):
+			m.ToggleSideBySide()
 		}
+	case FileClickedMsg:
+		m.toggleFold(msg.Index)
 	case ScrollMsg:
 		if msg.Horizontal {
+			m.ScrollHorizontal(msg.Delta)
 			return nil
 		}
 		return m.Scroll(msg.Delta)
 	}
-	var cmd tea.Cmd
-	m.view, cmd = m.view.Update(msg)
-	return cmd
+	return nil
+}
+
+// ToggleSideBySide switches between the unified and the side-by-side layout,
+// keeping the file at the top of the view in place.
+func (m *Model) ToggleSideBySide() {
+	if len(m.files) == 0 {
+		return
+	}
+	file := m.rows[m.yOffset].file
+	m.sideBySide = !m.sideBySide
+	m.buildRows()
+	m.yOffset = m.fileRow(file)
+	m.clampOffset()
+}
+
+func (m *Model) toggleFold(file int) {
+	if file < 0 || file >= len(m.files) {
+		return
+	}
+	m.files[file].folded = !m.files[file].folded
+	m.buildRows()
+	m.yOffset = m.fileRow(file)
+	m.clampOffset()
+}
+
+// jumpTo moves the top of the view to the next (or previous) row of the given kind
+func (m *Model) jumpTo(kind rowKind, direction int) {
+	for i := m.yOffset + direction; i >= 0 && i < len(m.rows); i += direction {
+		if m.rows[i].kind == kind {
+			m.yOffset = i
+			m.clampOffset()
+			return
+		}
+	}
+}
+
+func (m *Model) fileRow(file int) int {
+	for i, r := range m.rows {
+		if r.kind == rowFile && r.file == file {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m *Model) buildRows() {
+	m.rows = m.rows[:0]
+	if len(m.files) == 0 {
+		for _, line := range m.raw {
+			m.rows = append(m.rows, row{kind: rowRaw, raw: line})
+		}
+		return
+	}
+	for fi, file := range m.files {
+		m.rows = append(m.rows, row{kind: rowFile, file: fi})
+		if file.folded {
+			continue
+		}
+		for hi, hunk := range file.Hunks {
+			m.rows = append(m.rows, row{kind: rowHunk, file: fi, hunk: hi})
+			if !m.sideBySide {
+				for li := range hunk.Lines {
+					m.rows = append(m.rows, row{kind: rowLine, file: fi, hunk: hi, left: li, right: -1})
+				}
+				continue
+			}
+			m.appendSideBySideRows(fi, hi, hunk.Lines)
+		}
+	}
+}
+
+// appendSideBySideRows lines up removed lines on the left with added lines on the right.
+// Context lines appear on both sides.
+func (m *Model) appendSideBySideRows(file int, hunk int, lines []jj.DiffLine) {
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == jj.DiffLineContext {
+			m.rows = append(m.rows, row{kind: rowLine, file: file, hunk: hunk, left: i, right: i})
+			i++
+			continue
+		}
+		var removed, added []int
+		for i < len(lines) && lines[i].Kind == jj.DiffLineRemoved {
+			removed = append(removed, i)
+			i++
+		}
+		for i < len(lines) && lines[i].Kind == jj.DiffLineAdded {
+			added = append(added, i)
+			i++
+		}
+		for j := 0; j < max(len(removed), len(added)); j++ {
+			r := row{kind: rowLine, file: file, hunk: hunk, left: -1, right: -1}
+			if j < len(removed) {
+				r.left = removed[j]
+			}
+			if j < len(added) {
+				r.right = added[j]
+			}
+			m.rows = append(m.rows, r)
+		}
+	}
 }
 
 func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
-	m.view.Height = box.R.Dy()
-	m.view.Width = box.R.Dx()
-	dl.AddDraw(box.R, m.view.View(), 0)
+	m.height = box.R.Dy()
+	m.clampOffset()
+	width := box.R.Dx()
+	for i := 0; i < m.height && m.yOffset+i < len(m.rows); i++ {
+		r := m.rows[m.yOffset+i]
+		rect := cellbuf.Rect(box.R.Min.X, box.R.Min.Y+i, width, 1)
+		switch r.kind {
+		case rowRaw:
+			dl.AddDraw(rect, ansi.Cut(r.raw, m.xOffset, m.xOffset+width), 0)
+		case rowFile:
+			m.renderFileHeader(dl, rect, r.file)
+		case rowHunk:
+			header := m.files[r.file].Hunks[r.hunk].Header
+			dl.AddFill(rect, ' ', m.styles.text, 0)
+			dl.Text(rect.Min.X, rect.Min.Y, 0).Styled(ansi.Truncate(header, width, ""), m.styles.hunk).Done()
+		case rowLine:
+			if m.sideBySide {
+				half := (width - 1) / 2
+				m.renderLine(dl, cellbuf.Rect(rect.Min.X, rect.Min.Y, half, 1), r, r.left, sideOld)
+				dl.Text(rect.Min.X+half, rect.Min.Y, 0).Styled("│", m.styles.dimmed).Done()
+				m.renderLine(dl, cellbuf.Rect(rect.Min.X+half+1, rect.Min.Y, width-half-1, 1), r, r.right, sideNew)
+			} else {
+				m.renderLine(dl, rect, r, r.left, sideBoth)
+			}
+		}
+	}
 	dl.AddInteraction(box.R, ScrollMsg{}, render.InteractionScroll, 0)
 }
 
+func (m *Model) renderFileHeader(dl *render.DisplayContext, rect cellbuf.Rectangle, index int) {
+	file := m.files[index]
+	marker := "▾ "
+	if file.folded {
+		marker = "▸ "
+	}
+	statusStyle := m.styles.modified
+	status := "M"
+	path := file.Path()
+	switch file.Status {
+	case jj.FileAdded:
+		status, statusStyle = "A", m.styles.added
+	case jj.FileDeleted:
+		status, statusStyle = "D", m.styles.removed
+	case jj.FileRenamed:
+		status, path = "R", fmt.Sprintf("%s → %s", file.OldPath, file.NewPath)
+	case jj.FileCopied:
+		status, path = "C", fmt.Sprintf("%s → %s", file.OldPath, file.NewPath)
+	}
+
+	dl.AddFill(rect, ' ', m.styles.text, 0)
+	tb := dl.Text(rect.Min.X, rect.Min.Y, 0).
+		Styled(marker, m.styles.file).
+		Styled(status+" ", statusStyle).
+		Styled(path, m.styles.file)
+	if file.Binary {
+		tb.Styled(" (binary)", m.styles.dimmed)
+	}
+	added, removed := file.Stats()
+	if added > 0 {
+		tb.Styled(fmt.Sprintf(" +%d", added), m.styles.added)
+	}
+	if removed > 0 {
+		tb.Styled(fmt.Sprintf(" -%d", removed), m.styles.removed)
+	}
+	tb.Done()
+	dl.AddInteraction(rect, FileClickedMsg{Index: index}, render.InteractionClick, 0)
+}
+
+// renderLine draws a diff line with the line numbers of the given side
+func (m *Model) renderLine(dl *render.DisplayContext, rect cellbuf.Rectangle, r row, index int, side lineSide) {
+	dl.AddFill(rect, ' ', m.styles.text, 0)
+	if index < 0 || rect.Dx() <= 0 {
+		return
+	}
+	file := m.files[r.file]
+	line := file.Hunks[r.hunk].Lines[index]
+	spans := file.spans[r.hunk][index]
+
+	number := func(n int) string {
+		if n == 0 {
+			return strings.Repeat(" ", m.numberWidth)
+		}
+		return fmt.Sprintf("%*d", m.numberWidth, n)
+	}
+	var gutter string
+	switch side {
+	case sideOld:
+		gutter = number(line.OldNumber) + " "
+	case sideNew:
+		gutter = number(line.NewNumber) + " "
+	default:
+		gutter = number(line.OldNumber) + " " + number(line.NewNumber) + " "
+	}
+
+	style, wordStyle, sign := m.styles.text, m.styles.text, " "
+	switch line.Kind {
+	case jj.DiffLineAdded:
+		style, wordStyle, sign = m.styles.added, m.styles.addedWord, "+"
+	case jj.DiffLineRemoved:
+		style, wordStyle, sign = m.styles.removed, m.styles.removedWord, "-"
+	}
+
+	tb := dl.Text(rect.Min.X, rect.Min.Y, 0)
+	tb.Styled(ansi.Truncate(gutter, rect.Dx(), ""), m.styles.dimmed)
+	tb.Styled(sign, style)
+
+	// split the content into plain and changed parts, then cut out the
+	// horizontally visible window
+	type part struct {
+		text  string
+		style lipgloss.Style
+	}
+	var parts []part
+	pos := 0
+	for _, s := range spans {
+		if s.start > pos {
+			parts = append(parts, part{line.Content[pos:s.start], style})
+		}
+		parts = append(parts, part{line.Content[s.start:s.end], wordStyle})
+		pos = s.end
+	}
+	if pos < len(line.Content) {
+		parts = append(parts, part{line.Content[pos:], style})
+	}
+
+	available := rect.Dx() - ansi.StringWidth(gutter) - 1
+	col := 0
+	for _, p := range parts {
+		text := strings.ReplaceAll(p.text, "\t", strings.Repeat(" ", tabWidth))
+		w := ansi.StringWidth(text)
+		from, to := max(0, m.xOffset-col), min(w, m.xOffset+available-col)
+		if from < to {
+			tb.Styled(ansi.Cut(text, from, to), p.style)
+		}
+		col += w
+	}
+	tb.Done()
+}
+
 func New(output string) *Model {
-	view := viewport.New(0, 0)
 	content := strings.ReplaceAll(output, "\r", "")
-	if content == "" {
-		content = "(empty)"
-	}
-	view.SetContent(content)
-	km := config.Current.GetKeyMap()
-	view.KeyMap = viewport.KeyMap{
-		Up:           km.DiffView.ScrollUp,
-		Down:         km.DiffView.ScrollDown,
-		PageUp:       km.DiffView.PageUp,
-		PageDown:     km.DiffView.PageDown,
-		HalfPageUp:   km.DiffView.HalfPageUp,
-		HalfPageDown: km.DiffView.HalfPageDown,
-		Left:         km.DiffView.Left,
-		Right:        km.DiffView.Right,
-	}
-	return &Model{
-		view:   view,
-		keymap: km,
+	m := &Model{
+		keymap: config.Current.GetKeyMap(),
+		styles: newStyles(),
+	}
+
+	maxNumber := 0
+	for _, file := range jj.ParseGitDiff(content) {
+		view := fileView{FileDiff: file}
+		for _, hunk := range file.Hunks {
+			view.spans = append(view.spans, pairSpans(hunk.Lines))
+			maxNumber = max(maxNumber, hunk.OldStart+hunk.OldCount, hunk.NewStart+hunk.NewCount)
+		}
+		m.files = append(m.files, view)
+	}
+	m.numberWidth = len(strconv.Itoa(maxNumber))
+
+	if len(m.files) == 0 {
+		content = strings.TrimSuffix(content, "\n")
+		if content == "" {
+			content = "(empty)"
+		}
+		m.raw = strings.Split(content, "\n")
 	}
+	m.buildRows()
+	return m
 }
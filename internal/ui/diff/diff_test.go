@@ -23,10 +23,10 @@ func TestScroll_AdjustsViewportOffset(t *testing.T) {
 	model := New(content)
 
 	model.Scroll(2)
-	assert.Equal(t, 2, model.view.YOffset)
+	assert.Equal(t, 2, model.yOffset)
 
 	model.Scroll(-1)
-	assert.Equal(t, 1, model.view.YOffset)
+	assert.Equal(t, 1, model.yOffset)
 }
 
 func TestUpdate_CancelReturnsClose(t *testing.T) {
@@ -40,3 +40,80 @@ func TestUpdate_CancelReturnsClose(t *testing.T) {
 
 	assert.Contains(t, msgs, common.CloseViewMsg{})
 }
+
+const gitDiff = `diff --git a/a.txt b/a.txt
+index 1111111..2222222 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ keep
+-hello world
++hello there
+diff --git a/b.txt b/b.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/b.txt
+@@ -0,0 +1 @@
++new
+`
+
+func TestNew_RendersParsedGitDiff(t *testing.T) {
+	model := New(gitDiff)
+	rendered := test.Stripped(test.RenderImmediate(model, 40, 10))
+	assert.Equal(t, `▾ M a.txt +1 -1
+@@ -1,2 +1,2 @@
+1 1  keep
+2   -hello world
+2 +hello there
+▾ A b.txt +1
+@@ -0,0 +1 @@
+1 +new`, rendered)
+}
+
+func TestSideBySide_PairsRemovedAndAddedLines(t *testing.T) {
+	model := New(gitDiff)
+	model.ToggleSideBySide()
+	rendered := test.Stripped(test.RenderImmediate(model, 41, 4))
+	assert.Equal(t, `▾ M a.txt +1 -1
+@@ -1,2 +1,2 @@
+1  keep             │1  keep
+2 -hello world      │2 +hello there`, rendered)
+}
+
+func TestNavigation_JumpsBetweenFilesAndHunks(t *testing.T) {
+	model := New(gitDiff)
+	model.SetHeight(2)
+
+	test.SimulateModel(model, test.Type("]"))
+	assert.Equal(t, 5, model.yOffset)
+
+	test.SimulateModel(model, test.Type("["))
+	assert.Equal(t, 0, model.yOffset)
+
+	test.SimulateModel(model, test.Type("nn"))
+	assert.Equal(t, 6, model.yOffset)
+}
+
+func TestToggleFold_HidesFileContent(t *testing.T) {
+	model := New(gitDiff)
+	test.SimulateModel(model, test.Press(tea.KeyTab))
+	rendered := test.Stripped(test.RenderImmediate(model, 40, 10))
+	assert.Equal(t, `▸ M a.txt +1 -1
+▾ A b.txt +1
+@@ -0,0 +1 @@
+1 +new`, rendered)
+
+	test.SimulateModel(model, func() tea.Msg { return FileClickedMsg{Index: 0} })
+	assert.Len(t, model.rows, 8)
+}
+
+func TestWordDiff_HighlightsChangedWords(t *testing.T) {
+	oldSpans, newSpans := wordDiff("hello world", "hello there")
+	assert.Equal(t, []span{{start: 6, end: 11}}, oldSpans)
+	assert.Equal(t, []span{{start: 6, end: 11}}, newSpans)
+
+	oldSpans, newSpans = wordDiff("foo", "bar")
+	assert.Nil(t, oldSpans)
+	assert.Nil(t, newSpans)
+}
@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/idursun/jjui/internal/jj"
+)
+
+// maxWordDiffCells caps the size of the LCS table so very long lines don't stall rendering.
+const maxWordDiffCells = 250_000
+
+// span marks a changed byte range [start, end) of a line.
+type span struct {
+	start int
+	end   int
+}
+
+// tokenize splits a line into runs of word characters, runs of whitespace and
+// single punctuation characters. The returned values are the byte offsets where
+// each token starts, with a final entry for the end of the line.
+func tokenize(s string) []int {
+	var bounds []int
+	kind := -1
+	for i, r := range s {
+		k := 2
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			k = 0
+		case unicode.IsSpace(r):
+			k = 1
+		}
+		if k != kind || k == 2 {
+			bounds = append(bounds, i)
+		}
+		kind = k
+	}
+	return append(bounds, len(s))
+}
+
+// wordDiff returns the ranges of old and new that are not part of their longest
+// common token subsequence. It returns nil for both when the lines share nothing
+// but whitespace, in which case the whole line is the change and highlighting
+// words would be noise.
+func wordDiff(old, new string) ([]span, []span) {
+	oldBounds, newBounds := tokenize(old), tokenize(new)
+	n, m := len(oldBounds)-1, len(newBounds)-1
+	if n <= 0 || m <= 0 || (n+1)*(m+1) > maxWordDiffCells {
+		return nil, nil
+	}
+	oldToken := func(i int) string { return old[oldBounds[i]:oldBounds[i+1]] }
+	newToken := func(j int) string { return new[newBounds[j]:newBounds[j+1]] }
+
+	// lcs[i][j] is the LCS length of old[i:] and new[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldToken(i) == newToken(j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var oldSpans, newSpans []span
+	add := func(spans []span, start, end int) []span {
+		if len(spans) > 0 && spans[len(spans)-1].end == start {
+			spans[len(spans)-1].end = end
+			return spans
+		}
+		return append(spans, span{start: start, end: end})
+	}
+	common := 0
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldToken(i) == newToken(j):
+			if r, _ := utf8.DecodeRuneInString(oldToken(i)); !unicode.IsSpace(r) {
+				common++
+			}
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			newSpans = add(newSpans, newBounds[j], newBounds[j+1])
+			j++
+		default:
+			oldSpans = add(oldSpans, oldBounds[i], oldBounds[i+1])
+			i++
+		}
+	}
+	if common == 0 {
+		return nil, nil
+	}
+	return oldSpans, newSpans
+}
+
+// pairSpans computes intra-line changes for a hunk. Each run of removed lines
+// that is directly followed by a run of added lines is paired up line by line.
+func pairSpans(lines []jj.DiffLine) [][]span {
+	spans := make([][]span, len(lines))
+	for i := 0; i < len(lines); {
+		if lines[i].Kind != jj.DiffLineRemoved {
+			i++
+			continue
+		}
+		removedStart := i
+		for i < len(lines) && lines[i].Kind == jj.DiffLineRemoved {
+			i++
+		}
+		addedStart := i
+		for i < len(lines) && lines[i].Kind == jj.DiffLineAdded {
+			i++
+		}
+		pairs := min(addedStart-removedStart, i-addedStart)
+		for p := 0; p < pairs; p++ {
+			oldIndex, newIndex := removedStart+p, addedStart+p
+			spans[oldIndex], spans[newIndex] = wordDiff(lines[oldIndex].Content, lines[newIndex].Content)
+		}
+	}
+	return spans
+}
@@ -0,0 +1,289 @@
+package dryrun
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ help.KeyMap           = (*Model)(nil)
+)
+
+// ShowMsg previews the revisions as they would be after running the command
+type ShowMsg struct {
+	Command jj.CommandArgs
+}
+
+func Show(command jj.CommandArgs) tea.Cmd {
+	return func() tea.Msg {
+		return ShowMsg{Command: command}
+	}
+}
+
+// Result is the outcome of a command that was run and undone
+type Result struct {
+	Graph     string   // jj log of the revisions after the command
+	Conflicts []string // the revisions the command leaves with conflicts
+}
+
+// Run runs the command without touching the working copy, captures the
+// revisions and the conflicts it results in and restores the operation the
+// repository was at. The operation of the command is abandoned afterwards so
+// that undoing the restore doesn't apply the command.
+func Run(ctx *context.MainContext, command jj.CommandArgs) (Result, error) {
+	ctx.Previewing.Store(true)
+	defer ctx.Previewing.Store(false)
+	output, err := ctx.RunCommandImmediate(jj.OpLogId(true))
+	if err != nil {
+		return Result{}, err
+	}
+	before := strings.TrimSpace(string(output))
+	output, err = ctx.RunCommandImmediate(jj.Conflicts())
+	if err != nil {
+		return Result{}, err
+	}
+	conflicted := lines(output)
+
+	var result Result
+	if _, err = ctx.RunCommandImmediate(append(slices.Clone(command), "--ignore-working-copy")); err == nil {
+		result, err = capture(ctx, conflicted)
+	}
+	if restoreErr := restore(ctx, before); restoreErr != nil {
+		return Result{}, restoreErr
+	}
+	return result, err
+}
+
+func capture(ctx *context.MainContext, conflicted []string) (Result, error) {
+	graph, err := ctx.RunCommandImmediate(jj.LogGraph(ctx.CurrentRevset, ctx.LogLimit(), ctx.LogTemplate()))
+	if err != nil {
+		return Result{}, err
+	}
+	output, err := ctx.RunCommandImmediate(jj.Conflicts())
+	if err != nil {
+		return Result{}, err
+	}
+	var conflicts []string
+	for _, line := range lines(output) {
+		if !slices.Contains(conflicted, line) {
+			conflicts = append(conflicts, line)
+		}
+	}
+	return Result{Graph: string(graph), Conflicts: conflicts}, nil
+}
+
+// restore goes back to the operation and abandons the one of the command
+// unless the command failed before creating one
+func restore(ctx *context.MainContext, operationId string) error {
+	output, err := ctx.RunCommandImmediate(jj.OpLogId(false))
+	preview := strings.TrimSpace(string(output))
+	if err == nil && preview == operationId {
+		return nil
+	}
+	if _, err := ctx.RunCommandImmediate(append(jj.OpRestore(operationId), "--ignore-working-copy")); err != nil {
+		return fmt.Errorf("couldn't restore the operation %s, run `jj op restore %s`: %w", operationId, operationId, err)
+	}
+	if err != nil || preview == "" {
+		return nil
+	}
+	if _, err := ctx.RunCommandImmediate(append(jj.OpAbandon(preview), "--ignore-working-copy")); err != nil {
+		return fmt.Errorf("couldn't abandon the operation %s of the preview, run `jj op abandon %s`: %w", preview, preview, err)
+	}
+	return nil
+}
+
+func lines(output []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+type resultMsg struct {
+	result Result
+	err    error
+}
+
+type scrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m scrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type styles struct {
+	border lipgloss.Style
+	title  lipgloss.Style
+	text   lipgloss.Style
+	dimmed lipgloss.Style
+	err    lipgloss.Style
+}
+
+// Model shows the result of a command next to the revisions. Applying it
+// applies the operation the command was composed by.
+type Model struct {
+	context      *context.MainContext
+	command      jj.CommandArgs
+	loading      bool
+	err          error
+	conflicts    []string
+	lines        []string
+	height       int
+	listRenderer *render.ListRenderer
+	keymap       config.KeyMappings[key.Binding]
+	styles       styles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{m.keymap.Up, m.keymap.Down, m.keymap.ScrollUp, m.keymap.ScrollDown, m.keymap.Apply, m.keymap.Cancel}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	ctx, command := m.context, m.command
+	return func() tea.Msg {
+		result, err := Run(ctx, command)
+		return resultMsg{result: result, err: err}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case resultMsg:
+		m.loading = false
+		m.err = msg.err
+		m.conflicts = msg.result.Conflicts
+		m.lines = strings.Split(strings.TrimRight(msg.result.Graph, "\n"), "\n")
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.scroll(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.scroll(1)
+		case key.Matches(msg, m.keymap.ScrollUp):
+			m.scroll(-max(m.height-1, 1))
+		case key.Matches(msg, m.keymap.ScrollDown):
+			m.scroll(max(m.height-1, 1))
+		case key.Matches(msg, m.keymap.Apply):
+			if m.loading || m.err != nil {
+				return nil
+			}
+			return tea.Sequence(common.Close, intents.Invoke(intents.Apply{}))
+		case key.Matches(msg, m.keymap.Cancel):
+			return common.Close
+		}
+	case scrollMsg:
+		if !msg.Horizontal {
+			m.scroll(msg.Delta)
+		}
+	}
+	return nil
+}
+
+func (m *Model) scroll(delta int) {
+	m.listRenderer.SetScrollOffset(render.ClampStartLine(m.listRenderer.GetScrollOffset()+delta, m.height, len(m.lines)))
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	box, _ = box.CutBottom(1)
+	_, frame := box.CutRight(box.R.Dx() / 2)
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 3 {
+		return
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	content := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(content.R.Dx()).Height(content.R.Dy()).Render("")
+	window.AddDraw(frame.R, m.styles.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(content.R, ' ', m.styles.text, render.ZDialogs)
+
+	titleBox, content := content.CutTop(1)
+	window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).
+		Styled("Result of ", m.styles.dimmed).
+		Styled("jj "+strings.Join(m.command, " "), m.styles.title).
+		Done()
+
+	var notes []string
+	var noteStyle lipgloss.Style
+	switch {
+	case m.loading:
+		notes, noteStyle = []string{"running the command..."}, m.styles.dimmed
+	case m.err != nil:
+		notes, noteStyle = lines([]byte(m.err.Error())), m.styles.err
+	case len(m.conflicts) > 0:
+		notes, noteStyle = []string{fmt.Sprintf("%d new conflicts:", len(m.conflicts))}, m.styles.err
+		for _, conflict := range m.conflicts {
+			notes = append(notes, "  "+conflict)
+		}
+	default:
+		notes, noteStyle = []string{"no new conflicts"}, m.styles.dimmed
+	}
+	for _, note := range notes {
+		if content.R.Dy() <= 1 {
+			break
+		}
+		var noteBox layout.Box
+		noteBox, content = content.CutTop(1)
+		window.Text(noteBox.R.Min.X, noteBox.R.Min.Y, render.ZDialogs).Styled(note, noteStyle).Done()
+	}
+	if m.loading || m.err != nil {
+		return
+	}
+	_, content = content.CutTop(1)
+
+	m.height = content.R.Dy()
+	m.listRenderer.Render(
+		window,
+		content,
+		len(m.lines),
+		-1,
+		false,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			dl.AddDraw(rect, m.lines[index], render.ZDialogs)
+		},
+		func(_ int) tea.Msg { return nil },
+	)
+	m.listRenderer.RegisterScroll(window, content)
+}
+
+func New(context *context.MainContext, command jj.CommandArgs) *Model {
+	return &Model{
+		context:      context,
+		command:      command,
+		loading:      true,
+		listRenderer: render.NewListRenderer(scrollMsg{}),
+		keymap:       config.Current.GetKeyMap(),
+		styles: styles{
+			border: common.DefaultPalette.GetBorder("dry_run border", lipgloss.RoundedBorder()),
+			title:  common.DefaultPalette.Get("dry_run title"),
+			text:   common.DefaultPalette.Get("dry_run text"),
+			dimmed: common.DefaultPalette.Get("dry_run dimmed"),
+			err:    common.DefaultPalette.Get("dry_run error"),
+		},
+	}
+}
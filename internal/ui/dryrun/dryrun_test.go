@@ -0,0 +1,99 @@
+package dryrun
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var rebase = jj.CommandArgs{"rebase", "-r", "abc", "--onto", "def"}
+
+// conflictsRunner answers the conflicts listed before and after the command in
+// turn
+type conflictsRunner struct {
+	*test.CommandRunner
+	conflicts []string
+}
+
+func (r *conflictsRunner) RunCommandImmediate(args []string) ([]byte, error) {
+	if slices.Equal(args, jj.Conflicts()) {
+		output := r.conflicts[0]
+		r.conflicts = r.conflicts[1:]
+		return []byte(output), nil
+	}
+	return r.CommandRunner.RunCommandImmediate(args)
+}
+
+func TestRun_CapturesResultAndRestoresOperation(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(&conflictsRunner{CommandRunner: commandRunner, conflicts: []string{"xyz old", "xyz old\nabc moved"}})
+	ctx.CurrentRevset = "all()"
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("op1"))
+	commandRunner.Expect(append(slices.Clone(rebase), "--ignore-working-copy"))
+	commandRunner.Expect(jj.LogGraph("all()", ctx.LogLimit(), ctx.LogTemplate())).SetOutput([]byte("@  abc\n○  def"))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("op2"))
+	commandRunner.Expect(append(jj.OpRestore("op1"), "--ignore-working-copy"))
+	commandRunner.Expect(append(jj.OpAbandon("op2"), "--ignore-working-copy"))
+	defer commandRunner.Verify()
+
+	result, err := Run(ctx, rebase)
+	require.NoError(t, err)
+	assert.False(t, ctx.Previewing.Load())
+	assert.Equal(t, "@  abc\n○  def", result.Graph)
+	assert.Equal(t, []string{"abc moved"}, result.Conflicts)
+}
+
+func TestRun_FailedCommandLeavesOperation(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(&conflictsRunner{CommandRunner: commandRunner, conflicts: []string{""}})
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("op1"))
+	commandRunner.Expect(append(slices.Clone(rebase), "--ignore-working-copy")).SetError(errors.New("would create a loop"))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("op1"))
+	defer commandRunner.Verify()
+
+	_, err := Run(ctx, rebase)
+	assert.EqualError(t, err, "would create a loop")
+}
+
+func TestModel_ShowsConflictsAndApplies(t *testing.T) {
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+	config.Current = config.Default()
+
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)), rebase)
+	model.Update(resultMsg{result: Result{Graph: "@  abc\n○  def", Conflicts: []string{"abc moved"}}})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "jj rebase -r abc --onto def")
+	assert.Contains(t, rendered, "1 new conflicts:")
+	assert.Contains(t, rendered, "abc moved")
+	assert.Contains(t, rendered, "○  def")
+
+	var closed, applied bool
+	test.SimulateModel(model, test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		switch msg.(type) {
+		case common.CloseViewMsg:
+			closed = true
+		case intents.Apply:
+			applied = closed
+		}
+	})
+	assert.True(t, applied)
+}
+
+func TestModel_DoesNotApplyAfterError(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)), rebase)
+	model.Update(resultMsg{err: errors.New("would create a loop")})
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "would create a loop")
+	assert.Nil(t, model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+}
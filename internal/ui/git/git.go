@@ -36,6 +36,10 @@ type item struct {
 	name     string
 	desc     string
 	command  []string
+	// submitStack is set for the item that submits the stack of pull requests
+	// instead of running command
+	submitStack bool
+	remote      string
 }
 
 func (i item) ShortCut() string {
@@ -186,6 +190,8 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.applyFilters(false)
 		}
 		return nil
+	case stackLoadedMsg:
+		return m.pushStack(msg)
 	case intents.Intent:
 		return m.handleIntent(msg)
 	case tea.KeyMsg:
@@ -263,7 +269,7 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		if !ok {
 			return nil
 		}
-		return m.context.RunCommand(jj.Args(selected.command...), common.Refresh, common.Close)
+		return m.runItem(selected)
 	case intents.GitFilter:
 		filter := string(msg.Kind)
 		if filter != "" && m.categoryFilter != filter {
@@ -278,7 +284,7 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		}
 		for _, listItem := range m.visibleItems() {
 			if listItem.key == msg.Key {
-				return m.context.RunCommand(jj.Args(listItem.command...), common.Refresh, common.Close)
+				return m.runItem(listItem)
 			}
 		}
 		return nil
@@ -292,6 +298,13 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 	return nil
 }
 
+func (m *Model) runItem(item item) tea.Cmd {
+	if item.submitStack {
+		return m.submitStack(item.remote)
+	}
+	return m.context.RunCommand(jj.Args(item.command...), common.Refresh, common.Close)
+}
+
 func (m *Model) filtered(filter string) tea.Cmd {
 	m.categoryFilter = filter
 	m.applyFilters(true)
@@ -741,6 +754,14 @@ func (m *Model) createMenuItems() []item {
 			category: itemCategoryPush,
 			key:      "t",
 		},
+		item{
+			name:        fmt.Sprintf("submit stack --remote %s", selectedRemote),
+			desc:        fmt.Sprintf("Push and open pull requests for %s", m.stackRevset()),
+			category:    itemCategoryPush,
+			key:         "s",
+			submitStack: true,
+			remote:      selectedRemote,
+		},
 		item{
 			name:     fmt.Sprintf("git fetch --remote %s", selectedRemote),
 			desc:     "Fetch from remote",
@@ -1,16 +1,19 @@
 package git
 
 import (
+	stdcontext "context"
 	"fmt"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/forge"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/render"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Push(t *testing.T) {
@@ -75,6 +78,41 @@ func Test_PushChange(t *testing.T) {
 	test.SimulateModel(op, test.Press(tea.KeyEnter))
 }
 
+func Test_SubmitStack(t *testing.T) {
+	const changeId = "bbbb"
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkList(changeId)).SetOutput([]byte(""))
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin https://github.com/owner/repo.git"))
+	commandRunner.Expect(jj.Stack("trunk()..bbbb")).SetOutput([]byte("aaaa;first;First\nbbbb;second;Second\n"))
+	commandRunner.Expect(jj.TrunkBookmarks()).SetOutput([]byte("main\n"))
+	commandRunner.Expect(jj.GitPush("--remote", "origin", "--bookmark", "first", "--bookmark", "second"))
+	defer commandRunner.Verify()
+
+	fakeForge := test.NewFakeForge(forge.PullRequest{Bookmark: "first", Number: 5, State: forge.StateOpen})
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Forge = fakeForge
+
+	op := NewModel(ctx, jj.NewSelectedRevisions(&jj.Commit{ChangeId: changeId}))
+	test.SimulateModel(op, op.Init())
+	_ = test.RenderImmediate(op, 100, 40)
+	test.SimulateModel(op, test.Type("ps"))
+
+	require.Len(t, fakeForge.Created, 1)
+	assert.Equal(t, forge.PullRequest{Bookmark: "second", Number: 6, State: forge.StateOpen}, fakeForge.Created[0])
+	require.Len(t, fakeForge.Updated, 2)
+	assert.Equal(t, 5, fakeForge.Updated[0].Number)
+	assert.Equal(t, "main", fakeForge.Updated[0].Base)
+	assert.Contains(t, fakeForge.Updated[0].Body, "| 👉 | #5 | First |")
+	assert.Equal(t, 6, fakeForge.Updated[1].Number)
+	assert.Equal(t, "first", fakeForge.Updated[1].Base)
+	assert.Contains(t, fakeForge.Updated[1].Body, "| 👉 | #6 | Second |")
+}
+
+func Test_submitStack_RequiresBookmarks(t *testing.T) {
+	err := submitStack(stdcontext.Background(), test.NewFakeForge(), []jj.StackChange{{ChangeId: "aaaa"}}, "main")
+	assert.EqualError(t, err, "change aaaa has no bookmark")
+}
+
 // TestGit_ZIndex_RendersAboveMainContent verifies that the git overlay renders
 // at z-index >= render.ZMenuBorder. This ensures the git operations menu
 // renders above the main revision list content.
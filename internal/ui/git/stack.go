@@ -0,0 +1,158 @@
+package git
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/forge"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+const submitStackTimeout = 2 * time.Minute
+
+// stackRevset returns the changes to submit. Multiple selected revisions are
+// submitted as they are, otherwise everything between trunk and the selected
+// revision.
+func (m *Model) stackRevset() string {
+	ids := m.revisions.GetIds()
+	switch len(ids) {
+	case 0:
+		return "trunk()..@"
+	case 1:
+		return fmt.Sprintf("trunk()..%s", ids[0])
+	default:
+		return strings.Join(ids, " | ")
+	}
+}
+
+func (m *Model) submitter() (forge.Submitter, error) {
+	if m.context.Forge == nil {
+		return &forge.GitHub{Location: m.context.Location}, nil
+	}
+	if submitter, ok := m.context.Forge.(forge.Submitter); ok {
+		return submitter, nil
+	}
+	return nil, errors.New("the configured forge cannot create pull requests")
+}
+
+// stackLoadedMsg carries the changes of the stack to push
+type stackLoadedMsg struct {
+	remote  string
+	revset  string
+	changes []jj.StackChange
+}
+
+// submitStack loads the changes of the stack to push them and then open or
+// update a pull request per change
+func (m *Model) submitStack(remote string) tea.Cmd {
+	if _, err := m.submitter(); err != nil {
+		return intents.Invoke(intents.AddMessage{Text: "Failed to submit stack", Err: err})
+	}
+	revset := m.stackRevset()
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.Stack(revset))
+		if err != nil {
+			return intents.AddMessage{Text: "Failed to load stack", Err: err}
+		}
+		changes := jj.ParseStack(string(output))
+		if len(changes) == 0 {
+			return intents.AddMessage{Text: fmt.Sprintf("No changes to submit in %s", revset)}
+		}
+		return stackLoadedMsg{remote: remote, revset: revset, changes: changes}
+	}
+}
+
+// pushStack pushes every change of the stack. Changes without a bookmark are
+// pushed with --change so that jj creates a bookmark for them.
+func (m *Model) pushStack(msg stackLoadedMsg) tea.Cmd {
+	submitter, err := m.submitter()
+	if err != nil {
+		return intents.Invoke(intents.AddMessage{Text: "Failed to submit stack", Err: err})
+	}
+	flags := []string{"--remote", msg.remote}
+	for _, change := range msg.changes {
+		if bookmark := change.Bookmark(); bookmark != "" {
+			flags = append(flags, "--bookmark", bookmark)
+		} else {
+			flags = append(flags, "--change", change.ChangeId)
+		}
+	}
+	return tea.Batch(common.Close, m.context.RunCommand(jj.GitPush(flags...), m.submitPullRequests(submitter, msg.revset), common.Refresh))
+}
+
+func (m *Model) submitPullRequests(submitter forge.Submitter, revset string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.Stack(revset))
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		changes := jj.ParseStack(string(output))
+		trunk := "main"
+		if output, err := m.context.RunCommandImmediate(jj.TrunkBookmarks()); err == nil {
+			if name := jj.ParseTrunkBookmark(string(output)); name != "" {
+				trunk = name
+			}
+		}
+
+		c, cancel := stdcontext.WithTimeout(stdcontext.Background(), submitStackTimeout)
+		defer cancel()
+		if err := submitStack(c, submitter, changes, trunk); err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		return intents.AddMessage{Text: fmt.Sprintf("Submitted a stack of %d pull requests", len(changes))}
+	}
+}
+
+// submitStack creates the missing pull requests of the stack from the bottom up,
+// then points every pull request at the bookmark of its parent and refreshes
+// the stack table in their bodies.
+func submitStack(c stdcontext.Context, submitter forge.Submitter, changes []jj.StackChange, trunk string) error {
+	var bookmarks []string
+	for _, change := range changes {
+		bookmark := change.Bookmark()
+		if bookmark == "" {
+			return fmt.Errorf("change %s has no bookmark", change.ChangeId)
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	existing, err := submitter.PullRequests(c, bookmarks)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]forge.StackEntry, len(changes))
+	bases := make([]string, len(changes))
+	for i, change := range changes {
+		bases[i] = trunk
+		if i > 0 {
+			bases[i] = bookmarks[i-1]
+		}
+		pr, ok := existing[bookmarks[i]]
+		if !ok || !pr.IsOpen() {
+			pr, err = submitter.CreatePullRequest(c, bookmarks[i], bases[i], change.Title, "")
+			if err != nil {
+				return fmt.Errorf("creating pull request for %s: %w", bookmarks[i], err)
+			}
+		}
+		entries[i] = forge.StackEntry{Number: pr.Number, Title: change.Title}
+	}
+
+	for i, entry := range entries {
+		body, err := submitter.PullRequestBody(c, entry.Number)
+		if err != nil {
+			return err
+		}
+		body = forge.WithStackTable(body, forge.StackTable(entries, i))
+		if err := submitter.UpdatePullRequest(c, entry.Number, bases[i], body); err != nil {
+			return fmt.Errorf("updating pull request #%d: %w", entry.Number, err)
+		}
+	}
+	return nil
+}
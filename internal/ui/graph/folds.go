@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/idursun/jjui/internal/parser"
+)
+
+// LinearRun returns the range [start, end) of the rows around index that form
+// a linear chain: every row has its node in the same column and all lanes run
+// straight through them. Folding the rows inside the range keeps the graph
+// drawn around it intact. The range is empty when the row is not linear.
+func LinearRun(rows []parser.Row, index int) (int, int) {
+	if index < 0 || index >= len(rows) {
+		return index, index
+	}
+	shape, node, ok := linearShape(rows[index])
+	if !ok {
+		return index, index
+	}
+	same := func(row parser.Row) bool {
+		s, n, ok := linearShape(row)
+		return ok && s == shape && n == node
+	}
+	start, end := index, index+1
+	for start > 0 && same(rows[start-1]) {
+		start--
+	}
+	for end < len(rows) && same(rows[end]) {
+		end++
+	}
+	return start, end
+}
+
+// Branch returns the indexes of the rows below index that continue its lane
+// until another lane joins or leaves it. Rows of the other lanes drawn in
+// between are not part of the branch.
+func Branch(rows []parser.Row, index int) []int {
+	if index < 0 || index >= len(rows) {
+		return nil
+	}
+	column := nodeColumn(rows[index])
+	if column == -1 || !continues(rows[index], column, true) {
+		return nil
+	}
+	var branch []int
+	for i := index + 1; i < len(rows); i++ {
+		row := rows[i]
+		if nodeColumn(row) != column {
+			if !continues(row, column, false) {
+				break
+			}
+			continue
+		}
+		if _, _, ok := linearShape(row); !ok {
+			break
+		}
+		branch = append(branch, i)
+	}
+	return branch
+}
+
+// linearShape returns the gutter of a row with its node drawn as a lane, when
+// the gutter is the same on every line and only made of lanes going straight
+// down, and the column of its node
+func linearShape(row parser.Row) (string, int, bool) {
+	shape, node := "", -1
+	for i, line := range row.Lines {
+		runes := []rune(gutterText(line))
+		for col, r := range runes {
+			switch {
+			case r == '│' || r == '|' || r == ' ':
+			case line.Flags&parser.Revision != 0 && node == -1 && isNode(r):
+				node = col
+				runes[col] = '│'
+			default:
+				return "", -1, false
+			}
+		}
+		lineShape := strings.TrimRight(string(runes), " ")
+		if i > 0 && lineShape != shape {
+			return "", -1, false
+		}
+		shape = lineShape
+	}
+	return shape, node, node != -1
+}
+
+// continues reports whether the lane in column is drawn straight down on the
+// lines of the row, skipping the line of its node
+func continues(row parser.Row, column int, skipNode bool) bool {
+	for _, line := range row.Lines {
+		if skipNode && line.Flags&parser.Revision != 0 {
+			continue
+		}
+		runes := []rune(gutterText(line))
+		if column >= len(runes) || (runes[column] != '│' && runes[column] != '|') {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeColumn(row parser.Row) int {
+	for _, line := range row.Lines {
+		if line.Flags&parser.Revision == 0 {
+			continue
+		}
+		for col, r := range []rune(gutterText(line)) {
+			if isNode(r) {
+				return col
+			}
+		}
+	}
+	return -1
+}
+
+func gutterText(line *parser.GraphRowLine) string {
+	var text strings.Builder
+	for _, segment := range line.Gutter.Segments {
+		text.WriteString(segment.Text)
+	}
+	return text.String()
+}
+
+func isNode(r rune) bool {
+	return strings.ContainsRune("@○◆×◉●o*", r)
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinearRun(t *testing.T) {
+	nodes := []jj.LogNode{node("a", "b"), node("b", "c"), node("c", "d"), node("d", "e"), node("e", "f")}
+	rows := Rows(nodes, NewStyles())
+
+	start, end := LinearRun(rows, 2)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 4, end, "the elided parent of e ends the run")
+
+	start, end = LinearRun(rows, 4)
+	assert.Equal(t, start, end)
+}
+
+func TestLinearRun_StopsAtMerge(t *testing.T) {
+	nodes := []jj.LogNode{node("a", "b"), node("b", "c"), node("c", "d", "e"), node("d", "f"), node("e", "f"), node("f")}
+	rows := Rows(nodes, NewStyles())
+
+	start, end := LinearRun(rows, 0)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 2, end)
+}
+
+func TestBranch(t *testing.T) {
+	// a-b and c-d-g branch off from e, the rows of c-d-g are drawn between b and e
+	nodes := []jj.LogNode{node("a", "b"), node("b", "e"), node("c", "d"), node("d", "g"), node("g", "e"), node("e")}
+	rows := Rows(nodes, NewStyles())
+
+	assert.Equal(t, []int{1}, Branch(rows, 0))
+	assert.Equal(t, []int{3}, Branch(rows, 2), "g joins the lane of e and stays visible")
+	assert.Empty(t, Branch(rows, 5))
+}
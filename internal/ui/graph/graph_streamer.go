@@ -21,16 +21,17 @@ type GraphStreamer struct {
 	controlChan chan parser.ControlMsg
 	rowsChan    <-chan parser.RowBatch
 	batchSize   int
+	streamed    int
 }
 
-// NewGraphStreamer runs `jj log` command with given revset and jjTemplate and
+// NewGraphStreamer runs `jj log` command with given revset, limit and template and
 // Returns:
 // - Streamer: If stdout is successfully opened.
 // - Error: Returns the stderr output (warnings are also written to stderr).
-func NewGraphStreamer(parentCtx context.Context, runner appContext.CommandRunner, revset string, jjTemplate string) (*GraphStreamer, error) {
+func NewGraphStreamer(parentCtx context.Context, runner appContext.CommandRunner, revset string, limit int, template string) (*GraphStreamer, error) {
 	ctx, cancel := context.WithCancel(parentCtx)
 
-	command, err := runner.RunCommandStreaming(ctx, jj.Log(revset, config.Current.Limit, jjTemplate))
+	command, err := runner.RunCommandStreaming(ctx, jj.Log(revset, limit, template))
 	if err != nil {
 		cancel()
 		return nil, err
@@ -41,6 +42,9 @@ func NewGraphStreamer(parentCtx context.Context, runner appContext.CommandRunner
 
 	// We must read stderr in the background because it may not be closed until the command exits. (e.g. warnings)
 	go func() {
+		if command.ErrPipe == nil {
+			return
+		}
 		buf := make([]byte, 1024)
 		for {
 			n, err := command.ErrPipe.Read(buf)
@@ -114,7 +118,32 @@ func (g *GraphStreamer) RequestMore() parser.RowBatch {
 		return parser.RowBatch{}
 	}
 	g.controlChan <- parser.RequestMore
-	return <-g.rowsChan
+	batch := <-g.rowsChan
+	g.streamed += len(batch.Rows)
+	return batch
+}
+
+// Streamed returns the number of rows read so far
+func (g *GraphStreamer) Streamed() int {
+	return g.streamed
+}
+
+// ReadWindow reads batches until the rows up to from+count are streamed or the
+// graph ends, and returns the rows from position from onwards. Earlier rows are
+// dropped as they arrive so only the window is kept in memory.
+func (g *GraphStreamer) ReadWindow(from int, count int) parser.RowBatch {
+	var window parser.RowBatch
+	window.HasMore = true
+	for window.HasMore && g.streamed < from+count {
+		start := g.streamed
+		batch := g.RequestMore()
+		window.HasMore = batch.HasMore
+		if skip := from - start; skip > 0 {
+			batch.Rows = batch.Rows[min(skip, len(batch.Rows)):]
+		}
+		window.Rows = append(window.Rows, batch.Rows...)
+	}
+	return window
 }
 
 func (g *GraphStreamer) Close() {
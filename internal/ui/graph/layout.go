@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"slices"
+
+	"github.com/idursun/jjui/internal/jj"
+)
+
+// cellWidth is the number of columns every lane takes in the gutter
+const cellWidth = 2
+
+// cell is a character of the gutter and the lane it belongs to, -1 for none
+type cell struct {
+	text string
+	lane int
+}
+
+type lineKind int
+
+const (
+	nodeLine lineKind = iota
+	descriptionLine
+	edgeLine
+	elidedLine
+)
+
+type gutterLine struct {
+	kind  lineKind
+	cells []cell
+}
+
+// nodeLayout is the gutter drawn for a revision and the lane of its node
+type nodeLayout struct {
+	lane  int
+	lines []gutterLine
+}
+
+// layout assigns the revisions, children first, to lanes. A lane waits for the
+// commit it points to; when a commit is already awaited in another lane the
+// edge joins that lane instead of opening a new one, so lanes never have to be
+// merged above a node. Parents outside of the revisions are elided.
+func layout(nodes []jj.LogNode) []nodeLayout {
+	known := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		known[node.FullCommitId] = true
+	}
+
+	var lanes []string
+	layouts := make([]nodeLayout, 0, len(nodes))
+	for _, node := range nodes {
+		lane := slices.Index(lanes, node.FullCommitId)
+		if lane == -1 {
+			lane = freeLane(lanes, -1)
+			if lane == len(lanes) {
+				lanes = append(lanes, "")
+			}
+		}
+		before := slices.Clone(lanes)
+		lanes[lane] = ""
+
+		var parents []string
+		elided := false
+		for _, parent := range node.Parents {
+			if known[parent] {
+				parents = append(parents, parent)
+			} else {
+				elided = true
+			}
+		}
+
+		// edges maps the lanes the node connects to, besides its own, to
+		// whether the lane was already there
+		edges := map[int]bool{}
+		for _, parent := range parents {
+			switch existing := slices.Index(lanes, parent); {
+			case existing != -1:
+				edges[existing] = true
+			case lanes[lane] == "":
+				lanes[lane] = parent
+			default:
+				target := freeLane(lanes, lane)
+				if target == len(lanes) {
+					lanes = append(lanes, "")
+				}
+				lanes[target] = parent
+				edges[target] = false
+			}
+		}
+
+		after := lanes
+		for len(lanes) > 0 && lanes[len(lanes)-1] == "" {
+			lanes = lanes[:len(lanes)-1]
+		}
+
+		width := max(len(before), len(after))
+		l := nodeLayout{lane: lane}
+		l.lines = append(l.lines, gutterLine{kind: nodeLine, cells: nodeCells(before, width, lane, nodeGlyph(node))})
+		if !node.Root {
+			l.lines = append(l.lines, gutterLine{kind: descriptionLine, cells: nodeCells(before, width, lane, ownLane(len(parents) > 0))})
+		}
+		if len(edges) > 0 {
+			l.lines = append(l.lines, gutterLine{kind: edgeLine, cells: edgeCells(before, after, width, lane, edges)})
+		}
+		if elided && len(parents) == 0 {
+			l.lines = append(l.lines, gutterLine{kind: elidedLine, cells: nodeCells(after, width, lane, "~")})
+		}
+		layouts = append(layouts, l)
+	}
+	return layouts
+}
+
+// freeLane returns the first lane not waiting for a commit, other than skip
+func freeLane(lanes []string, skip int) int {
+	for i, commit := range lanes {
+		if commit == "" && i != skip {
+			return i
+		}
+	}
+	return len(lanes)
+}
+
+func nodeGlyph(node jj.LogNode) string {
+	switch {
+	case node.IsWorkingCopy:
+		return "@"
+	case node.Conflict:
+		return "×"
+	case node.Immutable:
+		return "◆"
+	default:
+		return "○"
+	}
+}
+
+func ownLane(continues bool) string {
+	if continues {
+		return "│"
+	}
+	return " "
+}
+
+// nodeCells draws the lanes with glyph in the lane of the node
+func nodeCells(lanes []string, width int, lane int, glyph string) []cell {
+	cells := make([]cell, 0, width*cellWidth)
+	for i := range width {
+		switch {
+		case i == lane:
+			cells = append(cells, cell{text: glyph, lane: i})
+		case i < len(lanes) && lanes[i] != "":
+			cells = append(cells, cell{text: "│", lane: i})
+		default:
+			cells = append(cells, cell{text: " ", lane: -1})
+		}
+		cells = append(cells, cell{text: " ", lane: -1})
+	}
+	return cells
+}
+
+// edgeCells draws the edges from the lane of the node to the lanes of its
+// other parents
+func edgeCells(before []string, after []string, width int, lane int, edges map[int]bool) []cell {
+	active := func(lanes []string, i int) bool {
+		return i < len(lanes) && lanes[i] != ""
+	}
+	lo, hi := lane, lane
+	for target := range edges {
+		lo, hi = min(lo, target), max(hi, target)
+	}
+	left, right := lo < lane, hi > lane
+
+	cells := make([]cell, 0, width*cellWidth)
+	for i := range width {
+		text, owner := " ", -1
+		existing, isTarget := edges[i]
+		switch {
+		case i == lane:
+			owner = lane
+			continues := active(after, lane)
+			switch {
+			case left && right && continues:
+				text = "┼"
+			case left && right:
+				text = "┴"
+			case right && continues:
+				text = "├"
+			case right:
+				text = "╰"
+			case continues:
+				text = "┤"
+			default:
+				text = "╯"
+			}
+		case isTarget:
+			owner = i
+			switch {
+			case existing && i > lane:
+				text = "┤"
+			case existing:
+				text = "├"
+			case i > lane:
+				text = "╮"
+			default:
+				text = "╭"
+			}
+		case i > lo && i < hi:
+			owner = lane
+			text = "─"
+			if active(before, i) {
+				text, owner = "┼", i
+			}
+		case active(before, i) || active(after, i):
+			text, owner = "│", i
+		}
+		cells = append(cells, cell{text: text, lane: owner})
+
+		if i >= lo && i < hi {
+			cells = append(cells, cell{text: "─", lane: lane})
+		} else {
+			cells = append(cells, cell{text: " ", lane: -1})
+		}
+	}
+	return cells
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func node(id string, parents ...string) jj.LogNode {
+	return jj.LogNode{ChangeIdPrefix: id, ChangeId: id, CommitIdPrefix: id, CommitId: id, FullCommitId: id, Parents: parents}
+}
+
+func gutters(layouts []nodeLayout) []string {
+	var lines []string
+	for _, l := range layouts {
+		for _, line := range l.lines {
+			var sb strings.Builder
+			for _, c := range line.cells {
+				sb.WriteString(c.text)
+			}
+			lines = append(lines, strings.TrimRight(sb.String(), " "))
+		}
+	}
+	return lines
+}
+
+func TestLayout_MergeAndElidedParent(t *testing.T) {
+	working := node("a", "b")
+	working.IsWorkingCopy = true
+	nodes := []jj.LogNode{working, node("b", "c", "d"), node("c", "e"), node("d", "e"), node("e", "f")}
+
+	assert.Equal(t, []string{
+		"@",
+		"│",
+		"○",
+		"│",
+		"├─╮",
+		"○ │",
+		"│ │",
+		"│ ○",
+		"│ │",
+		"├─╯",
+		"○",
+		"",
+		"~",
+	}, gutters(layout(nodes)))
+}
+
+func TestLayout_ParentAwaitedByAnotherLane(t *testing.T) {
+	nodes := []jj.LogNode{node("a", "c"), node("b", "c"), node("c")}
+
+	assert.Equal(t, []string{
+		"○",
+		"│",
+		"│ ○",
+		"│ │",
+		"├─╯",
+		"○",
+		"",
+	}, gutters(layout(nodes)))
+}
+
+func TestLayout_MergeIntoLaneOnTheLeft(t *testing.T) {
+	nodes := []jj.LogNode{node("a", "c"), node("b", "d", "c"), node("d", "c"), node("c")}
+
+	assert.Equal(t, []string{
+		"○",
+		"│",
+		"│ ○",
+		"│ │",
+		"├─┤",
+		"│ ○",
+		"│ │",
+		"├─╯",
+		"○",
+		"",
+	}, gutters(layout(nodes)))
+}
+
+func TestRows(t *testing.T) {
+	nodes := []jj.LogNode{node("a", "b"), node("b")}
+	nodes[0].Description = "first"
+	nodes[1].Empty = true
+
+	rows := Rows(nodes, NewStyles())
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "a", rows[0].Commit.ChangeId)
+	assert.Equal(t, 2, rows[0].Indent)
+	assert.Equal(t, parser.Revision|parser.Highlightable, rows[0].Lines[0].Flags)
+	assert.Equal(t, "first", rows[0].Lines[1].Segments[0].Text)
+	assert.Equal(t, "(empty) ", rows[1].Lines[1].Segments[0].Text)
+	assert.Equal(t, "(no description set)", rows[1].Lines[1].Segments[1].Text)
+	assert.Equal(t, uint64(1), rows[0].Lines[0].Gutter.Segments[0].Lane)
+	if assert.NotNil(t, rows[1].Previous) {
+		assert.Equal(t, "a", rows[1].Previous.Commit.ChangeId)
+	}
+}
@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/parser"
+	"github.com/idursun/jjui/internal/screen"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// laneColors is the number of "graph lane N" styles the lanes cycle through
+const laneColors = 6
+
+type Styles struct {
+	Lanes       []lipgloss.Style
+	WorkingCopy lipgloss.Style
+	ChangeId    lipgloss.Style
+	CommitId    lipgloss.Style
+	Rest        lipgloss.Style
+	Author      lipgloss.Style
+	Timestamp   lipgloss.Style
+	Bookmark    lipgloss.Style
+	Conflict    lipgloss.Style
+	Empty       lipgloss.Style
+	Description lipgloss.Style
+	Placeholder lipgloss.Style
+}
+
+func NewStyles() Styles {
+	styles := Styles{
+		WorkingCopy: common.DefaultPalette.Get("graph working_copy"),
+		ChangeId:    common.DefaultPalette.Get("graph change_id"),
+		CommitId:    common.DefaultPalette.Get("graph commit_id"),
+		Rest:        common.DefaultPalette.Get("graph rest"),
+		Author:      common.DefaultPalette.Get("graph author"),
+		Timestamp:   common.DefaultPalette.Get("graph timestamp"),
+		Bookmark:    common.DefaultPalette.Get("graph bookmark"),
+		Conflict:    common.DefaultPalette.Get("graph conflict"),
+		Empty:       common.DefaultPalette.Get("graph empty"),
+		Description: common.DefaultPalette.Get("graph description"),
+		Placeholder: common.DefaultPalette.Get("graph placeholder"),
+	}
+	for i := range laneColors {
+		styles.Lanes = append(styles.Lanes, common.DefaultPalette.Get(fmt.Sprintf("graph lane %d", i)))
+	}
+	return styles
+}
+
+// Rows lays out the revisions listed by jj.LogNodes as the rows parsed from
+// `jj log`, so they are rendered and navigated the same way. The lanes are
+// colored and every gutter segment carries the bit of its lane.
+func Rows(nodes []jj.LogNode, styles Styles) []parser.Row {
+	layouts := layout(nodes)
+	rows := make([]parser.Row, 0, len(nodes))
+	var previous *parser.Row
+	for i, node := range nodes {
+		l := layouts[i]
+		row := parser.Row{
+			Commit:   node.Commit(),
+			Indent:   len(l.lines[0].cells),
+			Previous: previous,
+		}
+		for _, gl := range l.lines {
+			line := &parser.GraphRowLine{Gutter: styles.gutter(gl.cells, node)}
+			switch gl.kind {
+			case nodeLine:
+				line.Flags = parser.Revision | parser.Highlightable
+				line.Segments = styles.nodeSegments(node)
+			case descriptionLine:
+				line.Flags = parser.Highlightable
+				line.Segments = styles.descriptionSegments(node)
+			case edgeLine:
+				line.Flags = parser.Highlightable
+			case elidedLine:
+				line.Flags = parser.Elided
+			}
+			row.Lines = append(row.Lines, line)
+		}
+		rows = append(rows, row)
+		// only the row right above is used to extend its gutter
+		prev := row
+		prev.Previous = nil
+		previous = &prev
+	}
+	return rows
+}
+
+func (s Styles) gutter(cells []cell, node jj.LogNode) parser.GraphGutter {
+	gutter := parser.GraphGutter{Segments: make([]*screen.Segment, 0, len(cells))}
+	for _, c := range cells {
+		segment := &screen.Segment{Text: c.text}
+		if c.lane >= 0 {
+			segment.Style = s.Lanes[c.lane%len(s.Lanes)]
+			segment.Lane = 1 << (c.lane % 64)
+		}
+		if c.text == "@" && node.IsWorkingCopy {
+			segment.Style = s.WorkingCopy
+		}
+		gutter.Segments = append(gutter.Segments, segment)
+	}
+	return gutter
+}
+
+func (s Styles) nodeSegments(node jj.LogNode) []*screen.Segment {
+	var segments []*screen.Segment
+	add := func(text string, style lipgloss.Style) {
+		segments = append(segments, &screen.Segment{Text: text, Style: style})
+	}
+	id := func(prefix string, short string, style lipgloss.Style) {
+		add(prefix, style)
+		if rest, ok := strings.CutPrefix(short, prefix); ok {
+			add(rest, s.Rest)
+		}
+	}
+
+	id(node.ChangeIdPrefix, node.ChangeId, s.ChangeId)
+	if node.Divergent {
+		add("??", s.Conflict)
+	}
+	if node.Root {
+		add(" root() ", s.Description)
+		id(node.CommitIdPrefix, node.CommitId, s.CommitId)
+		return segments
+	}
+	if node.Author != "" {
+		add(" ", s.Description)
+		add(node.Author, s.Author)
+	}
+	add(" ", s.Description)
+	add(node.Timestamp, s.Timestamp)
+	for _, bookmark := range node.Bookmarks {
+		add(" ", s.Description)
+		add(bookmark, s.Bookmark)
+	}
+	if node.Hidden {
+		add(" ", s.Description)
+		add("hidden", s.Rest)
+	}
+	add(" ", s.Description)
+	id(node.CommitIdPrefix, node.CommitId, s.CommitId)
+	if node.Conflict {
+		add(" ", s.Description)
+		add("conflict", s.Conflict)
+	}
+	return segments
+}
+
+func (s Styles) descriptionSegments(node jj.LogNode) []*screen.Segment {
+	var segments []*screen.Segment
+	if node.Empty {
+		segments = append(segments, &screen.Segment{Text: "(empty) ", Style: s.Empty})
+	}
+	if node.Description == "" {
+		return append(segments, &screen.Segment{Text: "(no description set)", Style: s.Placeholder})
+	}
+	return append(segments, &screen.Segment{Text: node.Description, Style: s.Description})
+}
@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/idursun/jjui/internal/parser"
+)
+
+// RowIndex maps the change ids streamed from a graph to their position in it,
+// so revisions whose rows are no longer kept in memory can still be found.
+type RowIndex struct {
+	positions map[string]int
+}
+
+func NewRowIndex() *RowIndex {
+	return &RowIndex{positions: make(map[string]int)}
+}
+
+// Add records the rows streamed at the given position. The working copy is
+// recorded as @.
+func (x *RowIndex) Add(offset int, rows []parser.Row) {
+	for i, row := range rows {
+		if row.Commit == nil {
+			continue
+		}
+		position := offset + i
+		if changeId := row.Commit.GetChangeId(); changeId != "" {
+			x.positions[strings.ToLower(changeId)] = position
+		}
+		if row.Commit.ChangeId != "" {
+			x.positions[strings.ToLower(row.Commit.ChangeId)] = position
+		}
+		if row.Commit.IsWorkingCopy {
+			x.positions["@"] = position
+		}
+	}
+}
+
+// Position returns the position of the revision in the graph
+func (x *RowIndex) Position(revision string) (int, bool) {
+	if x == nil {
+		return 0, false
+	}
+	position, ok := x.positions[strings.ToLower(revision)]
+	return position, ok
+}
@@ -0,0 +1,462 @@
+package hunks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ help.KeyMap           = (*Model)(nil)
+)
+
+// makeTempDir creates the directory the selected content is prepared in
+var makeTempDir = func() (string, error) {
+	return os.MkdirTemp("", "jjui-selection-")
+}
+
+type action int
+
+const (
+	actionSplit action = iota
+	actionSquash
+	actionRestore
+)
+
+type rowKind int
+
+const (
+	rowFile rowKind = iota
+	rowHunk
+	rowLine
+)
+
+type row struct {
+	kind rowKind
+	file int
+	hunk int
+	line int
+}
+
+type checkState int
+
+const (
+	unchecked checkState = iota
+	partial
+	checked
+)
+
+type rowClickedMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type styles struct {
+	border   lipgloss.Style
+	title    lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+	file     lipgloss.Style
+	hunk     lipgloss.Style
+	added    lipgloss.Style
+	removed  lipgloss.Style
+}
+
+// Model lets the user pick the hunks and lines of a revision that should be
+// split, squashed or restored.
+type Model struct {
+	context             *context.MainContext
+	revision            *jj.Commit
+	files               []jj.FileDiff
+	skipped             int
+	selected            [][][]bool
+	rows                []row
+	cursor              int
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.Up,
+		m.keymap.Down,
+		m.keymap.HunkPicker.Toggle,
+		m.keymap.HunkPicker.Split,
+		m.keymap.HunkPicker.Squash,
+		m.keymap.HunkPicker.Restore,
+		m.keymap.HunkPicker.Close,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		km := m.keymap.HunkPicker
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.move(1)
+		case key.Matches(msg, km.Toggle):
+			m.toggle(m.cursor)
+		case key.Matches(msg, km.Split):
+			return m.apply(actionSplit)
+		case key.Matches(msg, km.Squash):
+			return m.apply(actionSquash)
+		case key.Matches(msg, km.Restore):
+			return m.apply(actionRestore)
+		case key.Matches(msg, km.Close), key.Matches(msg, m.keymap.Cancel):
+			return common.Close
+		}
+	case rowClickedMsg:
+		if msg.Index >= 0 && msg.Index < len(m.rows) && m.isSelectable(msg.Index) {
+			m.cursor = msg.Index
+			m.toggle(msg.Index)
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.ensureCursorVisible = false
+		m.listRenderer.SetScrollOffset(max(0, m.listRenderer.GetScrollOffset()+msg.Delta))
+	}
+	return nil
+}
+
+// isSelectable reports whether the cursor can stop on the row; context lines can't be picked
+func (m *Model) isSelectable(index int) bool {
+	r := m.rows[index]
+	return r.kind != rowLine || m.line(r).Kind != jj.DiffLineContext
+}
+
+func (m *Model) move(delta int) {
+	for i := m.cursor + delta; i >= 0 && i < len(m.rows); i += delta {
+		if m.isSelectable(i) {
+			m.cursor = i
+			m.ensureCursorVisible = true
+			return
+		}
+	}
+}
+
+func (m *Model) line(r row) jj.DiffLine {
+	return m.files[r.file].Hunks[r.hunk].Lines[r.line]
+}
+
+// toggle flips the row under the cursor. Files and hunks select all of their
+// changed lines unless they are already fully selected.
+func (m *Model) toggle(index int) {
+	if index < 0 || index >= len(m.rows) {
+		return
+	}
+	r := m.rows[index]
+	switch r.kind {
+	case rowFile:
+		value := m.fileState(r.file) != checked
+		for hi := range m.selected[r.file] {
+			m.setHunk(r.file, hi, value)
+		}
+	case rowHunk:
+		m.setHunk(r.file, r.hunk, m.hunkState(r.file, r.hunk) != checked)
+	case rowLine:
+		m.selected[r.file][r.hunk][r.line] = !m.selected[r.file][r.hunk][r.line]
+	}
+}
+
+func (m *Model) setHunk(file int, hunk int, value bool) {
+	for li, line := range m.files[file].Hunks[hunk].Lines {
+		if line.Kind != jj.DiffLineContext {
+			m.selected[file][hunk][li] = value
+		}
+	}
+}
+
+func (m *Model) hunkState(file int, hunk int) checkState {
+	total, count := 0, 0
+	for li, line := range m.files[file].Hunks[hunk].Lines {
+		if line.Kind == jj.DiffLineContext {
+			continue
+		}
+		total++
+		if m.selected[file][hunk][li] {
+			count++
+		}
+	}
+	return stateOf(count, total)
+}
+
+func (m *Model) fileState(file int) checkState {
+	total, count := 0, 0
+	for hi := range m.files[file].Hunks {
+		switch m.hunkState(file, hi) {
+		case checked:
+			count += 2
+		case partial:
+			count++
+		}
+		total += 2
+	}
+	return stateOf(count, total)
+}
+
+func stateOf(count int, total int) checkState {
+	switch {
+	case count == 0:
+		return unchecked
+	case count == total:
+		return checked
+	default:
+		return partial
+	}
+}
+
+// apply prepares the content of every file with a selection and runs the
+// action with jj's diff editor replaced by a copy of the prepared files.
+func (m *Model) apply(a action) tea.Cmd {
+	dir, files, deleted, err := m.prepare(a)
+	if err != nil {
+		return func() tea.Msg {
+			return common.CommandCompletedMsg{Err: err}
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	tool := jj.SelectionToolArgs(dir, deleted)
+	changeId := m.revision.GetChangeId()
+	// the continuations run whether the command succeeds or not
+	removeDir := func() tea.Msg {
+		_ = os.RemoveAll(dir)
+		return nil
+	}
+	switch a {
+	case actionSplit:
+		args := append(jj.Split(changeId, files, false, true), tool...)
+		return tea.Batch(common.Close, m.context.RunInteractiveCommand(args, tea.Batch(common.Refresh, removeDir)))
+	case actionRestore:
+		args := append(jj.Restore(changeId, files, true), tool...)
+		return tea.Batch(common.Close, m.context.RunCommand(args, common.Refresh, removeDir))
+	default:
+		return tea.Batch(common.Close, func() tea.Msg {
+			return intents.StartSquash{
+				Selected:       jj.NewSelectedRevisions(m.revision),
+				Files:          files,
+				DiffEditorArgs: tool,
+				SelectionDir:   dir,
+			}
+		})
+	}
+}
+
+// prepare writes the content that jj's diff editor should leave behind for each
+// file with a selection. For split and squash that is the parent content with the
+// selected changes applied; for restore the selected changes are the ones to drop.
+func (m *Model) prepare(a action) (string, []string, []string, error) {
+	var files, deleted []string
+	for fi := range m.files {
+		if m.fileState(fi) != unchecked {
+			files = append(files, m.files[fi].Path())
+		}
+	}
+	if len(files) == 0 {
+		return "", nil, nil, nil
+	}
+
+	dir, err := makeTempDir()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	for fi, file := range m.files {
+		if m.fileState(fi) == unchecked {
+			continue
+		}
+		content := ""
+		if file.Status != jj.FileDeleted {
+			output, err := m.context.ReadFile(m.revision.GetChangeId(), file.Path())
+			if err != nil {
+				_ = os.RemoveAll(dir)
+				return "", nil, nil, errors.Join(fmt.Errorf("failed to read %s", file.Path()), err)
+			}
+			content = output
+		}
+		selected := func(hunk int, line int) bool {
+			return m.selected[fi][hunk][line] != (a == actionRestore)
+		}
+		result, exists := file.ApplySelection(content, selected)
+		if !exists {
+			deleted = append(deleted, file.Path())
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(file.Path()))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", nil, nil, err
+		}
+		if err := os.WriteFile(target, []byte(result), 0o644); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", nil, nil, err
+		}
+	}
+	return dir, files, deleted, nil
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	frame := box.Center(max(box.R.Dx()-4, 0), max(box.R.Dy()-2, 0))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 3 {
+		return
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	contentBox := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	window.AddDraw(frame.R, m.styles.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(contentBox.R, ' ', m.styles.text, render.ZDialogs)
+
+	titleBox, listBox := contentBox.CutTop(1)
+	title := fmt.Sprintf("Pick hunks of %s", m.revision.GetChangeId())
+	if m.skipped > 0 {
+		title += fmt.Sprintf(" (%d binary or renamed files are not listed)", m.skipped)
+	}
+	window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).Styled(title, m.styles.title).Done()
+
+	if len(m.rows) == 0 {
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled("No changes to pick from", m.styles.dimmed).Done()
+		return
+	}
+
+	m.listRenderer.Render(
+		window,
+		listBox,
+		len(m.rows),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			m.renderRow(dl, index, rect)
+		},
+		func(index int) tea.Msg { return rowClickedMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(window, listBox)
+	m.ensureCursorVisible = false
+}
+
+func (m *Model) renderRow(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+	r := m.rows[index]
+	tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs)
+	switch r.kind {
+	case rowFile:
+		tb.Styled(checkbox(m.fileState(r.file))+" ", m.styles.file).Styled(m.files[r.file].Path(), m.styles.file)
+	case rowHunk:
+		tb.Space(2).Styled(checkbox(m.hunkState(r.file, r.hunk))+" ", m.styles.hunk).Styled(m.files[r.file].Hunks[r.hunk].Header, m.styles.hunk)
+	case rowLine:
+		line := m.line(r)
+		content := strings.ReplaceAll(line.Content, "\t", "    ")
+		switch line.Kind {
+		case jj.DiffLineContext:
+			tb.Space(8).Styled(content, m.styles.dimmed)
+		case jj.DiffLineAdded:
+			tb.Space(4).Styled(m.lineCheckbox(r)+" +"+content, m.styles.added)
+		case jj.DiffLineRemoved:
+			tb.Space(4).Styled(m.lineCheckbox(r)+" -"+content, m.styles.removed)
+		}
+	}
+	tb.Done()
+	if index == m.cursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZDialogs+1)
+	}
+}
+
+func checkbox(state checkState) string {
+	switch state {
+	case checked:
+		return "[x]"
+	case partial:
+		return "[~]"
+	default:
+		return "[ ]"
+	}
+}
+
+func (m *Model) lineCheckbox(r row) string {
+	if m.selected[r.file][r.hunk][r.line] {
+		return checkbox(checked)
+	}
+	return checkbox(unchecked)
+}
+
+func New(context *context.MainContext, revision *jj.Commit, diff string) *Model {
+	m := &Model{
+		context:      context,
+		revision:     revision,
+		keymap:       config.Current.GetKeyMap(),
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+		styles: styles{
+			border:   common.DefaultPalette.GetBorder("hunks border", lipgloss.RoundedBorder()),
+			title:    common.DefaultPalette.Get("hunks title"),
+			text:     common.DefaultPalette.Get("hunks text"),
+			dimmed:   common.DefaultPalette.Get("hunks dimmed"),
+			selected: common.DefaultPalette.Get("hunks selected"),
+			file:     common.DefaultPalette.Get("diff file"),
+			hunk:     common.DefaultPalette.Get("diff hunk"),
+			added:    common.DefaultPalette.Get("diff added"),
+			removed:  common.DefaultPalette.Get("diff removed"),
+		},
+	}
+	for _, file := range jj.ParseGitDiff(diff) {
+		// binary files and renames can't be rebuilt from their hunks
+		if file.Binary || len(file.Hunks) == 0 || file.Status == jj.FileRenamed || file.Status == jj.FileCopied {
+			m.skipped++
+			continue
+		}
+		fi := len(m.files)
+		m.files = append(m.files, file)
+		m.rows = append(m.rows, row{kind: rowFile, file: fi})
+		hunks := make([][]bool, len(file.Hunks))
+		for hi, hunk := range file.Hunks {
+			hunks[hi] = make([]bool, len(hunk.Lines))
+			m.rows = append(m.rows, row{kind: rowHunk, file: fi, hunk: hi})
+			for li := range hunk.Lines {
+				m.rows = append(m.rows, row{kind: rowLine, file: fi, hunk: hi, line: li})
+			}
+		}
+		m.selected = append(m.selected, hunks)
+	}
+	return m
+}
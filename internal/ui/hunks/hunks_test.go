@@ -0,0 +1,163 @@
+package hunks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	Revision = "revision"
+	Diff     = `diff --git a/file.txt b/file.txt
+index 1111111..2222222 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,3 +1,4 @@
+ a
+-b
++B
+ c
++d
+`
+	Content = "a\nB\nc\nd\n"
+)
+
+var Commit = &jj.Commit{
+	ChangeId: Revision,
+	CommitId: Revision,
+}
+
+func useTempDir(t *testing.T) string {
+	dir := t.TempDir()
+	previous := makeTempDir
+	makeTempDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { makeTempDir = previous })
+	return dir
+}
+
+// readOnCompletion reads the file when the command completes, before the
+// selection directory is removed
+func readOnCompletion(t *testing.T, path string, content *string) func(tea.Msg) {
+	return func(msg tea.Msg) {
+		if _, ok := msg.(common.CommandCompletedMsg); ok {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			*content = string(data)
+		}
+	}
+}
+
+func TestNew_ListsFilesHunksAndLines(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "[ ] file.txt")
+	assert.Contains(t, rendered, "[ ] @@ -1,3 +1,4 @@")
+	assert.Contains(t, rendered, "[ ] +B")
+	assert.Contains(t, rendered, "[ ] -b")
+}
+
+func TestToggle_MarksHunkAsPartiallySelected(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	// file -> hunk -> -b -> +B, context lines are skipped
+	test.SimulateModel(model, test.Type("jjj"))
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "[~] file.txt")
+	assert.Contains(t, rendered, "[~] @@ -1,3 +1,4 @@")
+	assert.Contains(t, rendered, "[x] +B")
+	assert.Contains(t, rendered, "[ ] -b")
+}
+
+func TestApply_SplitsSelectedLines(t *testing.T) {
+	dir := useTempDir(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	commandRunner.Expect(append(jj.Split(Revision, []string{"file.txt"}, false, true), jj.SelectionToolArgs(dir, nil)...))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	test.SimulateModel(model, test.Type("jjj"))
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	var content string
+	test.SimulateModel(model, test.Type("s"), readOnCompletion(t, filepath.Join(dir, "file.txt"), &content))
+	assert.Equal(t, "a\nb\nB\nc\n", content)
+	assert.NoDirExists(t, dir)
+}
+
+func TestApply_RemovesSelectionWhenCommandFails(t *testing.T) {
+	dir := useTempDir(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	commandRunner.Expect(append(jj.Split(Revision, []string{"file.txt"}, false, true), jj.SelectionToolArgs(dir, nil)...)).SetError(errors.New("failed"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	test.SimulateModel(model, test.Type("s"))
+	assert.NoDirExists(t, dir)
+}
+
+func TestApply_RestoresSelectedLines(t *testing.T) {
+	dir := useTempDir(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	commandRunner.Expect(append(jj.Restore(Revision, []string{"file.txt"}, true), jj.SelectionToolArgs(dir, nil)...))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	// select the whole file, then drop the added line at the end
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	test.SimulateModel(model, test.Type("jjjjj"))
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	var content string
+	test.SimulateModel(model, test.Type("r"), readOnCompletion(t, filepath.Join(dir, "file.txt"), &content))
+
+	// restore keeps the unselected changes
+	assert.Equal(t, "a\nb\nc\nd\n", content)
+	assert.NoDirExists(t, dir)
+}
+
+func TestApply_SquashStartsSquashWithSelectionTool(t *testing.T) {
+	dir := useTempDir(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileShow(Revision, "file.txt")).SetOutput([]byte(Content))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+
+	var squash *intents.StartSquash
+	test.SimulateModel(model, test.Type("S"), func(msg tea.Msg) {
+		if intent, ok := msg.(intents.StartSquash); ok {
+			squash = &intent
+		}
+	})
+	require.NotNil(t, squash)
+	assert.Equal(t, []string{"file.txt"}, squash.Files)
+	assert.Equal(t, jj.SelectionToolArgs(dir, nil), squash.DiffEditorArgs)
+	assert.Equal(t, dir, squash.SelectionDir)
+}
+
+func TestApply_NothingSelected(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), Commit, Diff)
+	test.SimulateModel(model, test.Type("s"))
+}
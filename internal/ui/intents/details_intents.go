@@ -40,3 +40,11 @@ func (DetailsToggleSelect) isIntent() {}
 type DetailsRevisionsChangingFile struct{}
 
 func (DetailsRevisionsChangingFile) isIntent() {}
+
+type DetailsPickHunks struct{}
+
+func (DetailsPickHunks) isIntent() {}
+
+type DetailsResolveConflicts struct{}
+
+func (DetailsResolveConflicts) isIntent() {}
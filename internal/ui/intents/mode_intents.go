@@ -50,6 +50,12 @@ type RebaseToggleSkipEmptied struct{}
 
 func (RebaseToggleSkipEmptied) isIntent() {}
 
+// PreviewResult shows the revisions as they would be after applying the
+// rebase, duplicate or revert, without changing the repository
+type PreviewResult struct{}
+
+func (PreviewResult) isIntent() {}
+
 type RevertSetTarget struct {
 	Target RevertTarget
 }
@@ -15,6 +15,12 @@ type OpLogClose struct{}
 
 func (OpLogClose) isIntent() {}
 
+type OpLogToggleCheck struct {
+	OperationId string
+}
+
+func (OpLogToggleCheck) isIntent() {}
+
 type OpLogShowDiff struct {
 	OperationId string
 }
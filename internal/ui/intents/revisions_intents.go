@@ -9,6 +9,11 @@ func (OpenDetails) isIntent() {}
 type StartSquash struct {
 	Selected jj.SelectedRevisions
 	Files    []string
+	// DiffEditorArgs replaces the interactive diff editor, see jj.SelectionToolArgs
+	DiffEditorArgs jj.CommandArgs
+	// SelectionDir is the directory DiffEditorArgs copies from, it is removed
+	// when the squash ends
+	SelectionDir string
 }
 
 func (StartSquash) isIntent() {}
@@ -96,6 +101,33 @@ type Navigate struct {
 
 func (Navigate) isIntent() {}
 
+type FoldTarget int
+
+const (
+	FoldRun    FoldTarget = iota // the linear run of revisions around the revision
+	FoldBranch                   // the revisions below it in its branch
+)
+
+type FoldAction int
+
+const (
+	FoldToggle FoldAction = iota
+	FoldCollapse
+	FoldExpand
+)
+
+type Fold struct {
+	Target   FoldTarget
+	Action   FoldAction
+	ChangeID string // defaults to the selected revision
+}
+
+func (Fold) isIntent() {}
+
+type UnfoldAll struct{}
+
+func (UnfoldAll) isIntent() {}
+
 type StartNew struct {
 	Selected jj.SelectedRevisions
 }
@@ -201,3 +233,15 @@ type Refresh struct {
 }
 
 func (Refresh) isIntent() {}
+
+type OpenTemplates struct{}
+
+func (OpenTemplates) isIntent() {}
+
+// SetTemplate shows the revisions with the template, the one of the view or
+// the config when it is empty
+type SetTemplate struct {
+	Template string
+}
+
+func (SetTemplate) isIntent() {}
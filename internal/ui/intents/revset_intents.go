@@ -26,3 +26,21 @@ func (Set) isIntent() {}
 type Reset struct{}
 
 func (Reset) isIntent() {}
+
+type OpenViews struct{}
+
+func (OpenViews) isIntent() {}
+
+// ApplyView shows the revset of the named view with its settings
+type ApplyView struct {
+	Name string
+}
+
+func (ApplyView) isIntent() {}
+
+// SaveView saves the current revset and preview as a view of the repository
+type SaveView struct {
+	Name string
+}
+
+func (SaveView) isIntent() {}
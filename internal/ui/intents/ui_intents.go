@@ -40,6 +40,10 @@ type OpenGit struct{}
 
 func (OpenGit) isIntent() {}
 
+type OpenJournal struct{}
+
+func (OpenJournal) isIntent() {}
+
 type BookmarksSet struct{}
 
 func (BookmarksSet) isIntent() {}
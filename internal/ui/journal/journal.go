@@ -0,0 +1,307 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ help.KeyMap           = (*Model)(nil)
+)
+
+// writeClipboard is replaced in tests
+var writeClipboard = clipboard.WriteAll
+
+// detailsHeight is the number of lines showing the selected entry
+const detailsHeight = 6
+
+type loadedMsg struct {
+	entries []config.JournalEntry
+	err     error
+}
+
+type rowClickedMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type styles struct {
+	border   lipgloss.Style
+	title    lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+	success  lipgloss.Style
+	err      lipgloss.Style
+}
+
+// Model lists the jj commands recorded in the journal of the repository, most
+// recent first.
+type Model struct {
+	context             *context.MainContext
+	entries             []config.JournalEntry
+	err                 error
+	loading             bool
+	cursor              int
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.Up,
+		m.keymap.Down,
+		m.keymap.Journal.Copy,
+		m.keymap.Journal.Rerun,
+		m.keymap.Journal.Close,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	journal := m.context.Journal
+	return func() tea.Msg {
+		if journal == nil {
+			return loadedMsg{}
+		}
+		entries, err := journal.Entries()
+		// newest first
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+		return loadedMsg{entries: entries, err: err}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case common.ConfigReloadedMsg:
+		m.keymap = config.Current.GetKeyMap()
+		m.styles = newStyles()
+	case loadedMsg:
+		m.loading = false
+		m.entries = msg.entries
+		m.err = msg.err
+		m.cursor = 0
+	case tea.KeyMsg:
+		km := m.keymap.Journal
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.move(1)
+		case key.Matches(msg, km.Copy):
+			return m.copy()
+		case key.Matches(msg, km.Rerun):
+			return m.rerun()
+		case key.Matches(msg, km.Close), key.Matches(msg, m.keymap.Cancel):
+			return common.Close
+		}
+	case rowClickedMsg:
+		if msg.Index >= 0 && msg.Index < len(m.entries) {
+			m.cursor = msg.Index
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.ensureCursorVisible = false
+		m.listRenderer.SetScrollOffset(max(0, m.listRenderer.GetScrollOffset()+msg.Delta))
+	}
+	return nil
+}
+
+func (m *Model) move(delta int) {
+	next := m.cursor + delta
+	if next < 0 || next >= len(m.entries) {
+		return
+	}
+	m.cursor = next
+	m.ensureCursorVisible = true
+}
+
+func (m *Model) selected() (config.JournalEntry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return config.JournalEntry{}, false
+	}
+	return m.entries[m.cursor], true
+}
+
+func (m *Model) copy() tea.Cmd {
+	entry, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	command := entry.ShellCommand()
+	if err := writeClipboard(command); err != nil {
+		return intents.Invoke(intents.AddMessage{Text: "Failed to copy to clipboard", Err: err})
+	}
+	return intents.Invoke(intents.AddMessage{Text: "Copied " + command})
+}
+
+func (m *Model) rerun() tea.Cmd {
+	entry, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	args := jj.Args(entry.Args...)
+	switch {
+	case jj.UsesMergeTool(entry.Args):
+		return intents.Invoke(intents.AddMessage{Text: "Cannot re-run the command", Err: errors.New("the files jjui prepared for it were removed")})
+	case entry.Input == nil && slices.Contains(entry.Args, "--stdin"):
+		return intents.Invoke(intents.AddMessage{Text: "Cannot re-run the command", Err: errors.New("its input was not recorded")})
+	case entry.Input != nil:
+		return tea.Batch(common.Close, m.context.RunCommandWithInput(args, *entry.Input, common.Refresh))
+	case entry.Interactive:
+		return tea.Batch(common.Close, m.context.RunInteractiveCommand(args, common.Refresh))
+	default:
+		return tea.Batch(common.Close, m.context.RunCommand(args, common.Refresh))
+	}
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	frame := box.Center(max(box.R.Dx()-4, 0), max(box.R.Dy()-2, 0))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= detailsHeight+4 {
+		return
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	contentBox := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	window.AddDraw(frame.R, m.styles.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(contentBox.R, ' ', m.styles.text, render.ZDialogs)
+
+	titleBox, listBox := contentBox.CutTop(1)
+	window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).
+		Styled(fmt.Sprintf("Journal (%d commands)", len(m.entries)), m.styles.title).
+		Done()
+
+	switch {
+	case m.loading:
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled("Loading...", m.styles.dimmed).Done()
+		return
+	case m.err != nil:
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled(m.err.Error(), m.styles.err).Done()
+		return
+	case len(m.entries) == 0:
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled("No commands recorded yet", m.styles.dimmed).Done()
+		return
+	}
+
+	listBox, detailsBox := listBox.CutBottom(detailsHeight)
+	m.listRenderer.Render(
+		window,
+		listBox,
+		len(m.entries),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			m.renderRow(dl, index, rect)
+		},
+		func(index int) tea.Msg { return rowClickedMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(window, listBox)
+	m.ensureCursorVisible = false
+	m.renderDetails(window, detailsBox)
+}
+
+func (m *Model) renderRow(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+	entry := m.entries[index]
+	tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs).
+		Styled(entry.Time.Local().Format("2006-01-02 15:04:05"), m.styles.dimmed).
+		Space(1)
+	if entry.ExitCode == 0 {
+		tb.Styled(" ✓ ", m.styles.success)
+	} else {
+		tb.Styled(fmt.Sprintf("%3d", entry.ExitCode), m.styles.err)
+	}
+	tb.Space(1).Styled(entry.ShellCommand(), m.styles.text).Done()
+	if index == m.cursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZDialogs+1)
+	}
+}
+
+func (m *Model) renderDetails(dl *render.DisplayContext, box layout.Box) {
+	entry, ok := m.selected()
+	if !ok {
+		return
+	}
+	y := box.R.Min.Y + 1
+	operations := fmt.Sprintf("operation %s → %s", shortOperation(entry.OpBefore), shortOperation(entry.OpAfter))
+	dl.Text(box.R.Min.X, y, render.ZDialogs).Styled(operations, m.styles.dimmed).Done()
+	style := m.styles.text
+	if entry.ExitCode != 0 {
+		style = m.styles.err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(entry.Stderr), "\n") {
+		y++
+		if y >= box.R.Max.Y {
+			break
+		}
+		dl.Text(box.R.Min.X, y, render.ZDialogs).Styled(line, style).Done()
+	}
+}
+
+func shortOperation(id string) string {
+	if id == "" {
+		return "?"
+	}
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+func New(context *context.MainContext) *Model {
+	return &Model{
+		context:      context,
+		loading:      true,
+		keymap:       config.Current.GetKeyMap(),
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+		styles:       newStyles(),
+	}
+}
+
+func newStyles() styles {
+	return styles{
+		border:   common.DefaultPalette.GetBorder("journal border", lipgloss.RoundedBorder()),
+		title:    common.DefaultPalette.Get("journal title"),
+		text:     common.DefaultPalette.Get("journal text"),
+		dimmed:   common.DefaultPalette.Get("journal dimmed"),
+		selected: common.DefaultPalette.Get("journal selected"),
+		success:  common.DefaultPalette.Get("journal success"),
+		err:      common.DefaultPalette.Get("journal error"),
+	}
+}
@@ -0,0 +1,154 @@
+package journal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJournal(t *testing.T, entries ...config.JournalEntry) *config.Journal {
+	journal := config.NewJournalFile(filepath.Join(t.TempDir(), "journal.jsonl"))
+	for _, entry := range entries {
+		require.NoError(t, journal.Append(entry))
+	}
+	return journal
+}
+
+func TestInit_ListsMostRecentFirst(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Journal = newJournal(t,
+		config.JournalEntry{Time: time.Now(), Args: []string{"new"}},
+		config.JournalEntry{Time: time.Now(), Args: []string{"git", "push"}, ExitCode: 1, Stderr: "Error: rejected"},
+	)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "Journal (2 commands)")
+	assert.Contains(t, rendered, "jj git push")
+	assert.Contains(t, rendered, "Error: rejected")
+	assert.Less(t, strings.Index(rendered, "jj git push"), strings.Index(rendered, "jj new"))
+}
+
+func TestInit_WithoutEntries(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Journal = newJournal(t)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "No commands recorded yet")
+}
+
+func TestRerun_RunsSelectedCommand(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Args("new"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Journal = newJournal(t,
+		config.JournalEntry{Time: time.Now(), Args: []string{"new"}},
+		config.JournalEntry{Time: time.Now(), Args: []string{"status"}},
+	)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("jr"))
+}
+
+// inputRunner records the input of the commands run with RunCommandWithInput
+type inputRunner struct {
+	*test.CommandRunner
+	input *string
+}
+
+func (r *inputRunner) RunCommandWithInput(args []string, input string, continuations ...tea.Cmd) tea.Cmd {
+	r.input = &input
+	return r.CommandRunner.RunCommandWithInput(args, input, continuations...)
+}
+
+func TestRerun_FeedsRecordedInputToDescribe(t *testing.T) {
+	describe := jj.SetDescription("abc", "the description")
+	commandRunner := &inputRunner{CommandRunner: test.NewTestCommandRunner(t)}
+	commandRunner.Expect(describe.Args)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Journal = newJournal(t, config.JournalEntry{Time: time.Now(), Args: describe.Args, Input: &describe.Input})
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("r"))
+	require.NotNil(t, commandRunner.input)
+	assert.Equal(t, "the description", *commandRunner.input)
+}
+
+func TestRerun_RefusesCommandsItCannotReplay(t *testing.T) {
+	tests := map[string]config.JournalEntry{
+		"input not recorded": {Args: jj.SetDescription("abc", "").Args},
+		"selection removed":  {Args: append(jj.Split("abc", []string{"file.txt"}, false, true), jj.SelectionToolArgs("/tmp/selection", nil)...), Interactive: true},
+	}
+	for name, entry := range tests {
+		t.Run(name, func(t *testing.T) {
+			commandRunner := test.NewTestCommandRunner(t)
+			defer commandRunner.Verify()
+			ctx := test.NewTestContext(commandRunner)
+			entry.Time = time.Now()
+			ctx.Journal = newJournal(t, entry)
+
+			model := New(ctx)
+			test.SimulateModel(model, model.Init())
+			var flashMsg intents.AddMessage
+			test.SimulateModel(model, test.Type("r"), func(msg tea.Msg) {
+				if got, ok := msg.(intents.AddMessage); ok {
+					flashMsg = got
+				}
+			})
+			assert.Equal(t, "Cannot re-run the command", flashMsg.Text)
+		})
+	}
+}
+
+func TestCopy_WritesShellCommandToClipboard(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Journal = newJournal(t, config.JournalEntry{Time: time.Now(), Args: []string{"describe", "-m", "a message"}})
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	oldWriteClipboard := writeClipboard
+	t.Cleanup(func() {
+		writeClipboard = oldWriteClipboard
+	})
+
+	var copied string
+	writeClipboard = func(value string) error {
+		copied = value
+		return nil
+	}
+
+	var flashMsg intents.AddMessage
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		if got, ok := msg.(intents.AddMessage); ok {
+			flashMsg = got
+		}
+	})
+
+	assert.Equal(t, "jj describe -m 'a message'", copied)
+	assert.Equal(t, "Copied jj describe -m 'a message'", flashMsg.Text)
+	assert.NoError(t, flashMsg.Err)
+}
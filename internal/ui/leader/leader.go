@@ -1,12 +1,17 @@
 package leader
 
 import (
+	"fmt"
+	"log"
 	"maps"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 )
@@ -15,19 +20,35 @@ type Model struct {
 	cancel  key.Binding
 	shown   context.LeaderMap
 	context *context.MainContext
+	// mode is the name of the operation the revisions are in
+	mode string
+	// count is the number typed before the keys, 0 when none is typed
+	count int
+	// level are the entries of the level shown, including those whose revset
+	// conditions are still being evaluated
+	level context.LeaderMap
+	// inRevset tells whether the selected revision is in the revsets of the
+	// conditions evaluated so far
+	inRevset map[string]bool
 }
 
-func New(ctx *context.MainContext) *Model {
+func New(ctx *context.MainContext, mode string) *Model {
 	keyMap := config.Current.GetKeyMap()
 	m := &Model{
-		context: ctx,
-		cancel:  keyMap.Cancel,
+		context:  ctx,
+		cancel:   keyMap.Cancel,
+		mode:     mode,
+		inRevset: make(map[string]bool),
 	}
 	return m
 }
 
 func (m *Model) ShortHelp() []key.Binding {
-	bindings := []key.Binding{m.cancel}
+	count := key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("1-9", "count"))
+	if m.count > 0 {
+		count.SetHelp(strconv.Itoa(m.count), "times")
+	}
+	bindings := []key.Binding{m.cancel, count}
 	for m := range maps.Values(m.shown) {
 		bindings = append(bindings, *m.Bind)
 	}
@@ -41,6 +62,13 @@ func (m *Model) FullHelp() [][]key.Binding {
 
 type initMsg struct{}
 
+// conditionsMsg is the result of evaluating the revset conditions of a level
+type conditionsMsg struct {
+	revsets []string
+	output  string
+	err     error
+}
+
 func InitCmd() tea.Msg {
 	return initMsg{}
 }
@@ -52,34 +80,95 @@ func (m *Model) Init() tea.Cmd {
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case initMsg:
-		m.shown = contextEnabled(m.context, m.context.Leader)
+		return m.show(m.context.Leader)
+	case conditionsMsg:
+		if msg.err != nil {
+			log.Println("leader: failed to evaluate the conditions:", msg.err)
+		}
+		results := strings.Split(msg.output, "\n")
+		for i, revset := range msg.revsets {
+			m.inRevset[revset] = msg.err == nil && i < len(results) && results[i] == "1"
+		}
+		if m.level != nil {
+			m.shown = m.visible()
+		}
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.cancel):
-			m.shown = nil
+			m.shown, m.level = nil, nil
 			return common.Close
 		}
 		for c := range maps.Values(m.shown) {
 			if key.Matches(msg, *c.Bind) {
 				if len(c.Nest) > 0 {
-					m.shown = contextEnabled(m.context, c.Nest)
-					return nil
+					return m.show(c.Nest)
 				}
-				m.shown = nil
-				cmds := sendCmds(c.Send)
-				return tea.Sequence(
-					common.Close,
-					tea.Sequence(cmds...),
-				)
+				m.shown, m.level = nil, nil
+				return tea.Sequence(common.Close, m.run(c))
 			}
 		}
+		if digit, ok := countDigit(msg, m.count); ok {
+			m.count = m.count*10 + digit
+		}
 	}
 	return nil
 }
 
-func contextEnabled(ctx *context.MainContext, bnds context.LeaderMap) context.LeaderMap {
+// run runs the Lua script of the entry with the count in the local variable
+// count, or repeats its custom command or keys count times
+func (m *Model) run(c *context.Leader) tea.Cmd {
+	count := max(m.count, 1)
+	if c.Lua != "" {
+		// on the first line so that the lines in errors stay the same
+		script := fmt.Sprintf("local count = %d; %s", count, c.Lua)
+		return func() tea.Msg {
+			return common.RunLuaScriptMsg{Script: script}
+		}
+	}
+	var cmds []tea.Cmd
+	for range count {
+		if c.Command != "" {
+			command, ok := m.context.CustomCommands[c.Command]
+			if !ok {
+				return func() tea.Msg {
+					return common.CommandCompletedMsg{Err: fmt.Errorf("leader: unknown custom command %q", c.Command)}
+				}
+			}
+			cmds = append(cmds, command.Prepare(m.context))
+			continue
+		}
+		cmds = append(cmds, sendCmds(c.Send)...)
+	}
+	return tea.Sequence(cmds...)
+}
+
+// countDigit returns the digit typed as part of the count. A count can't
+// start with 0.
+func countDigit(msg tea.KeyMsg, count int) (int, bool) {
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return 0, false
+	}
+	r := msg.Runes[0]
+	if r < '0' || r > '9' || (r == '0' && count == 0) {
+		return 0, false
+	}
+	return int(r - '0'), true
+}
+
+// show shows the entries of the level whose conditions hold. Those with revset
+// conditions not evaluated yet are shown once the returned command evaluates
+// them.
+func (m *Model) show(bnds context.LeaderMap) tea.Cmd {
+	m.level = m.enabled(bnds)
+	m.shown = m.visible()
+	return m.evaluate()
+}
+
+// enabled returns the entries whose placeholders can be replaced and whose
+// conditions, apart from the revset, hold for the selected item
+func (m *Model) enabled(bnds context.LeaderMap) context.LeaderMap {
 	bnds = maps.Clone(bnds)
-	replacementKeys := slices.Collect(maps.Keys(ctx.CreateReplacements()))
+	replacementKeys := slices.Collect(maps.Keys(m.context.CreateReplacements()))
 	maps.DeleteFunc(bnds, func(k string, v *context.Leader) bool {
 		if v == nil {
 			return true
@@ -89,11 +178,59 @@ func contextEnabled(ctx *context.MainContext, bnds context.LeaderMap) context.Le
 				return true
 			}
 		}
-		return false
+		return !m.holds(v.When)
 	})
 	return bnds
 }
 
+func (m *Model) holds(when context.LeaderCondition) bool {
+	if when.Item != "" && when.Item != context.ItemType(m.context.SelectedItem) {
+		return false
+	}
+	if when.Mode != "" && when.Mode != m.mode {
+		return false
+	}
+	if when.Revset == "" {
+		return true
+	}
+	_, ok := m.context.SelectedItem.(context.SelectedRevision)
+	return ok
+}
+
+// visible returns the entries of the level whose revset conditions are known
+// to hold
+func (m *Model) visible() context.LeaderMap {
+	shown := maps.Clone(m.level)
+	maps.DeleteFunc(shown, func(k string, v *context.Leader) bool {
+		return v.When.Revset != "" && !m.inRevset[v.When.Revset]
+	})
+	return shown
+}
+
+// evaluate checks in one command whether the selected revision is in the
+// revsets of the level that weren't evaluated yet
+func (m *Model) evaluate() tea.Cmd {
+	revision, ok := m.context.SelectedItem.(context.SelectedRevision)
+	if !ok {
+		return nil
+	}
+	var revsets []string
+	for _, entry := range m.level {
+		revset := entry.When.Revset
+		if _, known := m.inRevset[revset]; revset != "" && !known && !slices.Contains(revsets, revset) {
+			revsets = append(revsets, revset)
+		}
+	}
+	if len(revsets) == 0 {
+		return nil
+	}
+	slices.Sort(revsets)
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.ContainedIn(revision.CommitId, revsets))
+		return conditionsMsg{revsets: revsets, output: string(output), err: err}
+	}
+}
+
 func sendCmds(strings []string) []tea.Cmd {
 	var cmds []tea.Cmd
 	send := func(k tea.Key) {
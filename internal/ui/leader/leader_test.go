@@ -1,9 +1,12 @@
 package leader
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/test"
@@ -62,7 +65,7 @@ send = ["?"]
 	ctx := &context.MainContext{
 		Leader: lm,
 	}
-	model := New(ctx)
+	model := New(ctx, "normal")
 	_ = model.Update(initMsg{})
 	if len(model.shown) == 0 {
 		t.Fatal("expected shown leader keys")
@@ -99,7 +102,7 @@ send = ["gf", "enter"]
 	ctx := &context.MainContext{
 		Leader: lm,
 	}
-	model := New(ctx)
+	model := New(ctx, "normal")
 	_ = model.Update(initMsg{})
 	// Press 'g' to enter the submenu
 	msgG := tea.KeyMsg{
@@ -116,7 +119,7 @@ send = ["gf", "enter"]
 
 	// Test that key is available when its context is satified.
 	ctx.SelectedItem = context.SelectedRevision{ChangeId: "foo"}
-	model = New(ctx)
+	model = New(ctx, "normal")
 	_ = model.Update(initMsg{})
 	cmd = model.Update(msgG)
 	if cmd != nil {
@@ -151,7 +154,7 @@ send = ["?"]
 	ctx := &context.MainContext{
 		Leader: lm,
 	}
-	model := New(ctx)
+	model := New(ctx, "normal")
 	msg := tea.KeyMsg{
 		Type: tea.KeyEsc,
 	}
@@ -167,3 +170,137 @@ send = ["?"]
 		t.Errorf("expected CloseViewMsg, got %T", msgOut)
 	}
 }
+
+func TestUpdate_RunsCommandOfEntry(t *testing.T) {
+	lm := context.LeaderMap{}
+	context.AddLeaderCommand(lm, "sp", "push the stack", "stack push")
+	ctx := &context.MainContext{
+		Leader: lm,
+		CustomCommands: map[string]context.CustomCommand{
+			"stack push": context.CustomLuaCommand{Script: `flash("pushed")`},
+		},
+	}
+	model := New(ctx, "normal")
+	_ = model.Update(initMsg{})
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, test.Type("sp"), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, common.CloseViewMsg{})
+	assert.Contains(t, msgs, common.RunLuaScriptMsg{Script: `flash("pushed")`})
+}
+
+func TestUpdate_RunsLuaOfEntryWithCount(t *testing.T) {
+	lm, err := context.LoadLeader(`[leader.n]
+help = "New revisions"
+lua = "for i = 1, count do jj('new') end"
+`)
+	assert.NoError(t, err)
+	model := New(&context.MainContext{Leader: lm}, "normal")
+	_ = model.Update(initMsg{})
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, test.Type("12n"), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, common.RunLuaScriptMsg{Script: "local count = 12; for i = 1, count do jj('new') end"})
+}
+
+func TestUpdate_RepeatsKeysOfEntryWithCount(t *testing.T) {
+	lm, err := context.LoadLeader(`[leader.d]
+send = ["j"]
+`)
+	assert.NoError(t, err)
+	model := New(&context.MainContext{Leader: lm}, "normal")
+	_ = model.Update(initMsg{})
+
+	var keys []tea.Msg
+	test.SimulateModel(model, test.Type("3d"), func(msg tea.Msg) {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			keys = append(keys, msg)
+		}
+	})
+	j := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	assert.Equal(t, []tea.Msg{j, j, j}, keys[2:], "3 and d are typed first")
+}
+
+func TestUpdate_DigitBoundToEntryIsNotCount(t *testing.T) {
+	lm, err := context.LoadLeader(`[leader.1]
+send = ["j"]
+`)
+	assert.NoError(t, err)
+	model := New(&context.MainContext{Leader: lm}, "normal")
+	_ = model.Update(initMsg{})
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, test.Type("1"), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, common.CloseViewMsg{})
+	assert.Zero(t, model.count)
+}
+
+func TestUpdate_HidesEntriesWhoseConditionsDontHold(t *testing.T) {
+	lm, err := context.LoadLeader(`[leader.f]
+send = ["f"]
+when = { item = "file" }
+
+[leader.r]
+send = ["r"]
+when = { item = "revision", mode = "rebase" }
+
+[leader.m]
+send = ["m"]
+when = { revset = "mutable()" }
+
+[leader.i]
+send = ["i"]
+when = { revset = "immutable()" }
+`)
+	assert.NoError(t, err)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ContainedIn("abc", []string{"immutable()", "mutable()"})).SetOutput([]byte("0\n1\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Leader = lm
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "xyz", CommitId: "abc"}
+
+	model := New(ctx, "normal")
+	cmd := model.Update(initMsg{})
+	assert.Empty(t, model.shown, "the entries with revsets wait for the evaluation")
+	test.SimulateModel(model, cmd)
+	assert.ElementsMatch(t, []string{"m"}, slices.Collect(maps.Keys(model.shown)))
+
+	model = New(ctx, "rebase")
+	cmd = model.Update(initMsg{})
+	assert.ElementsMatch(t, []string{"r"}, slices.Collect(maps.Keys(model.shown)))
+	test.SimulateModel(model, cmd)
+	assert.ElementsMatch(t, []string{"m", "r"}, slices.Collect(maps.Keys(model.shown)))
+}
+
+func TestUpdate_EvaluatesRevsetsOfNestedLevelsOnce(t *testing.T) {
+	lm, err := context.LoadLeader(`[leader.g]
+help = "Git"
+
+[leader.gp]
+send = ["p"]
+when = { revset = "mutable()" }
+
+[leader.m]
+send = ["m"]
+when = { revset = "mutable()" }
+`)
+	assert.NoError(t, err)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ContainedIn("abc", []string{"mutable()"})).SetOutput([]byte("1\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Leader = lm
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "xyz", CommitId: "abc"}
+
+	model := New(ctx, "normal")
+	test.SimulateModel(model, model.Update(initMsg{}))
+	assert.Nil(t, model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}), "the result is reused")
+	assert.ElementsMatch(t, []string{"p"}, slices.Collect(maps.Keys(model.shown)))
+}
@@ -2,6 +2,7 @@ package details
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"path"
 	"reflect"
@@ -66,6 +67,13 @@ func (s *Operation) Init() tea.Cmd {
 
 func (s *Operation) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
+	case common.ConfigReloadedMsg:
+		s.keyMap = config.Current.GetKeyMap()
+		s.keymap = s.keyMap
+		s.styles = newStyles()
+		s.DetailsList.styles = s.styles
+		s.targetMarkerStyle = common.DefaultPalette.Get("revisions details target_marker")
+		return nil
 	case confirmation.CloseMsg:
 		s.confirmation = nil
 		s.selectedHint = ""
@@ -171,6 +179,10 @@ func (s *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 		return s.handleIntent(intents.DetailsToggleSelect{})
 	case key.Matches(msg, s.keyMap.Details.RevisionsChangingFile):
 		return s.handleIntent(intents.DetailsRevisionsChangingFile{})
+	case key.Matches(msg, s.keyMap.Details.PickHunks):
+		return s.handleIntent(intents.DetailsPickHunks{})
+	case key.Matches(msg, s.keyMap.Details.ResolveConflicts):
+		return s.handleIntent(intents.DetailsResolveConflicts{})
 	}
 	return nil
 }
@@ -196,7 +208,7 @@ func (s *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 			return nil
 		}
 		return func() tea.Msg {
-			output, _ := s.context.RunCommandImmediate(jj.Diff(s.revision.GetChangeId(), selected.fileName))
+			output, _ := s.context.RunCommandImmediate(jj.DiffGit(s.revision.GetChangeId(), selected.fileName))
 			return common.ShowDiffMsg(output)
 		}
 	case intents.DetailsSplit:
@@ -284,6 +296,25 @@ func (s *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 			return tea.Batch(common.Close, common.UpdateRevSet(fmt.Sprintf("files(%s)", jj.EscapeFileName(current.fileName))))
 		}
 		return nil
+	case intents.DetailsPickHunks:
+		// checked files narrow down the picker, otherwise all files are shown
+		selectedFiles := s.getSelectedFiles(false)
+		revision := s.revision
+		return func() tea.Msg {
+			output, err := s.context.RunCommandImmediate(jj.DiffGit(revision.GetChangeId(), selectedFiles...))
+			if err != nil {
+				return common.CommandCompletedMsg{Output: string(output), Err: err}
+			}
+			return common.ShowHunkPickerMsg{Revision: revision, Diff: string(output)}
+		}
+	case intents.DetailsResolveConflicts:
+		files := s.getConflictedFiles()
+		if len(files) == 0 {
+			return intents.Invoke(intents.AddMessage{Err: errors.New("no conflicted files")})
+		}
+		return func() tea.Msg {
+			return common.ShowConflictsMsg{Revision: s.revision, Files: files}
+		}
 	}
 	return nil
 }
@@ -331,6 +362,8 @@ func (s *Operation) ShortHelp() []key.Binding {
 		s.keyMap.Details.Restore,
 		s.keyMap.Details.Absorb,
 		s.keyMap.Details.RevisionsChangingFile,
+		s.keyMap.Details.PickHunks,
+		s.keyMap.Details.ResolveConflicts,
 	}
 }
 
@@ -424,6 +457,25 @@ func (s *Operation) getSelectedFiles(allowVirtualSelection bool) []string {
 	return selectedFiles
 }
 
+// getConflictedFiles returns the checked conflicted files, or all of them when
+// none of the conflicted files are checked
+func (s *Operation) getConflictedFiles() []string {
+	var all, selected []string
+	for _, f := range s.files {
+		if !f.conflict {
+			continue
+		}
+		all = append(all, f.fileName)
+		if f.selected {
+			selected = append(selected, f.fileName)
+		}
+	}
+	if len(selected) > 0 {
+		return selected
+	}
+	return all
+}
+
 func (s *Operation) createListItems(content string, selectedFiles []string) []*item {
 	var items []*item
 	scanner := bufio.NewScanner(strings.NewReader(content))
@@ -497,10 +549,8 @@ func (s *Operation) load(revision string) tea.Cmd {
 	}
 }
 
-func NewOperation(context *context.MainContext, selected *jj.Commit) *Operation {
-	keyMap := config.Current.GetKeyMap()
-
-	s := styles{
+func newStyles() styles {
+	return styles{
 		Added:    common.DefaultPalette.Get("revisions details added"),
 		Deleted:  common.DefaultPalette.Get("revisions details deleted"),
 		Modified: common.DefaultPalette.Get("revisions details modified"),
@@ -511,7 +561,12 @@ func NewOperation(context *context.MainContext, selected *jj.Commit) *Operation
 		Text:     common.DefaultPalette.Get("revisions details text"),
 		Conflict: common.DefaultPalette.Get("revisions details conflict"),
 	}
+}
+
+func NewOperation(context *context.MainContext, selected *jj.Commit) *Operation {
+	keyMap := config.Current.GetKeyMap()
 
+	s := newStyles()
 	l := NewDetailsList(s)
 	op := &Operation{
 		DetailsList:       l,
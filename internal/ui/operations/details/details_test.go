@@ -212,3 +212,44 @@ A test/file4`
 	files := model.createListItems(content, nil)
 	assert.Len(t, files, 4)
 }
+
+func TestModel_Update_PicksHunksOfSelectedFiles(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffGit(Revision, "file.txt")).SetOutput([]byte("diff"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	test.SimulateModel(model, model.Init())
+
+	var picker *common.ShowHunkPickerMsg
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	test.SimulateModel(model, test.Type("H"), func(msg tea.Msg) {
+		if msg, ok := msg.(common.ShowHunkPickerMsg); ok {
+			picker = &msg
+		}
+	})
+	assert.NotNil(t, picker)
+	assert.Equal(t, "diff", picker.Diff)
+}
+
+func TestModel_Update_ResolvesConflictedFiles(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("true false $\nM file.txt\nA newfile.txt\n"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	test.SimulateModel(model, model.Init())
+	assert.Contains(t, test.RenderImmediate(model, 100, 20), "conflict")
+
+	var conflicts *common.ShowConflictsMsg
+	test.SimulateModel(model, test.Type("R"), func(msg tea.Msg) {
+		if msg, ok := msg.(common.ShowConflictsMsg); ok {
+			conflicts = &msg
+		}
+	})
+	assert.NotNil(t, conflicts)
+	assert.Equal(t, []string{"file.txt"}, conflicts.Files)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dryrun"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
@@ -105,9 +106,10 @@ func (r *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 		return common.StartAceJump()
 	case intents.DuplicateSetTarget:
 		r.Target = duplicateTargetFromIntent(msg.Target)
+	case intents.PreviewResult:
+		return dryrun.Show(r.command())
 	case intents.Apply:
-		target := targetToFlags[r.Target]
-		return r.context.RunCommand(jj.Duplicate(r.From, r.targetArg(), target), common.RefreshAndSelect(r.From.Last()), common.Close)
+		return r.context.RunCommand(r.command(), common.RefreshAndSelect(r.From.Last()), common.Close)
 	case intents.Cancel:
 		return common.Close
 	default:
@@ -116,6 +118,11 @@ func (r *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 	return nil
 }
 
+// command is the jj command the duplicate runs with
+func (r *Operation) command() jj.CommandArgs {
+	return jj.Duplicate(r.From, r.targetArg(), targetToFlags[r.Target])
+}
+
 func duplicateTargetFromIntent(target intents.DuplicateTarget) Target {
 	switch target {
 	case intents.DuplicateTargetDestination:
@@ -145,6 +152,8 @@ func (r *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, r.keyMap.Duplicate.Target):
 		r.targetPicker = target_picker.NewModel(r.context)
 		return r.targetPicker.Init()
+	case key.Matches(msg, r.keyMap.Duplicate.PreviewResult):
+		return r.handleIntent(intents.PreviewResult{})
 	case key.Matches(msg, r.keyMap.Apply):
 		return r.handleIntent(intents.Apply{})
 	case key.Matches(msg, r.keyMap.Cancel):
@@ -166,6 +175,7 @@ func (r *Operation) ShortHelp() []key.Binding {
 		r.keyMap.Duplicate.Before,
 		r.keyMap.Duplicate.Onto,
 		r.keyMap.Duplicate.Target,
+		r.keyMap.Duplicate.PreviewResult,
 		r.keyMap.AceJump,
 	}
 }
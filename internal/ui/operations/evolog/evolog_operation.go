@@ -193,7 +193,7 @@ func (o *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 		}
 		return func() tea.Msg {
 			selectedCommitId := o.getSelectedEvolog().CommitId
-			output, _ := o.context.RunCommandImmediate(jj.Diff(selectedCommitId, ""))
+			output, _ := o.context.RunCommandImmediate(jj.DiffGit(selectedCommitId))
 			return common.ShowDiffMsg(output)
 		}
 	case intents.EvologRestore:
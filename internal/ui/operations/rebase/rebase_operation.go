@@ -14,6 +14,7 @@ import (
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dryrun"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
@@ -144,16 +145,10 @@ func (r *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 		}
 	case intents.RebaseToggleSkipEmptied:
 		r.SkipEmptied = !r.SkipEmptied
+	case intents.PreviewResult:
+		return dryrun.Show(r.command(false))
 	case intents.Apply:
-		skipEmptied := r.SkipEmptied
-		if r.Target == TargetInsert {
-			insertAfter := r.InsertStart.GetChangeId()
-			insertBefore := r.targetArg()
-			return r.context.RunCommand(jj.RebaseInsert(r.From, insertAfter, insertBefore, skipEmptied, msg.Force), common.RefreshAndSelect(r.From.Last()), common.Close)
-		}
-		source := sourceToFlags[r.Source]
-		target := targetToFlags[r.Target]
-		return r.context.RunCommand(jj.Rebase(r.From, r.targetArg(), source, target, skipEmptied, msg.Force), common.RefreshAndSelect(r.From.Last()), common.Close)
+		return r.context.RunCommand(r.command(msg.Force), common.RefreshAndSelect(r.From.Last()), common.Close)
 	case intents.Cancel:
 		return common.Close
 	default:
@@ -162,6 +157,18 @@ func (r *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 	return nil
 }
 
+// command is the jj command the rebase runs with
+func (r *Operation) command(force bool) jj.CommandArgs {
+	if r.Target == TargetInsert {
+		insertAfter := r.InsertStart.GetChangeId()
+		insertBefore := r.targetArg()
+		return jj.RebaseInsert(r.From, insertAfter, insertBefore, r.SkipEmptied, force)
+	}
+	source := sourceToFlags[r.Source]
+	target := targetToFlags[r.Target]
+	return jj.Rebase(r.From, r.targetArg(), source, target, r.SkipEmptied, force)
+}
+
 func rebaseSourceFromIntent(source intents.RebaseSource) Source {
 	switch source {
 	case intents.RebaseSourceRevision:
@@ -216,6 +223,8 @@ func (r *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 		return r.handleIntent(intents.RebaseSetTarget{Target: intents.RebaseTargetInsert})
 	case key.Matches(msg, r.keyMap.Rebase.SkipEmptied):
 		return r.handleIntent(intents.RebaseToggleSkipEmptied{})
+	case key.Matches(msg, r.keyMap.Rebase.PreviewResult):
+		return r.handleIntent(intents.PreviewResult{})
 	case key.Matches(msg, r.keyMap.Apply, r.keyMap.ForceApply):
 		return r.handleIntent(intents.Apply{Force: key.Matches(msg, r.keyMap.ForceApply)})
 	case key.Matches(msg, r.keyMap.Cancel):
@@ -266,6 +275,7 @@ func (r *Operation) ShortHelp() []key.Binding {
 		r.keyMap.Rebase.Insert,
 		r.keyMap.Rebase.Target,
 		r.keyMap.Rebase.SkipEmptied,
+		r.keyMap.Rebase.PreviewResult,
 		r.keyMap.AceJump,
 	}
 }
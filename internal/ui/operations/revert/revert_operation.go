@@ -12,6 +12,7 @@ import (
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dryrun"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
@@ -110,15 +111,10 @@ func (r *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 		if r.Target == TargetInsert {
 			r.InsertStart = r.To
 		}
+	case intents.PreviewResult:
+		return dryrun.Show(r.command())
 	case intents.Apply:
-		if r.Target == TargetInsert {
-			insertAfter := r.InsertStart.GetChangeId()
-			insertBefore := r.targetArg()
-			return r.context.RunCommand(jj.RevertInsert(r.From, insertAfter, insertBefore), common.RefreshAndSelect(r.From.Last()), common.Close)
-		}
-		source := "--revisions"
-		target := targetToFlags[r.Target]
-		return r.context.RunCommand(jj.Revert(r.From, r.targetArg(), source, target), common.RefreshAndSelect(r.From.Last()), common.Close)
+		return r.context.RunCommand(r.command(), common.RefreshAndSelect(r.From.Last()), common.Close)
 	case intents.Cancel:
 		return common.Close
 	default:
@@ -127,6 +123,18 @@ func (r *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 	return nil
 }
 
+// command is the jj command the revert runs with
+func (r *Operation) command() jj.CommandArgs {
+	if r.Target == TargetInsert {
+		insertAfter := r.InsertStart.GetChangeId()
+		insertBefore := r.targetArg()
+		return jj.RevertInsert(r.From, insertAfter, insertBefore)
+	}
+	source := "--revisions"
+	target := targetToFlags[r.Target]
+	return jj.Revert(r.From, r.targetArg(), source, target)
+}
+
 func revertTargetFromIntent(target intents.RevertTarget) Target {
 	switch target {
 	case intents.RevertTargetDestination:
@@ -158,6 +166,8 @@ func (r *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, r.keyMap.Revert.Target):
 		r.targetPicker = target_picker.NewModel(r.context)
 		return r.targetPicker.Init()
+	case key.Matches(msg, r.keyMap.Revert.PreviewResult):
+		return r.handleIntent(intents.PreviewResult{})
 	case key.Matches(msg, r.keyMap.Apply):
 		return r.handleIntent(intents.Apply{})
 	case key.Matches(msg, r.keyMap.Cancel):
@@ -182,6 +192,7 @@ func (r *Operation) ShortHelp() []key.Binding {
 		r.keyMap.Revert.Onto,
 		r.keyMap.Revert.Insert,
 		r.keyMap.Revert.Target,
+		r.keyMap.Revert.PreviewResult,
 	}
 }
 
@@ -1,6 +1,7 @@
 package squash
 
 import (
+	"os"
 	"slices"
 	"strings"
 
@@ -38,6 +39,8 @@ type Operation struct {
 	keepEmptied           bool
 	useDestinationMessage bool
 	interactive           bool
+	diffEditorArgs        jj.CommandArgs
+	selectionDir          string
 	styles                styles
 }
 
@@ -92,14 +95,15 @@ func (s *Operation) handleIntent(intent intents.Intent) tea.Cmd {
 	case intents.StartAceJump:
 		return common.StartAceJump()
 	case intents.Apply:
-		args := jj.Squash(s.from, s.targetArg(), s.files, s.keepEmptied, s.useDestinationMessage, s.interactive, intent.Force)
-		continuation := common.RefreshAndSelect(s.current.GetChangeId())
+		args := jj.Squash(s.from, s.targetArg(), s.files, s.keepEmptied, s.useDestinationMessage, s.interactive || s.diffEditorArgs != nil, intent.Force)
+		args = append(args, s.diffEditorArgs...)
+		continuation := tea.Batch(common.RefreshAndSelect(s.current.GetChangeId()), s.removeSelection())
 		if s.interactive || !s.useDestinationMessage {
 			return tea.Batch(common.Close, s.context.RunInteractiveCommand(args, continuation))
 		}
 		return tea.Batch(common.Close, s.context.RunCommand(args, continuation))
 	case intents.Cancel:
-		return common.Close
+		return tea.Batch(common.Close, s.removeSelection())
 	case intents.SquashToggleKeepEmptied:
 		s.keepEmptied = !s.keepEmptied
 	case intents.SquashToggleUseDestinationMessage:
@@ -166,7 +170,9 @@ func (s *Operation) Render(commit *jj.Commit, pos operations.RenderPosition) str
 		if s.keepEmptied {
 			marker = "<< keep empty >>"
 		}
-		if s.interactive {
+		if s.diffEditorArgs != nil {
+			marker += " (selected hunks)"
+		} else if s.interactive {
 			marker += " (interactive)"
 		}
 		return s.styles.sourceMarker.Render(marker)
@@ -221,6 +227,27 @@ func WithFiles(files []string) Option {
 	}
 }
 
+// WithDiffEditorArgs squashes the content prepared in dir for jj.SelectionToolArgs
+// instead of opening the diff editor. dir is removed when the squash ends.
+func WithDiffEditorArgs(args jj.CommandArgs, dir string) Option {
+	return func(op *Operation) {
+		op.diffEditorArgs = args
+		op.selectionDir = dir
+	}
+}
+
+// removeSelection removes the directory of the prepared content, if any
+func (s *Operation) removeSelection() tea.Cmd {
+	if s.selectionDir == "" {
+		return nil
+	}
+	dir := s.selectionDir
+	return func() tea.Msg {
+		_ = os.RemoveAll(dir)
+		return nil
+	}
+}
+
 func NewOperation(context *context.MainContext, from jj.SelectedRevisions, opts ...Option) *Operation {
 	styles := styles{
 		dimmed:       common.DefaultPalette.Get("squash dimmed"),
@@ -0,0 +1,42 @@
+package squash
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	source    = &jj.Commit{ChangeId: "a"}
+	target    = &jj.Commit{ChangeId: "b"}
+	revisions = jj.NewSelectedRevisions(source)
+	files     = []string{"file.txt"}
+)
+
+func Test_Cancel_RemovesSelection(t *testing.T) {
+	dir := t.TempDir()
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), revisions, WithFiles(files), WithDiffEditorArgs(jj.SelectionToolArgs(dir, nil), dir))
+	model.SetSelectedRevision(target)
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+	assert.NoDirExists(t, dir)
+}
+
+func Test_Apply_RemovesSelectionWhenSquashFails(t *testing.T) {
+	dir := t.TempDir()
+	tool := jj.SelectionToolArgs(dir, nil)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(append(jj.Squash(revisions, "b", files, false, false, true, false), tool...)).SetError(errors.New("failed"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), revisions, WithFiles(files), WithDiffEditorArgs(tool, dir))
+	model.SetSelectedRevision(target)
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	assert.NoDirExists(t, dir)
+}
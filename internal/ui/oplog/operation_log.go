@@ -2,6 +2,7 @@ package oplog
 
 import (
 	"bytes"
+	"slices"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -40,6 +41,7 @@ type Model struct {
 	listRenderer     *render.ListRenderer
 	rows             []row
 	cursor           int
+	checked          []string
 	keymap           config.KeyMappings[key.Binding]
 	textStyle        lipgloss.Style
 	selectedStyle    lipgloss.Style
@@ -72,6 +74,7 @@ func (m *Model) ShortHelp() []key.Binding {
 		m.keymap.ScrollDown,
 		m.keymap.Quit,
 		m.keymap.Cancel,
+		m.keymap.ToggleSelect,
 		m.keymap.Diff,
 		m.keymap.OpLog.Restore,
 		m.keymap.OpLog.Revert,
@@ -98,8 +101,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case intents.Intent:
 		return m.handleIntent(msg)
+	case common.ConfigReloadedMsg:
+		m.keymap = config.Current.GetKeyMap()
+		m.textStyle = common.DefaultPalette.Get("oplog text")
+		m.selectedStyle = common.DefaultPalette.Get("oplog selected")
 	case updateOpLogMsg:
 		m.rows = msg.Rows
+		m.checked = slices.DeleteFunc(m.checked, func(id string) bool { return m.rowIndex(id) == -1 })
 		return m.updateSelection()
 	case OpLogClickedMsg:
 		if msg.Index >= 0 && msg.Index < len(m.rows) {
@@ -124,6 +132,8 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		return m.navigate(intent.Delta, intent.IsPage)
 	case intents.OpLogClose:
 		return m.close()
+	case intents.OpLogToggleCheck:
+		return m.toggleCheck(intent)
 	case intents.OpLogShowDiff:
 		return m.showDiff(intent)
 	case intents.OpLogRestore:
@@ -136,8 +146,13 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 
 func (m *Model) keyToIntent(msg tea.KeyMsg) tea.Cmd {
 	switch {
+	case key.Matches(msg, m.keymap.Cancel) && len(m.checked) > 0:
+		m.checked = nil
+		return m.updateSelection()
 	case key.Matches(msg, m.keymap.Cancel):
 		return m.handleIntent(intents.OpLogClose{})
+	case key.Matches(msg, m.keymap.ToggleSelect):
+		return m.handleIntent(intents.OpLogToggleCheck{})
 	case key.Matches(msg, m.keymap.Up, m.keymap.ScrollUp):
 		return m.handleIntent(intents.OpLogNavigate{
 			Delta:  -1,
@@ -195,15 +210,59 @@ func (m *Model) updateSelection() tea.Cmd {
 	if len(m.rows) == 0 {
 		return nil
 	}
+	if from, to, ok := m.checkedRange(); ok {
+		return m.context.SetSelectedItem(context.SelectedOperationRange{From: from, To: to})
+	}
 	return m.context.SetSelectedItem(context.SelectedOperation{OperationId: m.rows[m.cursor].OperationId})
 }
 
+func (m *Model) rowIndex(operationId string) int {
+	return slices.IndexFunc(m.rows, func(r row) bool { return r.OperationId == operationId })
+}
+
+// toggleCheck checks or unchecks an operation. At most two operations are
+// checked; checking a third one unchecks the one checked first.
+func (m *Model) toggleCheck(intent intents.OpLogToggleCheck) tea.Cmd {
+	opId := intent.OperationId
+	if opId == "" {
+		if len(m.rows) == 0 {
+			return nil
+		}
+		opId = m.rows[m.cursor].OperationId
+	}
+	if i := slices.Index(m.checked, opId); i != -1 {
+		m.checked = slices.Delete(m.checked, i, i+1)
+	} else {
+		m.checked = append(m.checked, opId)
+		if len(m.checked) > 2 {
+			m.checked = m.checked[1:]
+		}
+	}
+	return m.updateSelection()
+}
+
+// checkedRange returns the two checked operations, older one first. The op log
+// lists the most recent operation at the top.
+func (m *Model) checkedRange() (string, string, bool) {
+	if len(m.checked) != 2 {
+		return "", "", false
+	}
+	from, to := m.checked[0], m.checked[1]
+	if m.rowIndex(from) < m.rowIndex(to) {
+		from, to = to, from
+	}
+	return from, to, true
+}
+
 func (m *Model) close() tea.Cmd {
 	return tea.Batch(common.Close, common.Refresh, common.SelectionChanged(m.context.SelectedItem))
 }
 
 func (m *Model) showDiff(intent intents.OpLogShowDiff) tea.Cmd {
 	opId := intent.OperationId
+	if from, to, ok := m.checkedRange(); ok && opId == "" {
+		return m.showRangeDiff(from, to)
+	}
 	if opId == "" {
 		if len(m.rows) == 0 {
 			return nil
@@ -216,6 +275,19 @@ func (m *Model) showDiff(intent intents.OpLogShowDiff) tea.Cmd {
 	}
 }
 
+// showRangeDiff shows the changes between two operations, headed by the
+// revisions they added, rewrote and abandoned and the bookmarks they moved
+func (m *Model) showRangeDiff(from string, to string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.OpDiff(from, to))
+		if err != nil {
+			return intents.AddMessage{Text: "Failed to diff operations", Err: err}
+		}
+		summary := jj.ParseOpDiffSummary(string(output))
+		return common.ShowDiffMsg(summary.Changes() + "\n\n" + string(output))
+	}
+}
+
 func (m *Model) restore(intent intents.OpLogRestore) tea.Cmd {
 	opId := intent.OperationId
 	if opId == "" {
@@ -257,10 +329,16 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 			styleOverride = m.selectedStyle
 		}
 
+		isChecked := slices.Contains(m.checked, row.OperationId)
+
 		y := itemRect.Min.Y
 		for _, line := range row.Lines {
 			var content bytes.Buffer
-			for _, segment := range line.Segments {
+			idIndex := line.FindIdIndex()
+			for i, segment := range line.Segments {
+				if isChecked && i == idIndex {
+					content.WriteString(m.selectedStyle.Render("✓ "))
+				}
 				content.WriteString(segment.Style.Inherit(styleOverride).Render(segment.Text))
 			}
 			lineContent := lipgloss.PlaceHorizontal(itemRect.Dx(), 0, content.String(), lipgloss.WithWhitespaceBackground(styleOverride.GetBackground()))
@@ -1,14 +1,17 @@
 package oplog
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/render"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -137,3 +140,51 @@ func TestOpLogNavigateIntent(t *testing.T) {
 
 	assert.Equal(t, 0, m.cursor, "expected cursor to move back to 0")
 }
+
+func TestToggleCheck_SelectsRangeOfTwoOperations(t *testing.T) {
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	m.rows = []row{{OperationId: "op3"}, {OperationId: "op2"}, {OperationId: "op1"}}
+
+	test.SimulateModel(m, test.Type(" "))
+	assert.Equal(t, context.SelectedOperation{OperationId: "op3"}, m.context.SelectedItem)
+
+	m.SetCursor(2)
+	test.SimulateModel(m, test.Type(" "))
+	assert.Equal(t, context.SelectedOperationRange{From: "op1", To: "op3"}, m.context.SelectedItem)
+
+	m.SetCursor(1)
+	test.SimulateModel(m, test.Type(" "))
+	assert.Equal(t, []string{"op1", "op2"}, m.checked)
+	assert.Equal(t, context.SelectedOperationRange{From: "op1", To: "op2"}, m.context.SelectedItem)
+
+	test.SimulateModel(m, test.Press(tea.KeyEsc))
+	assert.Empty(t, m.checked)
+	assert.Equal(t, context.SelectedOperation{OperationId: "op2"}, m.context.SelectedItem)
+}
+
+func TestShowDiff_DiffsCheckedOperations(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpDiff("op1", "op3")).SetOutput([]byte("Changed commits:\n○  + kxqpmswn 5e6f7a8b feature\n○  + zzzzmpyr 9a8b7c6d\n│  - zzzzmpyr/1 1a2b3c4d\n"))
+	defer commandRunner.Verify()
+
+	m := New(test.NewTestContext(commandRunner))
+	m.rows = []row{{OperationId: "op3"}, {OperationId: "op2"}, {OperationId: "op1"}}
+	m.checked = []string{"op3", "op1"}
+
+	var diff common.ShowDiffMsg
+	test.SimulateModel(m, test.Type("d"), func(msg tea.Msg) {
+		if got, ok := msg.(common.ShowDiffMsg); ok {
+			diff = got
+		}
+	})
+	assert.True(t, strings.HasPrefix(string(diff), "1 added, 1 rewritten, 0 abandoned, no bookmarks moved\nAdded: kxqpmswn\nRewritten: zzzzmpyr\n\nChanged commits:"), string(diff))
+}
+
+func TestConfigReloadRebuildsStyles(t *testing.T) {
+	t.Cleanup(common.DefaultPalette.Reset)
+	m := New(&context.MainContext{})
+
+	common.DefaultPalette.Update(map[string]config.Color{"oplog text": {Fg: "red"}})
+	m.Update(common.ConfigReloadedMsg{})
+	assert.Equal(t, common.DefaultPalette.Get("oplog text").GetForeground(), m.textStyle.GetForeground())
+}
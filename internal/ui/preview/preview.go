@@ -214,6 +214,8 @@ func (m *Model) refreshPreviewForItem(item common.SelectedItem) tea.Cmd {
 				jj.OperationIdPlaceholder:  sel.OperationId,
 				jj.PreviewWidthPlaceholder: previewWidth,
 			})
+		case common.SelectedOperationRange:
+			args = jj.OpDiff(sel.From, sel.To)
 		}
 
 		output, _ := m.context.RunCommandImmediate(args)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/forge"
 	"github.com/idursun/jjui/internal/parser"
 	"github.com/idursun/jjui/internal/screen"
 	"github.com/idursun/jjui/internal/ui/common"
@@ -21,6 +22,15 @@ type DisplayContextRenderer struct {
 	dimmedStyle   lipgloss.Style
 	selectedStyle lipgloss.Style
 	matchedStyle  lipgloss.Style
+	// pullRequests maps bookmark names to their pull requests on the forge
+	pullRequests map[string]forge.PullRequest
+	// bookmarkTargets maps the local bookmarks to the commits they point to
+	bookmarkTargets   map[string]string
+	pullRequestStyles pullRequestStyles
+	// draggable registers the rows to be dragged onto each other
+	draggable bool
+	// rowRects are the screen rectangles of the rows drawn the last time
+	rowRects map[int]cellbuf.Rectangle
 }
 
 // itemRenderer is a helper for rendering individual revision items
@@ -90,19 +100,58 @@ func (ir itemRenderer) renderSegmentForLine(tb *render.TextBuilder, segment *scr
 // NewDisplayContextRenderer creates a new DisplayContext-based renderer
 func NewDisplayContextRenderer(textStyle, dimmedStyle, selectedStyle, matchedStyle lipgloss.Style) *DisplayContextRenderer {
 	return &DisplayContextRenderer{
-		listRenderer:  render.NewListRenderer(ViewportScrollMsg{}),
-		textStyle:     textStyle,
-		dimmedStyle:   dimmedStyle,
-		selectedStyle: selectedStyle,
-		matchedStyle:  matchedStyle,
+		listRenderer:      render.NewListRenderer(ViewportScrollMsg{}),
+		textStyle:         textStyle,
+		dimmedStyle:       dimmedStyle,
+		selectedStyle:     selectedStyle,
+		matchedStyle:      matchedStyle,
+		pullRequestStyles: newPullRequestStyles(),
 	}
 }
 
+// SetStyles replaces the styles after the palette is rebuilt
+func (r *DisplayContextRenderer) SetStyles(textStyle, dimmedStyle, selectedStyle, matchedStyle lipgloss.Style) {
+	r.textStyle = textStyle
+	r.dimmedStyle = dimmedStyle
+	r.selectedStyle = selectedStyle
+	r.matchedStyle = matchedStyle
+	r.pullRequestStyles = newPullRequestStyles()
+}
+
 // SetSelections sets the selected revisions for rendering checkboxes
 func (r *DisplayContextRenderer) SetSelections(selections map[string]bool) {
 	r.selections = selections
 }
 
+// SetPullRequests sets the pull requests rendered next to their bookmarks and
+// the commits the bookmarks point to
+func (r *DisplayContextRenderer) SetPullRequests(pullRequests map[string]forge.PullRequest, targets map[string]string) {
+	r.pullRequests = pullRequests
+	r.bookmarkTargets = targets
+}
+
+// SetDraggable sets whether the rows can be dragged
+func (r *DisplayContextRenderer) SetDraggable(draggable bool) {
+	r.draggable = draggable
+}
+
+// RowRect returns where the row was drawn the last time, if it was visible
+func (r *DisplayContextRenderer) RowRect(index int) (cellbuf.Rectangle, bool) {
+	rect, ok := r.rowRects[index]
+	return rect, ok
+}
+
+// IndexAt returns the row drawn at the screen position, or -1 when there is none
+func (r *DisplayContextRenderer) IndexAt(x, y int) int {
+	pos := cellbuf.Pos(x, y)
+	for index, rect := range r.rowRects {
+		if pos.In(rect) {
+			return index
+		}
+	}
+	return -1
+}
+
 // Render renders the revisions list to a DisplayContext
 func (r *DisplayContextRenderer) Render(
 	dl *render.DisplayContext,
@@ -113,6 +162,7 @@ func (r *DisplayContextRenderer) Render(
 	quickSearch string,
 	ensureCursorVisible bool,
 ) {
+	r.rowRects = make(map[int]cellbuf.Rectangle)
 	if len(items) == 0 {
 		return
 	}
@@ -139,6 +189,11 @@ func (r *DisplayContextRenderer) Render(
 		if isSelected {
 			r.addHighlights(dl, item, rect, operation)
 		}
+
+		r.rowRects[index] = rect
+		if r.draggable {
+			dl.AddInteraction(rect, RowDragMsg{Index: index}, render.InteractionDrag, 0)
+		}
 	}
 
 	// Click message factory
@@ -217,8 +272,11 @@ func (r *DisplayContextRenderer) calculateItemHeight(
 	isSelected bool,
 	operation operations.Operation,
 ) int {
-	// Base height from the item's lines
-	height := len(item.Lines)
+	if item.Hidden {
+		return 0
+	}
+	// Base height from the item's lines and its folded rows
+	height := rowHeight(item)
 
 	// Add operation height if item is selected and operation exists
 	if isSelected && operation != nil {
@@ -476,6 +534,24 @@ func (r *DisplayContextRenderer) renderItemToDisplayContext(
 	if !afterRendered {
 		renderAfter()
 	}
+
+	// Render the marker of the rows folded into this one
+	if item.Folded > 0 && y < rect.Max.Y {
+		lineRect := cellbuf.Rect(rect.Min.X, y, rect.Dx(), 1)
+		r.renderOperationLine(dl, lineRect, item.Extend(), r.dimmedStyle.Render(foldedText(item.Folded)))
+	}
+}
+
+// rowHeight returns the number of lines a row takes without an operation
+func rowHeight(row parser.Row) int {
+	switch {
+	case row.Hidden:
+		return 0
+	case row.Folded > 0:
+		return len(row.Lines) + 1
+	default:
+		return len(row.Lines)
+	}
 }
 
 // renderLine writes a line into a TextBuilder (helper for itemRenderer)
@@ -520,6 +596,11 @@ func (ir *itemRenderer) renderLine(tb *render.TextBuilder, line *parser.GraphRow
 			}
 		}
 		ir.renderSegmentForLine(tb, segment, lineIsHighlightable)
+		if line.Flags&parser.Revision == parser.Revision {
+			if pr, ok := ir.renderer.pullRequestFor(segment.Text, ir.row.Commit); ok {
+				ir.renderer.renderPullRequest(tb, pr)
+			}
+		}
 	}
 
 	// Add affected marker
@@ -601,3 +682,14 @@ func (r *DisplayContextRenderer) GetFirstRowIndex() int {
 func (r *DisplayContextRenderer) GetLastRowIndex() int {
 	return r.listRenderer.GetLastRowIndex()
 }
+
+// ShiftRows keeps the same rows in view after the given number of rows, taking
+// the given number of lines, are added to (positive) or dropped from
+// (negative) the top of the list.
+func (r *DisplayContextRenderer) ShiftRows(rows int, lines int) {
+	r.listRenderer.StartLine = max(r.listRenderer.StartLine+lines, 0)
+	if r.listRenderer.FirstRowIndex != -1 {
+		r.listRenderer.FirstRowIndex = max(r.listRenderer.FirstRowIndex+rows, 0)
+		r.listRenderer.LastRowIndex = max(r.listRenderer.LastRowIndex+rows, 0)
+	}
+}
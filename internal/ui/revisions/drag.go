@@ -0,0 +1,161 @@
+package revisions
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/operations/rebase"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// RowDragMsg is sent when the mouse is pressed on a row. It selects the row
+// like a click and starts dragging its revision, or the checked revisions when
+// the row is one of them, until the mouse is released.
+type RowDragMsg struct {
+	Index int
+	X     int
+	Y     int
+}
+
+func (m RowDragMsg) SetDragStart(x, y int) tea.Msg {
+	m.X = x
+	m.Y = y
+	return m
+}
+
+// drag is the revisions being dragged onto another row. Releasing the mouse on
+// a row rebases them onto it, after it with alt, before it with ctrl or
+// inserts them between it and the row above with ctrl+alt.
+type drag struct {
+	from   jj.SelectedRevisions
+	source int
+	// target is the row under the mouse, -1 when the revisions can't be
+	// dropped there
+	target int
+	mode   rebase.Target
+}
+
+type dragStyles struct {
+	target lipgloss.Style
+	marker lipgloss.Style
+}
+
+func newDragStyles() dragStyles {
+	return dragStyles{
+		target: common.DefaultPalette.Get("revisions drop_target"),
+		marker: common.DefaultPalette.Get("revisions target_marker"),
+	}
+}
+
+// Dragging returns whether revisions are being dragged, in which case the
+// mouse motion and release events should be sent to the model
+func (m *Model) Dragging() bool {
+	return m.drag != nil
+}
+
+func (m *Model) startDrag(msg RowDragMsg) tea.Cmd {
+	if msg.Index < 0 || msg.Index >= len(m.rows) || !m.InNormalMode() {
+		return nil
+	}
+	m.SetCursor(msg.Index)
+	from := jj.NewSelectedRevisions(m.rows[m.cursor].Commit)
+	if selected := m.SelectedRevisions(); m.isDragged(selected, m.cursor) {
+		from = selected
+	}
+	m.drag = &drag{from: from, source: m.cursor, target: -1}
+	return m.updateSelection()
+}
+
+func (m *Model) dragTo(msg tea.MouseMsg) tea.Cmd {
+	m.drag.mode = dropMode(msg)
+	m.drag.target = m.dropTarget(m.displayContextRenderer.IndexAt(msg.X, msg.Y))
+	if msg.Action != tea.MouseActionRelease {
+		return nil
+	}
+	d := m.drag
+	m.drag = nil
+	if d.target == -1 {
+		return nil
+	}
+
+	op := rebase.NewOperation(m.context, d.from, rebase.SourceRevision, d.mode)
+	op.To = m.rows[d.target].Commit
+	cursor := d.target
+	if d.mode == rebase.TargetInsert {
+		cursor = m.rowAbove(d.target)
+		op.InsertStart = op.To
+		op.To = m.rows[cursor].Commit
+	}
+	m.op = op
+	m.SetCursor(cursor)
+	return m.op.Update(intents.Apply{})
+}
+
+func dropMode(msg tea.MouseMsg) rebase.Target {
+	switch {
+	case msg.Ctrl && msg.Alt:
+		return rebase.TargetInsert
+	case msg.Ctrl:
+		return rebase.TargetBefore
+	case msg.Alt:
+		return rebase.TargetAfter
+	default:
+		return rebase.TargetDestination
+	}
+}
+
+// dropTarget returns the row if the dragged revisions can be dropped on it
+// with the current mode, -1 otherwise
+func (m *Model) dropTarget(index int) int {
+	if index < 0 || index >= len(m.rows) || index == m.drag.source || m.isDragged(m.drag.from, index) {
+		return -1
+	}
+	if m.drag.mode == rebase.TargetInsert {
+		above := m.rowAbove(index)
+		if above == -1 || m.isDragged(m.drag.from, above) {
+			return -1
+		}
+	}
+	return index
+}
+
+// rowAbove returns the visible row above the row, or -1 for the first row
+func (m *Model) rowAbove(index int) int {
+	if index <= 0 {
+		return -1
+	}
+	return m.foldHead(index - 1)
+}
+
+func (m *Model) isDragged(from jj.SelectedRevisions, index int) bool {
+	commitId := m.rows[index].Commit.CommitId
+	return slices.ContainsFunc(from.Revisions, func(commit *jj.Commit) bool {
+		return commit.CommitId == commitId
+	})
+}
+
+// renderDrop highlights the row the dragged revisions would be dropped on
+func (m *Model) renderDrop(dl *render.DisplayContext) {
+	if m.drag == nil || m.drag.target == -1 {
+		return
+	}
+	rect, ok := m.displayContextRenderer.RowRect(m.drag.target)
+	if !ok {
+		return
+	}
+	dl.AddHighlight(rect, m.dragStyles.target, 2)
+
+	label := map[rebase.Target]string{
+		rebase.TargetDestination: "<< onto >>",
+		rebase.TargetAfter:       "<< after >>",
+		rebase.TargetBefore:      "<< before >>",
+		rebase.TargetInsert:      "<< insert >>",
+	}[m.drag.mode]
+	x := max(rect.Max.X-lipgloss.Width(label), rect.Min.X)
+	dl.AddDraw(cellbuf.Rect(x, rect.Min.Y, rect.Max.X-x, 1), m.dragStyles.marker.Render(label), 3)
+}
@@ -0,0 +1,144 @@
+package revisions
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/jj"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDragModel loads a stack of 4 revisions, r03 on top of r00, each taking
+// two lines, and draws it so that row i starts at line 2*i
+func newDragModel(t *testing.T) (*Model, *test.CommandRunner) {
+	useDefaultConfig(t).Revisions.LogBatching = false
+
+	var lines []string
+	for i := 3; i >= 0; i-- {
+		lines = append(lines,
+			fmt.Sprintf("○  _PREFIX:r%02d_PREFIX:c%02d_PREFIX:false revision %d", i, i, i),
+			fmt.Sprintf("│  description %d", i))
+	}
+	model, commandRunner := newLogModel(t, lines, nil)
+	require.Len(t, model.rows, 4)
+	test.RenderImmediate(model, 80, 20)
+	return model, commandRunner
+}
+
+func release(x, y int, ctrl, alt bool) tea.Cmd {
+	return func() tea.Msg {
+		return tea.MouseMsg{X: x, Y: y, Ctrl: ctrl, Alt: alt, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft}
+	}
+}
+
+func TestModel_DragRow_RegistersDragInteraction(t *testing.T) {
+	model, _ := newDragModel(t)
+
+	dl := render.NewDisplayContext()
+	model.ViewRect(dl, layout.NewBox(cellbuf.Rect(0, 0, 80, 20)))
+	msg, handled := dl.ProcessMouseEvent(tea.MouseMsg{X: 5, Y: 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	require.True(t, handled)
+	assert.Equal(t, RowDragMsg{Index: 1, X: 5, Y: 2}, msg)
+}
+
+func TestModel_DragRow_RebasesOntoDroppedRow(t *testing.T) {
+	model, commandRunner := newDragModel(t)
+	from := jj.NewSelectedRevisions(model.rows[0].Commit)
+	commandRunner.Expect(jj.Rebase(from, "r01", "--revisions", "--onto", false, false))
+	defer commandRunner.Verify()
+
+	test.SimulateModel(model, model.Update(RowDragMsg{Index: 0}))
+	assert.True(t, model.Dragging())
+	test.SimulateModel(model, model.Update(tea.MouseMsg{X: 5, Y: 4, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft}))
+	test.SimulateModel(model, release(5, 4, false, false))
+	assert.False(t, model.Dragging())
+	assert.True(t, model.InNormalMode())
+}
+
+func TestModel_DragRow_ModifiersChooseTheTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctrl     bool
+		alt      bool
+		expected func(from jj.SelectedRevisions) jj.CommandArgs
+	}{
+		{
+			name: "after",
+			alt:  true,
+			expected: func(from jj.SelectedRevisions) jj.CommandArgs {
+				return jj.Rebase(from, "r01", "--revisions", "--insert-after", false, false)
+			},
+		},
+		{
+			name: "before",
+			ctrl: true,
+			expected: func(from jj.SelectedRevisions) jj.CommandArgs {
+				return jj.Rebase(from, "r01", "--revisions", "--insert-before", false, false)
+			},
+		},
+		{
+			name: "insert",
+			ctrl: true,
+			alt:  true,
+			expected: func(from jj.SelectedRevisions) jj.CommandArgs {
+				return jj.RebaseInsert(from, "r01", "r02", false, false)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			model, commandRunner := newDragModel(t)
+			from := jj.NewSelectedRevisions(model.rows[3].Commit)
+			commandRunner.Expect(tc.expected(from))
+			defer commandRunner.Verify()
+
+			test.SimulateModel(model, model.Update(RowDragMsg{Index: 3}))
+			test.SimulateModel(model, release(5, 5, tc.ctrl, tc.alt))
+		})
+	}
+}
+
+func TestModel_DragRow_DragsCheckedRevisions(t *testing.T) {
+	model, commandRunner := newDragModel(t)
+	for _, i := range []int{0, 1} {
+		commit := model.rows[i].Commit
+		model.context.AddCheckedItem(appContext.SelectedRevision{ChangeId: commit.ChangeId, CommitId: commit.CommitId})
+	}
+	from := jj.NewSelectedRevisions(model.rows[0].Commit, model.rows[1].Commit)
+	commandRunner.Expect(jj.Rebase(from, "r00", "--revisions", "--onto", false, false))
+	defer commandRunner.Verify()
+
+	test.SimulateModel(model, model.Update(RowDragMsg{Index: 1}))
+	test.SimulateModel(model, release(5, 6, false, false))
+}
+
+func TestModel_DragRow_ReleasingOnTheSameRowSelectsIt(t *testing.T) {
+	model, commandRunner := newDragModel(t)
+	defer commandRunner.Verify()
+
+	test.SimulateModel(model, model.Update(RowDragMsg{Index: 2}))
+	test.SimulateModel(model, release(5, 5, false, false))
+	assert.False(t, model.Dragging())
+	assert.Equal(t, "r01", model.SelectedRevision().ChangeId)
+	assert.True(t, model.InNormalMode())
+}
+
+func TestModel_DragRow_ShowsDropTarget(t *testing.T) {
+	model, _ := newDragModel(t)
+
+	test.SimulateModel(model, model.Update(RowDragMsg{Index: 0}))
+	test.SimulateModel(model, model.Update(tea.MouseMsg{X: 5, Y: 6, Alt: true, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft}))
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "<< after >>")
+
+	test.SimulateModel(model, model.Update(tea.MouseMsg{X: 5, Y: 0, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft}))
+	rendered = test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.NotContains(t, rendered, "<<", "the revision can't be dropped on itself")
+}
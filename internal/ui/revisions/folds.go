@@ -0,0 +1,219 @@
+package revisions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/parser"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/graph"
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+// folds are the change ids of the linear runs and the branches folded in the
+// view. A run is folded into its first row and a branch into its head, so
+// folds stay in place while the graph changes around them.
+type folds struct {
+	state config.FoldState
+	// heads maps every hidden row to the row it is folded into
+	heads map[int]int
+}
+
+// applyFolds hides the rows of the folded runs and branches
+func (m *Model) applyFolds() {
+	m.folds.heads = make(map[int]int)
+	for i := range m.rows {
+		m.rows[i].Hidden = false
+		m.rows[i].Folded = 0
+	}
+	hide := func(head int, rows []int) {
+		for _, i := range rows {
+			if m.rows[i].Hidden || i == head {
+				continue
+			}
+			m.rows[i].Hidden = true
+			m.rows[head].Folded++
+			m.folds.heads[i] = head
+		}
+	}
+	for i := range m.rows {
+		changeId := m.rows[i].Commit.GetChangeId()
+		if isFolded(m.folds.state.Branches, changeId) && !m.rows[i].Hidden {
+			hide(i, graph.Branch(m.rows, i))
+		}
+		if isFolded(m.folds.state.Runs, changeId) {
+			start, end := graph.LinearRun(m.rows, i)
+			if start < end && !m.rows[start].Hidden {
+				hide(start, rangeOf(start+1, end-1))
+			}
+		}
+	}
+}
+
+// foldHead returns the row the given row is folded into, or the row itself
+// when it is not hidden
+func (m *Model) foldHead(index int) int {
+	if head, ok := m.folds.heads[index]; ok {
+		return head
+	}
+	return index
+}
+
+// stepVisible returns the row step visible rows away from the cursor. The
+// result is out of the rows when there are not enough rows in that direction.
+func (m *Model) stepVisible(step int) int {
+	index, direction := m.cursor, 1
+	if step < 0 {
+		direction, step = -1, -step
+	}
+	for step > 0 {
+		index += direction
+		if index < 0 || index >= len(m.rows) {
+			return index + direction*(step-1)
+		}
+		if !m.rows[index].Hidden {
+			step--
+		}
+	}
+	return index
+}
+
+func (m *Model) fold(intent intents.Fold) tea.Cmd {
+	index := m.cursor
+	if intent.ChangeID != "" {
+		index = m.selectRevision(intent.ChangeID)
+	}
+	if index < 0 || index >= len(m.rows) {
+		return nil
+	}
+	index = m.foldHead(index)
+	changeId := m.rows[index].Commit.GetChangeId()
+
+	state := &m.folds.state
+	switch intent.Target {
+	case intents.FoldBranch:
+		folded := isFolded(state.Branches, changeId)
+		state.Branches = slices.DeleteFunc(state.Branches, func(id string) bool { return sameChange(id, changeId) })
+		if intent.Action == intents.FoldCollapse || (intent.Action == intents.FoldToggle && !folded) {
+			if len(graph.Branch(m.rows, index)) == 0 {
+				return intents.Invoke(intents.AddMessage{Text: "There is no branch below the revision to collapse"})
+			}
+			state.Branches = append(state.Branches, changeId)
+		}
+	default:
+		start, end := graph.LinearRun(m.rows, index)
+		inRun := func(id string) bool {
+			return slices.ContainsFunc(m.rows[start:end], func(row parser.Row) bool { return sameChange(id, row.Commit.GetChangeId()) })
+		}
+		folded := m.rows[start].Folded > 0 && slices.ContainsFunc(state.Runs, inRun)
+		state.Runs = slices.DeleteFunc(state.Runs, inRun)
+		if intent.Action == intents.FoldCollapse || (intent.Action == intents.FoldToggle && !folded) {
+			if end-start < 3 {
+				return intents.Invoke(intents.AddMessage{Text: "The revision is not in a linear run to fold"})
+			}
+			state.Runs = append(state.Runs, m.rows[start].Commit.GetChangeId())
+		}
+	}
+	return m.foldsChanged()
+}
+
+func (m *Model) unfoldAll() tea.Cmd {
+	m.folds.state = config.FoldState{}
+	return m.foldsChanged()
+}
+
+// unfold removes the fold hiding the row, so that it can be selected
+func (m *Model) unfold(index int) tea.Cmd {
+	head, ok := m.folds.heads[index]
+	if !ok {
+		return nil
+	}
+	start, end := graph.LinearRun(m.rows, head)
+	state := &m.folds.state
+	state.Runs = slices.DeleteFunc(state.Runs, func(id string) bool {
+		return slices.ContainsFunc(m.rows[start:end], func(row parser.Row) bool { return sameChange(id, row.Commit.GetChangeId()) })
+	})
+	headId := m.rows[head].Commit.GetChangeId()
+	state.Branches = slices.DeleteFunc(state.Branches, func(id string) bool { return sameChange(id, headId) })
+	return m.foldsChanged()
+}
+
+func (m *Model) foldsChanged() tea.Cmd {
+	m.applyFolds()
+	m.cursor = m.foldHead(m.cursor)
+	m.ensureCursorView = true
+	return tea.Batch(m.saveFolds(), m.updateSelection())
+}
+
+// saveFolds saves the folds with the full change ids of their revisions, so
+// that they keep matching the rows when the shortest unique prefixes shown in
+// the log get longer
+func (m *Model) saveFolds() tea.Cmd {
+	ctx := m.context
+	if ctx.Folds == nil {
+		return nil
+	}
+	runs, branches := slices.Clone(m.folds.state.Runs), slices.Clone(m.folds.state.Branches)
+	return func() tea.Msg {
+		ids := fullChangeIds(ctx, append(runs, branches...))
+		state := config.FoldState{Runs: ids[:len(runs)], Branches: ids[len(runs):]}
+		if err := ctx.Folds.Save(state); err != nil {
+			return intents.AddMessage{Text: "Failed to save the folded revisions", Err: err}
+		}
+		return nil
+	}
+}
+
+// fullChangeIds replaces the change id prefixes with the full change ids. The
+// ids that don't resolve, like the commit ids of hidden revisions, are kept.
+func fullChangeIds(ctx *appContext.MainContext, ids []string) []string {
+	if len(ids) == 0 {
+		return ids
+	}
+	revsets := make([]string, len(ids))
+	for i, id := range ids {
+		revsets[i] = fmt.Sprintf("present(%s)", id)
+	}
+	output, err := ctx.RunCommandImmediate(jj.GetFullIdsFromRevset(strings.Join(revsets, "|")))
+	if err != nil {
+		return ids
+	}
+	full := strings.Fields(string(output))
+	resolved := slices.Clone(ids)
+	for i, id := range ids {
+		if index := slices.IndexFunc(full, func(changeId string) bool { return strings.HasPrefix(changeId, id) }); index != -1 {
+			resolved[i] = full[index]
+		}
+	}
+	return resolved
+}
+
+// isFolded returns whether the revision with the change id is one of the folds
+func isFolded(folds []string, changeId string) bool {
+	return slices.ContainsFunc(folds, func(id string) bool { return sameChange(id, changeId) })
+}
+
+// sameChange returns whether the id of a fold, usually a full change id, is of
+// the revision with the change id shown in the log
+func sameChange(id string, changeId string) bool {
+	return changeId != "" && strings.HasPrefix(id, changeId)
+}
+
+func rangeOf(from int, to int) []int {
+	var indexes []int
+	for i := from; i <= to; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func foldedText(count int) string {
+	if count == 1 {
+		return "… 1 revision …"
+	}
+	return fmt.Sprintf("… %d revisions …", count)
+}
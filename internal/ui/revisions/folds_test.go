@@ -0,0 +1,102 @@
+package revisions
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFoldingModel loads a stack of 6 revisions, c05 on top of c00, on top of
+// trunk, with the side branch s01 on top of s00 next to it
+func newFoldingModel(t *testing.T, folds *config.Folds) (*Model, *test.CommandRunner) {
+	useDefaultConfig(t).Revisions.LogBatching = false
+
+	var lines []string
+	for i := 5; i >= 0; i-- {
+		lines = append(lines,
+			fmt.Sprintf("○  _PREFIX:c%02d_PREFIX:d%02d_PREFIX:false stack %d", i, i, i),
+			fmt.Sprintf("│  description %d", i))
+	}
+	lines = append(lines,
+		"│ ○  _PREFIX:s01_PREFIX:e01_PREFIX:false side 1",
+		"│ │  side description 1",
+		"│ ○  _PREFIX:s00_PREFIX:e00_PREFIX:false side 0",
+		"├─╯  side description 0",
+		"◆  _PREFIX:trunk_PREFIX:f00_PREFIX:false trunk",
+		"~  trunk description")
+	model, commandRunner := newLogModel(t, lines, func(ctx *appContext.MainContext) { ctx.Folds = folds })
+	require.Len(t, model.rows, 9)
+	return model, commandRunner
+}
+
+func TestModel_FoldRun(t *testing.T) {
+	model, _ := newFoldingModel(t, nil)
+	test.SimulateModel(model, model.Update(intents.Navigate{ChangeID: "c03"}))
+
+	test.SimulateModel(model, model.Update(intents.Fold{Target: intents.FoldRun}))
+	assert.Equal(t, "c05", model.SelectedRevision().ChangeId, "the run is folded into its first revision")
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "│ … 5 revisions …")
+	assert.Contains(t, rendered, "│ description 5")
+	assert.NotContains(t, rendered, "│ description 0")
+
+	test.SimulateModel(model, model.Update(intents.Navigate{Delta: 1}))
+	assert.Equal(t, "s01", model.SelectedRevision().ChangeId)
+	test.SimulateModel(model, model.Update(intents.Navigate{Delta: -1}))
+	assert.Equal(t, "c05", model.SelectedRevision().ChangeId)
+
+	test.SimulateModel(model, model.Update(intents.Fold{Target: intents.FoldRun}))
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "│ description 0")
+}
+
+func TestModel_FoldRun_UnfoldsWhenNavigatingToHiddenRevision(t *testing.T) {
+	model, _ := newFoldingModel(t, nil)
+	test.SimulateModel(model, model.Update(intents.Fold{Target: intents.FoldRun, Action: intents.FoldCollapse, ChangeID: "c02"}))
+	require.True(t, model.rows[2].Hidden)
+
+	test.SimulateModel(model, model.Update(intents.Navigate{ChangeID: "c02"}))
+	assert.Equal(t, "c02", model.SelectedRevision().ChangeId)
+	assert.False(t, model.rows[2].Hidden)
+}
+
+func TestModel_FoldBranch(t *testing.T) {
+	model, _ := newFoldingModel(t, nil)
+	test.SimulateModel(model, model.Update(intents.Navigate{ChangeID: "s01"}))
+
+	test.SimulateModel(model, model.Update(intents.Fold{Target: intents.FoldBranch}))
+	assert.Empty(t, model.folds.state.Branches, "s00 joins trunk, there is nothing to collapse below s01")
+
+	test.SimulateModel(model, model.Update(intents.Navigate{ChangeID: "c05"}))
+	test.SimulateModel(model, model.Update(intents.Fold{Target: intents.FoldBranch}))
+	assert.Equal(t, []string{"c05"}, model.folds.state.Branches)
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "… 5 revisions …")
+	assert.Contains(t, rendered, "side description 1")
+}
+
+func TestModel_Folds_ArePersisted(t *testing.T) {
+	folds := config.NewFoldsFile(filepath.Join(t.TempDir(), "folds.json"))
+	model, commandRunner := newFoldingModel(t, folds)
+	commandRunner.Expect(jj.GetFullIdsFromRevset("present(c05)")).SetOutput([]byte("c05kmzvwpq\n"))
+	test.SimulateModel(model, model.Update(intents.Fold{Target: intents.FoldRun, ChangeID: "c01"}))
+
+	state, err := folds.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c05kmzvwpq"}, state.Runs, "the full change id is saved")
+
+	model, _ = newFoldingModel(t, folds)
+	assert.Equal(t, 5, model.rows[0].Folded, "the full change id matches the prefix in the log")
+
+	test.SimulateModel(model, model.Update(intents.UnfoldAll{}))
+	state, err = folds.Load()
+	require.NoError(t, err)
+	assert.Empty(t, state.Runs)
+}
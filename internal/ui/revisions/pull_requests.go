@@ -0,0 +1,143 @@
+package revisions
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/forge"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+const pullRequestsTimeout = 30 * time.Second
+
+type pullRequestsMsg struct {
+	pullRequests map[string]forge.PullRequest
+	// targets maps the local bookmarks to the commits they point to
+	targets map[string]string
+	err     error
+}
+
+type pullRequestStyles struct {
+	number  lipgloss.Style
+	merged  lipgloss.Style
+	dimmed  lipgloss.Style
+	success lipgloss.Style
+	failure lipgloss.Style
+}
+
+func newPullRequestStyles() pullRequestStyles {
+	return pullRequestStyles{
+		number:  common.DefaultPalette.Get("revisions pr number"),
+		merged:  common.DefaultPalette.Get("revisions pr merged"),
+		dimmed:  common.DefaultPalette.Get("revisions pr dimmed"),
+		success: common.DefaultPalette.Get("revisions pr success"),
+		failure: common.DefaultPalette.Get("revisions pr error"),
+	}
+}
+
+// fetchPullRequests asks the forge for the pull requests of the local bookmarks.
+// Only one request is in flight at a time.
+func (m *Model) fetchPullRequests() tea.Cmd {
+	provider := m.context.Forge
+	if provider == nil || m.fetchingPullRequests {
+		return nil
+	}
+	m.fetchingPullRequests = true
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.BookmarkListAll())
+		if err != nil {
+			return pullRequestsMsg{err: err}
+		}
+		var bookmarks []string
+		targets := make(map[string]string)
+		for _, bookmark := range jj.ParseBookmarkListOutput(string(output)) {
+			if bookmark.Local != nil {
+				bookmarks = append(bookmarks, bookmark.Name)
+				targets[bookmark.Name] = bookmark.Local.CommitId
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), pullRequestsTimeout)
+		defer cancel()
+		pullRequests, err := provider.PullRequests(ctx, bookmarks)
+		return pullRequestsMsg{pullRequests: pullRequests, targets: targets, err: err}
+	}
+}
+
+func (m *Model) updatePullRequests(msg pullRequestsMsg) {
+	m.fetchingPullRequests = false
+	if msg.err != nil {
+		// keep showing the last known statuses, the forge may be unreachable for a while
+		log.Println("failed to fetch pull requests:", msg.err)
+		return
+	}
+	m.displayContextRenderer.SetPullRequests(msg.pullRequests, msg.targets)
+}
+
+// pullRequestFor returns the pull request of the bookmark rendered in the
+// segment when the bookmark points to the commit, so that authors or
+// descriptions that happen to match a bookmark name get no badge. Local
+// bookmarks are rendered with a trailing * when they are ahead of their remote
+// and with ?? when they are conflicted.
+func (r *DisplayContextRenderer) pullRequestFor(text string, commit *jj.Commit) (forge.PullRequest, bool) {
+	if len(r.pullRequests) == 0 || commit == nil {
+		return forge.PullRequest{}, false
+	}
+	name := strings.TrimRight(strings.TrimSpace(text), "*?")
+	if name == "" || strings.Contains(name, "@") {
+		return forge.PullRequest{}, false
+	}
+	// both ids are unique prefixes, so the commit is the same when one starts
+	// with the other
+	target := r.bookmarkTargets[name]
+	if target == "" || commit.CommitId == "" ||
+		!strings.HasPrefix(target, commit.CommitId) && !strings.HasPrefix(commit.CommitId, target) {
+		return forge.PullRequest{}, false
+	}
+	pr, ok := r.pullRequests[name]
+	return pr, ok
+}
+
+// renderPullRequest writes a badge like [#12 approved ci ✓] for the pull request
+func (r *DisplayContextRenderer) renderPullRequest(tb *render.TextBuilder, pr forge.PullRequest) {
+	s := r.pullRequestStyles
+	numberStyle := s.number
+	number := "#" + strconv.Itoa(pr.Number)
+	switch pr.State {
+	case forge.StateMerged:
+		numberStyle = s.merged
+		number += " merged"
+	case forge.StateClosed:
+		numberStyle = s.dimmed
+		number += " closed"
+	case forge.StateDraft:
+		numberStyle = s.dimmed
+		number += " draft"
+	}
+	tb.Styled(" [", s.dimmed).Styled(number, numberStyle)
+
+	switch pr.ReviewDecision {
+	case forge.ReviewApproved:
+		tb.Styled(" approved", s.success)
+	case forge.ReviewChangesRequested:
+		tb.Styled(" changes requested", s.failure)
+	case forge.ReviewRequired:
+		tb.Styled(" review required", s.dimmed)
+	}
+
+	switch pr.Checks {
+	case forge.ChecksPassing:
+		tb.Styled(" ci ✓", s.success)
+	case forge.ChecksFailing:
+		tb.Styled(" ci ✗", s.failure)
+	case forge.ChecksPending:
+		tb.Styled(" ci …", s.dimmed)
+	}
+	tb.Styled("]", s.dimmed)
+}
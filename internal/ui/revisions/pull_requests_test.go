@@ -0,0 +1,92 @@
+package revisions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/forge"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/parser"
+	"github.com/idursun/jjui/internal/screen"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var bookmarkRows = []parser.Row{
+	{
+		Commit: &jj.Commit{ChangeId: "a", CommitId: "1"},
+		Lines: []*parser.GraphRowLine{
+			{
+				Segments: []*screen.Segment{{Text: "a"}, {Text: " "}, {Text: "feature*"}, {Text: " "}, {Text: "feature@origin"}},
+				Flags:    parser.Revision,
+			},
+			{
+				Segments: []*screen.Segment{{Text: "feature"}},
+			},
+		},
+	},
+	{
+		Commit: &jj.Commit{ChangeId: "b", CommitId: "2"},
+		Lines: []*parser.GraphRowLine{
+			{
+				// the author of b is named like the feature bookmark
+				Segments: []*screen.Segment{{Text: "b"}, {Text: " "}, {Text: "main"}, {Text: " "}, {Text: "feature"}},
+				Flags:    parser.Revision,
+			},
+		},
+	},
+}
+
+func TestModel_RendersPullRequestBadges(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListAll()).SetOutput([]byte("feature;.;false;false;false;1\nfeature;origin;true;false;false;1\nmain;.;false;false;false;2\nremote;origin;false;false;false;3"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	fake := test.NewFakeForge(forge.PullRequest{
+		Bookmark:       "feature",
+		Number:         12,
+		State:          forge.StateOpen,
+		ReviewDecision: forge.ReviewApproved,
+		Checks:         forge.ChecksFailing,
+	})
+	ctx.Forge = fake
+	model := New(ctx)
+	model.updateGraphRows(bookmarkRows, "a")
+
+	test.SimulateModel(model, model.fetchPullRequests())
+	assert.Equal(t, [][]string{{"feature", "main"}}, fake.Requested)
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 10))
+	assert.Contains(t, rendered, "a feature* [#12 approved ci ✗] feature@origin")
+	assert.NotContains(t, rendered, "main [")
+	// badges are only added to the revision line of the bookmark
+	assert.Equal(t, 1, strings.Count(rendered, "#12"))
+	assert.True(t, strings.HasSuffix(rendered, "b main feature"))
+}
+
+func TestModel_KeepsPullRequestsWhenForgeFails(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListAll()).SetOutput([]byte("feature;.;false;false;false;1"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	fake := test.NewFakeForge()
+	fake.Err = errors.New("offline")
+	ctx.Forge = fake
+	model := New(ctx)
+	model.updateGraphRows(bookmarkRows, "a")
+	model.displayContextRenderer.SetPullRequests(map[string]forge.PullRequest{
+		"main": {Bookmark: "main", Number: 1, State: forge.StateMerged},
+	}, map[string]string{"main": "2"})
+
+	test.SimulateModel(model, model.fetchPullRequests())
+	assert.False(t, model.fetchingPullRequests)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 10)), "main [#1 merged]")
+}
+
+func TestModel_DoesNotFetchPullRequestsWithoutForge(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	assert.Nil(t, model.fetchPullRequests())
+}
@@ -56,12 +56,20 @@ var (
 const revsetAutoExpandStep = 50
 
 type Model struct {
-	rows                   []parser.Row
+	rows []parser.Row
+	// rowOffset is the position of rows[0] in the graph. When the log is
+	// streamed in batches only a window of log_window_size rows is kept around
+	// the cursor, and rowIndex locates the revisions that were dropped.
+	rowOffset              int
+	rowIndex               *graph.RowIndex
 	tag                    atomic.Uint64
 	revisionToSelect       string
 	offScreenRows          []parser.Row
+	offScreenOffset        int
+	offScreenIndex         *graph.RowIndex
 	streamer               *graph.GraphStreamer
 	hasMore                bool
+	windowLoading          bool
 	op                     common.ImmediateModel
 	cursor                 int
 	context                *appContext.MainContext
@@ -78,6 +86,10 @@ type Model struct {
 	matchedStyle           lipgloss.Style
 	ensureCursorView       bool
 	requestInFlight        bool
+	fetchingPullRequests   bool
+	folds                  folds
+	drag                   *drag
+	dragStyles             dragStyles
 }
 
 type revisionsMsg struct {
@@ -121,13 +133,26 @@ type appendRowsBatchMsg struct {
 	tag     uint64
 }
 
+type windowLoadedMsg struct {
+	streamer *graph.GraphStreamer
+	rows     []parser.Row
+	offset   int
+	hasMore  bool
+	// position is selected when it is not -1, otherwise the view stays on the
+	// same rows and is scrolled by scrollDelta lines
+	position    int
+	scrollDelta int
+	tag         uint64
+	err         error
+}
+
 func (m *Model) Cursor() int {
 	return m.cursor
 }
 
 func (m *Model) SetCursor(index int) {
 	if index >= 0 && index < len(m.rows) {
-		m.cursor = index
+		m.cursor = m.foldHead(index)
 		m.ensureCursorView = true
 	}
 }
@@ -140,6 +165,11 @@ func (m *Model) Scroll(delta int) tea.Cmd {
 	m.ensureCursorView = false
 	currentStart := m.displayContextRenderer.GetScrollOffset()
 	desiredStart := currentStart + delta
+
+	// Stream the rows above the window again when scrolling past its top
+	if desiredStart < 0 && m.rowOffset > 0 {
+		return m.loadWindow(m.rowOffset, -1, delta)
+	}
 	m.displayContextRenderer.SetScrollOffset(desiredStart)
 
 	// Request more rows if scrolling down and near the end
@@ -223,6 +253,13 @@ func (m *Model) SelectedRevisions() jj.SelectedRevisions {
 	for _, row := range m.rows {
 		if _, ok := ids[row.Commit.CommitId]; ok {
 			selected = append(selected, row.Commit)
+			delete(ids, row.Commit.CommitId)
+		}
+	}
+	// revisions checked before their rows were dropped from the window
+	for _, ci := range m.context.CheckedItems {
+		if rev, ok := ci.(appContext.SelectedRevision); ok && ids[rev.CommitId] {
+			selected = append(selected, &jj.Commit{ChangeId: rev.ChangeId, CommitId: rev.CommitId})
 		}
 	}
 
@@ -255,6 +292,20 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case intents.Intent:
 		return m.handleIntent(msg)
+	case common.ConfigReloadedMsg:
+		m.keymap = config.Current.GetKeyMap()
+		m.textStyle = common.DefaultPalette.Get("revisions text")
+		m.dimmedStyle = common.DefaultPalette.Get("revisions dimmed")
+		m.selectedStyle = common.DefaultPalette.Get("revisions selected")
+		m.matchedStyle = common.DefaultPalette.Get("revisions matched")
+		m.displayContextRenderer.SetStyles(m.textStyle, m.dimmedStyle, m.selectedStyle, m.matchedStyle)
+		m.dragStyles = newDragStyles()
+		if _, ok := m.op.(*operations.Default); ok {
+			m.op = operations.NewDefault()
+			return nil
+		}
+		// the other operations rebuild their own styles
+		return m.op.Update(msg)
 	case ItemClickedMsg:
 		// Don't allow changing selection if the operation is editing (e.g. describe)
 		if editable, ok := m.op.(common.Editable); ok && editable.IsEditing() {
@@ -266,6 +317,13 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		}
 		m.SetCursor(msg.Index)
 		return m.updateSelection()
+	case RowDragMsg:
+		return m.startDrag(msg)
+	case tea.MouseMsg:
+		if m.drag != nil {
+			return m.dragTo(msg)
+		}
+		return nil
 	case ViewportScrollMsg:
 		if msg.Horizontal {
 			return nil
@@ -306,7 +364,10 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		return tea.Batch(m.refresh(intents.Refresh{
 			KeepSelections:   msg.KeepSelections,
 			SelectedRevision: msg.SelectedRevision,
-		}), m.op.Update(msg))
+		}), m.op.Update(msg), m.fetchPullRequests())
+	case pullRequestsMsg:
+		m.updatePullRequests(msg)
+		return nil
 	case updateRevisionsMsg:
 		m.isLoading = false
 		m.updateGraphRows(msg.rows, msg.selectedRevision)
@@ -315,6 +376,8 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		})
 	case startRowsStreamingMsg:
 		m.offScreenRows = nil
+		m.offScreenOffset = 0
+		m.offScreenIndex = graph.NewRowIndex()
 		m.revisionToSelect = msg.selectedRevision
 
 		// If the revision to select is not set, use the currently selected item
@@ -334,14 +397,17 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		if msg.tag != m.tag.Load() {
 			return nil
 		}
+		m.offScreenIndex.Add(m.offScreenOffset+len(m.offScreenRows), msg.rows)
 		m.offScreenRows = append(m.offScreenRows, msg.rows...)
 		m.hasMore = msg.hasMore
 		m.isLoading = m.hasMore && len(m.offScreenRows) > 0
+		m.trimOffScreenRows()
 
 		if m.hasMore {
 			// keep requesting rows until we reach the initial load count or the current cursor position
 			lastRowIndex := m.displayContextRenderer.GetLastRowIndex()
-			if len(m.offScreenRows) < m.cursor+1 || len(m.offScreenRows) < lastRowIndex+1 {
+			streamed := m.offScreenOffset + len(m.offScreenRows)
+			if streamed < m.rowOffset+m.cursor+1 || streamed < m.rowOffset+lastRowIndex+1 {
 				return m.requestMoreRows(msg.tag)
 			}
 		} else if m.streamer != nil {
@@ -349,7 +415,12 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		}
 
 		currentSelectedRevision := m.SelectedRevision()
-		m.rows = m.offScreenRows
+		if m.offScreenIndex != m.rowIndex {
+			// keep the cursor at the same position in the new graph
+			m.cursor += m.rowOffset - m.offScreenOffset
+		}
+		m.rows, m.rowOffset, m.rowIndex = m.offScreenRows, m.offScreenOffset, m.offScreenIndex
+		m.applyFolds()
 		if m.revisionToSelect != "" {
 			m.SetCursor(m.selectRevision(m.revisionToSelect))
 			m.revisionToSelect = ""
@@ -370,6 +441,17 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 			})
 		}
 		return tea.Batch(cmds...)
+	case windowLoadedMsg:
+		m.windowLoading = false
+		if msg.tag != m.tag.Load() {
+			msg.streamer.Close()
+			return nil
+		}
+		if msg.err != nil {
+			return intents.Invoke(intents.AddMessage{Text: "Failed to load revisions", Err: msg.err})
+		}
+		m.showWindow(msg)
+		return tea.Batch(m.highlightChanges, m.updateSelection())
 	}
 
 	if intent, ok := msg.(intents.Intent); ok {
@@ -491,6 +573,12 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 				return m.handleIntent(intents.StartDuplicate{})
 			case key.Matches(msg, m.keymap.SetParents):
 				return m.handleIntent(intents.SetParents{})
+			case key.Matches(msg, m.keymap.Fold.Run):
+				return m.handleIntent(intents.Fold{Target: intents.FoldRun})
+			case key.Matches(msg, m.keymap.Fold.Branch):
+				return m.handleIntent(intents.Fold{Target: intents.FoldBranch})
+			case key.Matches(msg, m.keymap.Fold.UnfoldAll):
+				return m.handleIntent(intents.UnfoldAll{})
 			}
 		}
 	}
@@ -546,6 +634,10 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		return m.navigate(intents.Navigate{Delta: 1})
 	case intents.Navigate:
 		return m.navigate(intent)
+	case intents.Fold:
+		return m.fold(intent)
+	case intents.UnfoldAll:
+		return m.unfoldAll()
 	case intents.StartDescribe:
 		return m.startDescribe(intent)
 	case intents.StartEvolog:
@@ -632,6 +724,9 @@ func (m *Model) refresh(intent intents.Refresh) tea.Cmd {
 		m.context.ClearCheckedItems(reflect.TypeFor[appContext.SelectedRevision]())
 	}
 	m.isLoading = true
+	if config.Current.Revisions.StructuredGraph {
+		return m.loadStructured(m.context.CurrentRevset, intent.SelectedRevision)
+	}
 	if config.Current.Revisions.LogBatching {
 		currentTag := m.tag.Add(1)
 		return m.loadStreaming(m.context.CurrentRevset, intent.SelectedRevision, currentTag)
@@ -664,7 +759,7 @@ func (m *Model) startSquash(intent intents.StartSquash) tea.Cmd {
 	} else if m.cursor < len(m.rows)-1 {
 		m.SetCursor(m.cursor + 1)
 	}
-	m.op = squash.NewOperation(m.context, selected, squash.WithFiles(intent.Files))
+	m.op = squash.NewOperation(m.context, selected, squash.WithFiles(intent.Files), squash.WithDiffEditorArgs(intent.DiffEditorArgs, intent.SelectionDir))
 	return tea.Batch(m.op.Init(), m.updateSelection())
 }
 
@@ -928,16 +1023,21 @@ func (m *Model) navigate(intent intents.Navigate) tea.Cmd {
 	}
 
 	if intent.ChangeID != "" || intent.FallbackID != "" {
-		idx := m.selectRevision(intent.ChangeID)
-		if idx == -1 && intent.FallbackID != "" {
-			idx = m.selectRevision(intent.FallbackID)
+		idx, cmd := m.locateRevision(intent.ChangeID)
+		if idx == -1 && cmd == nil && intent.FallbackID != "" {
+			idx, cmd = m.locateRevision(intent.FallbackID)
+		}
+		if cmd != nil {
+			return cmd
 		}
 		if idx == -1 {
 			return nil
 		}
+		// the revision is shown when it was folded away
+		unfold := m.unfold(idx)
 		m.ensureCursorView = ensureView
 		m.SetCursor(idx)
-		return m.updateSelection()
+		return tea.Batch(unfold, m.updateSelection())
 	}
 
 	switch intent.Target {
@@ -946,6 +1046,9 @@ func (m *Model) navigate(intent intents.Navigate) tea.Cmd {
 		m.ensureCursorView = ensureView
 		return m.updateSelection()
 	case intents.TargetTop:
+		if m.rowOffset > 0 {
+			return m.loadWindow(0, 0, 0)
+		}
 		m.SetCursor(0)
 		m.ensureCursorView = ensureView
 		return m.updateSelection()
@@ -954,7 +1057,11 @@ func (m *Model) navigate(intent intents.Navigate) tea.Cmd {
 		m.ensureCursorView = ensureView
 		return m.updateSelection()
 	case intents.TargetWorkingCopy:
-		if idx := m.selectRevision("@"); idx != -1 {
+		idx, cmd := m.locateRevision("@")
+		if cmd != nil {
+			return cmd
+		}
+		if idx != -1 {
 			m.SetCursor(idx)
 		}
 		m.ensureCursorView = ensureView
@@ -988,7 +1095,7 @@ func (m *Model) navigate(intent intents.Navigate) tea.Cmd {
 
 	// Calculate new cursor position
 	totalItems := len(m.rows)
-	newCursor := m.cursor + step
+	newCursor := m.stepVisible(step)
 
 	if step > 0 {
 		// Moving down
@@ -1006,6 +1113,10 @@ func (m *Model) navigate(intent intents.Navigate) tea.Cmd {
 	} else {
 		// Moving up
 		if newCursor < 0 {
+			if m.rowOffset > 0 {
+				position := max(m.rowOffset+newCursor, 0)
+				return m.loadWindow(position, position, 0)
+			}
 			newCursor = 0
 		}
 	}
@@ -1062,7 +1173,7 @@ func (m *Model) showDiff(intent intents.ShowDiff) tea.Cmd {
 	}
 	changeId := commit.GetChangeId()
 	return func() tea.Msg {
-		output, _ := m.context.RunCommandImmediate(jj.Diff(changeId, ""))
+		output, _ := m.context.RunCommandImmediate(jj.DiffGit(changeId))
 		return common.ShowDiffMsg(output)
 	}
 }
@@ -1130,6 +1241,9 @@ func (m *Model) updateGraphRows(rows []parser.Row, selectedRevision string) {
 		currentSelectedRevision = cur.GetChangeId()
 	}
 	m.rows = rows
+	m.rowOffset = 0
+	m.rowIndex = nil
+	m.applyFolds()
 
 	if len(m.rows) > 0 {
 		m.SetCursor(m.selectRevision(currentSelectedRevision))
@@ -1158,6 +1272,7 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 
 	// Set selections
 	m.displayContextRenderer.SetSelections(m.context.GetSelectedRevisions())
+	m.displayContextRenderer.SetDraggable(m.InNormalMode())
 
 	// Get operation if any
 	var op operations.Operation
@@ -1175,6 +1290,7 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 		m.quickSearch,
 		m.ensureCursorView,
 	)
+	m.renderDrop(dl)
 
 	if overlayOp, ok := m.op.(common.Overlay); ok && overlayOp.IsOverlay() {
 		m.op.ViewRect(dl, box)
@@ -1186,7 +1302,7 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 
 func (m *Model) load(revset string, selectedRevision string) tea.Cmd {
 	return func() tea.Msg {
-		output, err := m.context.RunCommandImmediate(jj.Log(revset, config.Current.Limit, m.context.JJConfig.Templates.Log))
+		output, err := m.context.RunCommandImmediate(jj.Log(revset, m.context.LogLimit(), m.context.LogTemplate()))
 		if err != nil {
 			return common.UpdateRevisionsFailedMsg{
 				Err:    err,
@@ -1198,13 +1314,30 @@ func (m *Model) load(revset string, selectedRevision string) tea.Cmd {
 	}
 }
 
+// loadStructured lists the revisions with their parents and lays out the graph
+// in jjui instead of parsing the one drawn by `jj log`
+func (m *Model) loadStructured(revset string, selectedRevision string) tea.Cmd {
+	styles := graph.NewStyles()
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.LogNodes(revset, m.context.LogLimit()))
+		if err != nil {
+			return common.UpdateRevisionsFailedMsg{
+				Err:    err,
+				Output: string(output),
+			}
+		}
+		rows := graph.Rows(jj.ParseLogNodes(string(output)), styles)
+		return updateRevisionsMsg{rows, selectedRevision}
+	}
+}
+
 func (m *Model) loadStreaming(revset string, selectedRevision string, tag uint64) tea.Cmd {
 	if m.tag.Load() != tag {
 		return nil
 	}
 
 	var cmds []tea.Cmd
-	streamer, err := graph.NewGraphStreamer(context.Background(), m.context, revset, m.context.JJConfig.Templates.Log)
+	streamer, err := graph.NewGraphStreamer(context.Background(), m.context, revset, m.context.LogLimit(), m.context.LogTemplate())
 	if err != nil {
 		var errMsg string
 		if err == io.EOF {
@@ -1233,7 +1366,7 @@ func (m *Model) loadStreaming(revset string, selectedRevision string, tag uint64
 }
 
 func (m *Model) requestMoreRows(tag uint64) tea.Cmd {
-	if m.requestInFlight || m.streamer == nil || !m.hasMore || tag != m.tag.Load() {
+	if m.requestInFlight || m.windowLoading || m.streamer == nil || !m.hasMore || tag != m.tag.Load() {
 		return nil
 	}
 
@@ -1242,6 +1375,124 @@ func (m *Model) requestMoreRows(tag uint64) tea.Cmd {
 	return m.Update(appendRowsBatchMsg{batch.Rows, batch.HasMore, tag})
 }
 
+// trimOffScreenRows drops the streamed rows well above the part of the graph
+// in view once more than log_window_size rows are kept. loadWindow streams
+// them again when they are needed.
+func (m *Model) trimOffScreenRows() {
+	size := config.Current.Revisions.LogWindowSize
+	if size <= 0 || len(m.offScreenRows) <= size {
+		return
+	}
+	inView := m.cursor
+	if first := m.displayContextRenderer.GetFirstRowIndex(); first != -1 {
+		inView = min(inView, first)
+	}
+	keepFrom := m.rowOffset + inView - size/4
+	drop := min(len(m.offScreenRows)-size, keepFrom-m.offScreenOffset)
+	if drop <= 0 {
+		return
+	}
+
+	// the rows on the screen come from the same stream, so they are dropped too
+	inWindow := m.offScreenIndex == m.rowIndex
+	if inWindow {
+		lines := 0
+		for _, row := range m.offScreenRows[:drop] {
+			lines += rowHeight(row)
+		}
+		m.cursor -= drop
+		m.displayContextRenderer.ShiftRows(-drop, -lines)
+	}
+	m.offScreenRows = slices.Clone(m.offScreenRows[drop:])
+	m.offScreenOffset += drop
+	if inWindow {
+		m.rows, m.rowOffset = m.offScreenRows, m.offScreenOffset
+		m.applyFolds()
+	}
+}
+
+// loadWindow streams the graph again from the start to load the rows around
+// the given position after they were dropped. The row at position is
+// selected once loaded unless it is -1.
+func (m *Model) loadWindow(around int, position int, scrollDelta int) tea.Cmd {
+	if m.windowLoading {
+		return nil
+	}
+	m.windowLoading = true
+
+	size := config.Current.Revisions.LogWindowSize
+	from := max(around-size/2, 0)
+	if size <= 0 {
+		// windowing was turned off, load everything streamed so far
+		from, size = 0, m.rowOffset+len(m.rows)
+	}
+	tag := m.tag.Load()
+	revset := m.context.CurrentRevset
+	limit, template := m.context.LogLimit(), m.context.LogTemplate()
+	return func() tea.Msg {
+		streamer, err := graph.NewGraphStreamer(context.Background(), m.context, revset, limit, template)
+		if streamer == nil {
+			return windowLoadedMsg{tag: tag, err: err}
+		}
+		batch := streamer.ReadWindow(from, size)
+		return windowLoadedMsg{
+			streamer:    streamer,
+			rows:        batch.Rows,
+			offset:      from,
+			hasMore:     batch.HasMore,
+			position:    position,
+			scrollDelta: scrollDelta,
+			tag:         tag,
+		}
+	}
+}
+
+// showWindow replaces the rows with the ones streamed by loadWindow
+func (m *Model) showWindow(msg windowLoadedMsg) {
+	m.streamer.Close()
+	m.streamer = msg.streamer
+	m.hasMore = msg.hasMore
+	if !m.hasMore {
+		m.streamer.Close()
+	}
+
+	position := msg.position
+	if position == -1 {
+		position = m.rowOffset + m.cursor
+		// the rows in view keep their place on the screen
+		added := m.rowOffset - msg.offset
+		lines := 0
+		for _, row := range msg.rows[:min(max(added, 0), len(msg.rows))] {
+			lines += rowHeight(row)
+		}
+		m.displayContextRenderer.ShiftRows(added, lines)
+		m.displayContextRenderer.SetScrollOffset(m.displayContextRenderer.GetScrollOffset() + msg.scrollDelta)
+	}
+
+	if m.rowIndex == nil {
+		m.rowIndex = graph.NewRowIndex()
+	}
+	m.rowIndex.Add(msg.offset, msg.rows)
+	m.rows, m.rowOffset = msg.rows, msg.offset
+	m.offScreenRows, m.offScreenOffset, m.offScreenIndex = msg.rows, msg.offset, m.rowIndex
+	m.applyFolds()
+	m.cursor = m.foldHead(min(max(position-msg.offset, 0), max(len(m.rows)-1, 0)))
+	m.ensureCursorView = msg.position != -1
+}
+
+// locateRevision returns the index of the revision in the rows, or a command
+// loading the rows around it when they were dropped from the window. Both are
+// empty when the revision is not in the graph streamed so far.
+func (m *Model) locateRevision(revision string) (int, tea.Cmd) {
+	if idx := m.selectRevision(revision); idx != -1 {
+		return idx, nil
+	}
+	if position, ok := m.rowIndex.Position(revision); ok && revision != "" {
+		return -1, m.loadWindow(position, position, 0)
+	}
+	return -1, nil
+}
+
 func (m *Model) maybeExpandRevsetFromBottom() tea.Cmd {
 	if m.isLoading || len(m.rows) == 0 {
 		return nil
@@ -1501,6 +1752,9 @@ func (m *Model) search(startIndex int, backward bool) int {
 			c = (startIndex - i + n) % n
 		}
 		row := &m.rows[c]
+		if row.Hidden {
+			continue
+		}
 		for _, line := range row.Lines {
 			for _, segment := range line.Segments {
 				if segment.Text != "" && strings.Contains(strings.ToLower(segment.Text), m.quickSearch) {
@@ -1537,8 +1791,10 @@ func New(c *appContext.MainContext) *Model {
 		dimmedStyle:   common.DefaultPalette.Get("revisions dimmed"),
 		selectedStyle: common.DefaultPalette.Get("revisions selected"),
 		matchedStyle:  common.DefaultPalette.Get("revisions matched"),
+		dragStyles:    newDragStyles(),
 	}
 	m.displayContextRenderer = NewDisplayContextRenderer(m.textStyle, m.dimmedStyle, m.selectedStyle, m.matchedStyle)
+	m.folds.state, _ = c.Folds.Load()
 	return &m
 }
 
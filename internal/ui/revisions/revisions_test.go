@@ -5,15 +5,49 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/parser"
 	"github.com/idursun/jjui/internal/screen"
 	"github.com/idursun/jjui/internal/ui/common"
+	appContext "github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// useDefaultConfig sets config.Current to the default config until the end of
+// the test and returns it to be changed by the test
+func useDefaultConfig(t *testing.T) *config.Config {
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+	config.Current = config.Default()
+	return config.Current
+}
+
+// newLogModel refreshes a new model with a log of the given lines, written
+// with test.LogBuilder. setup, when given, changes the context before the
+// model is created. It returns the command runner to expect more commands on.
+func newLogModel(t *testing.T, lines []string, setup func(ctx *appContext.MainContext)) (*Model, *test.CommandRunner) {
+	var lb test.LogBuilder
+	for _, line := range lines {
+		lb.Write(line)
+	}
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Log("all()", config.Current.Limit, "")).SetOutput([]byte(lb.String()))
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "all()"
+	if setup != nil {
+		setup(ctx)
+	}
+	model := New(ctx)
+	test.SimulateModel(model, model.Update(common.RefreshMsg{}))
+	require.NotEmpty(t, model.rows)
+	return model, commandRunner
+}
+
 func TestModel_highlightChanges(t *testing.T) {
 	model := Model{
 		rows: []parser.Row{
@@ -225,3 +259,25 @@ func TestModel_NavigateTargetBottom_DoesNotExpandRevset(t *testing.T) {
 		assert.False(t, isRevsetUpdate)
 	}
 }
+
+func TestModel_StructuredGraph(t *testing.T) {
+	useDefaultConfig(t).Revisions.StructuredGraph = true
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.LogNodes("all()", config.Current.Limit)).SetOutput([]byte(
+		"kx\tkxqyvtmn\t1a\t1a2b3c4d\t1a2b3c4d5e6f\t9f8e7d6c5b4a\t@\t\tJane\t2 days ago\tadd parser\n" +
+			"z\tzzzzzzzz\t9f\t9f8e7d6c\t9f8e7d6c5b4a\t\tir\t\t\t56 years ago\t\n"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "all()"
+	model := New(ctx)
+	test.SimulateModel(model, model.Update(common.RefreshMsg{}))
+
+	assert.Equal(t, 2, model.Len())
+	assert.Equal(t, "kx", model.SelectedRevision().ChangeId)
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 10))
+	assert.Contains(t, rendered, "@ kxqyvtmn Jane 2 days ago 1a2b3c4d")
+	assert.Contains(t, rendered, "│ add parser")
+	assert.Contains(t, rendered, "◆ zzzzzzzz root() 9f8e7d6c")
+}
@@ -0,0 +1,83 @@
+package revisions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWindowedModel streams a log of the given number of revisions, 6 rows per
+// batch and keeping 10 of them in memory
+func newWindowedModel(t *testing.T, revisions int) *Model {
+	c := useDefaultConfig(t)
+	c.Revisions.LogBatching = true
+	c.Revisions.LogBatchSize = 5
+	c.Revisions.LogWindowSize = 10
+
+	var lines []string
+	for i := range revisions {
+		lines = append(lines,
+			fmt.Sprintf("○  _PREFIX:c%02d_PREFIX:d%02d_PREFIX:false revision %d", i, i, i),
+			fmt.Sprintf("│  description %d", i))
+	}
+	model, _ := newLogModel(t, lines, nil)
+	return model
+}
+
+// moveDownTo moves the cursor down one row at a time, which streams more rows
+// when it reaches the end of the loaded ones
+func moveDownTo(t *testing.T, model *Model, changeId string) {
+	for range 100 {
+		if model.SelectedRevision().ChangeId == changeId {
+			return
+		}
+		test.SimulateModel(model, model.Update(intents.Navigate{Delta: 1}))
+	}
+	t.Fatalf("%s is not reached", changeId)
+}
+
+func TestModel_Window_DropsRowsAboveTheCursor(t *testing.T) {
+	model := newWindowedModel(t, 40)
+	moveDownTo(t, model, "c35")
+
+	assert.Positive(t, model.rowOffset)
+	assert.LessOrEqual(t, len(model.rows), 10)
+	assert.Equal(t, fmt.Sprintf("c%02d", model.rowOffset), model.rows[0].Commit.ChangeId)
+}
+
+func TestModel_Window_StreamsDroppedRowsAgainWhenMovingUp(t *testing.T) {
+	model := newWindowedModel(t, 40)
+	moveDownTo(t, model, "c35")
+	offset := model.rowOffset
+	for model.rowOffset == offset {
+		test.SimulateModel(model, model.Update(intents.Navigate{Delta: -1}))
+	}
+
+	assert.Less(t, model.rowOffset, offset)
+	assert.Equal(t, fmt.Sprintf("c%02d", offset-1), model.SelectedRevision().ChangeId)
+}
+
+func TestModel_Window_JumpsToDroppedRevision(t *testing.T) {
+	model := newWindowedModel(t, 40)
+	moveDownTo(t, model, "c35")
+	require.Equal(t, -1, model.selectRevision("c02"))
+
+	test.SimulateModel(model, model.Update(intents.Navigate{ChangeID: "c02"}))
+	assert.Equal(t, "c02", model.SelectedRevision().ChangeId)
+
+	test.SimulateModel(model, model.Update(intents.Navigate{ChangeID: "c33"}))
+	assert.Equal(t, "c33", model.SelectedRevision().ChangeId)
+}
+
+func TestModel_Window_TargetTopLoadsTheFirstRows(t *testing.T) {
+	model := newWindowedModel(t, 40)
+	moveDownTo(t, model, "c35")
+
+	test.SimulateModel(model, model.Update(intents.Navigate{Target: intents.TargetTop}))
+	assert.Equal(t, 0, model.rowOffset)
+	assert.Equal(t, "c00", model.SelectedRevision().ChangeId)
+}
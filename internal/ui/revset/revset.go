@@ -1,12 +1,16 @@
 package revset
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/common/autocompletion"
 	appContext "github.com/idursun/jjui/internal/ui/context"
@@ -37,6 +41,21 @@ const (
 	pillWidth          = 10
 )
 
+const (
+	// validationLimit is the number of revisions counted while editing
+	validationLimit    = 100
+	validationDebounce = 300 * time.Millisecond
+	validationId       = "revset-validation"
+)
+
+// validatedMsg is the result of evaluating the revset being edited. err is
+// nil when the revset is valid.
+type validatedMsg struct {
+	revset string
+	count  int
+	err    *jj.RevsetError
+}
+
 type completionScrollMsg struct {
 	Delta      int
 	Horizontal bool
@@ -64,11 +83,14 @@ type Model struct {
 	completionItems    []CompletionItem
 	selectedIndex      int
 	userInput          string // tracks what the user actually typed (separate from preview)
+	validation         *validatedMsg
 }
 
 type styles struct {
-	title lipgloss.Style
-	text  lipgloss.Style
+	title  lipgloss.Style
+	text   lipgloss.Style
+	dimmed lipgloss.Style
+	err    lipgloss.Style
 
 	// Completion overlay styles
 	completionText       lipgloss.Style
@@ -102,6 +124,8 @@ func New(context *appContext.MainContext) *Model {
 	styles := styles{
 		title:                palette.Get("revset title"),
 		text:                 palette.Get("revset text"),
+		dimmed:               palette.Get("revset dimmed"),
+		err:                  palette.Get("revset error"),
 		completionText:       palette.Get("revset completion text"),
 		completionMatched:    palette.Get("revset completion matched"),
 		completionSelected:   palette.Get("revset completion selected"),
@@ -189,6 +213,12 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.selectedIndex = msg.index
 			item := m.completionItems[msg.index]
 			m.selectCompletionItem(item)
+			return m.validate()
+		}
+		return nil
+	case validatedMsg:
+		if m.Editing && msg.revset == m.autoComplete.Value() {
+			m.validation = &msg
 		}
 		return nil
 	case tea.KeyMsg:
@@ -209,7 +239,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				}
 				m.updatePreview()
 			}
-			return nil
+			return m.validate()
 		case tea.KeyShiftTab:
 			// Shift+Tab cycles backwards
 			if len(m.completionItems) > 0 {
@@ -220,7 +250,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				}
 				m.updatePreview()
 			}
-			return nil
+			return m.validate()
 		case tea.KeyUp:
 			if len(m.completionItems) > 0 {
 				if m.selectedIndex < 0 {
@@ -233,7 +263,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				}
 				m.updatePreview()
 			}
-			return nil
+			return m.validate()
 		case tea.KeyDown:
 			if len(m.completionItems) > 0 {
 				if m.selectedIndex < 0 {
@@ -246,7 +276,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				}
 				m.updatePreview()
 			}
-			return nil
+			return m.validate()
 		}
 	case common.UpdateRevSetMsg:
 		if m.Editing {
@@ -266,11 +296,34 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.userInput = newValue
 		m.selectedIndex = -1 // reset to no selection
 		m.updateCompletionItems()
+		cmd = tea.Batch(cmd, m.validate())
 	}
 
 	return cmd
 }
 
+// validate evaluates the revset being edited in the background once typing
+// pauses. The revisions shown are only updated when the revset is applied.
+func (m *Model) validate() tea.Cmd {
+	revset := m.autoComplete.Value()
+	if strings.TrimSpace(revset) == "" {
+		m.validation = nil
+		return nil
+	}
+	if m.validation != nil && m.validation.revset == revset {
+		return nil
+	}
+	runner := m.context
+	return common.Debounce(validationId, validationDebounce, func() tea.Msg {
+		output, err := runner.RunCommandImmediate(jj.GetIdsFromRevsetWithLimit(revset, validationLimit+1))
+		if err != nil {
+			revsetErr := jj.ParseRevsetError(revset, err.Error())
+			return validatedMsg{revset: revset, err: &revsetErr}
+		}
+		return validatedMsg{revset: revset, count: len(strings.Fields(string(output)))}
+	})
+}
+
 func (m *Model) selectCompletionItem(item CompletionItem) {
 	newValue := m.applyCompletion(m.userInput, item)
 
@@ -329,7 +382,8 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		}
 		m.selectedIndex = -1 // no selection initially
 		m.updateCompletionItems()
-		return m.autoComplete.Init()
+		m.validation = nil
+		return tea.Batch(m.autoComplete.Init(), m.validate())
 	case intents.Cancel:
 		m.Editing = false
 		m.autoComplete.Blur()
@@ -365,6 +419,7 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	if !m.Editing {
 		return
 	}
+	m.renderValidation(dl, box, lipgloss.Width(line))
 
 	// Check if we have completions to show or signature help
 	items := m.completionItems
@@ -432,6 +487,49 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	m.listRenderer.RegisterScroll(dl, outerBox)
 }
 
+// renderValidation shows the number of revisions of the revset, or the error
+// of jj with the part of the revset it points at underlined, after the input
+// that takes inputWidth columns
+func (m *Model) renderValidation(dl *render.DisplayContext, box layout.Box, inputWidth int) {
+	v := m.validation
+	if v == nil || v.revset != m.autoComplete.Value() {
+		return
+	}
+	text, style := validationText(v), m.styles.dimmed
+	if v.err != nil {
+		style = m.styles.err
+		if v.err.Start >= 0 {
+			prompt := lipgloss.Width(m.styles.title.PaddingRight(1).Render("revset:"))
+			runes := []rune(v.revset)
+			start := box.R.Min.X + prompt + ansi.StringWidth(string(runes[:v.err.Start]))
+			width := max(ansi.StringWidth(string(runes[v.err.Start:v.err.End])), 1)
+			if start+width <= box.R.Max.X {
+				dl.AddEffect(render.UnderlineEffect{Rect: cellbuf.Rect(start, box.R.Min.Y, width, 1), Z: render.ZFuzzyInput + 1})
+			}
+		}
+	}
+	available := box.R.Dx() - inputWidth - 2
+	if available <= 0 {
+		return
+	}
+	text = ansi.Truncate(text, available, "…")
+	x := box.R.Max.X - ansi.StringWidth(text)
+	dl.Text(x, box.R.Min.Y, render.ZFuzzyInput+1).Styled(text, style).Done()
+}
+
+func validationText(v *validatedMsg) string {
+	switch {
+	case v.err != nil:
+		return v.err.Message
+	case v.count > validationLimit:
+		return fmt.Sprintf("more than %d revisions", validationLimit)
+	case v.count == 1:
+		return "1 revision"
+	default:
+		return fmt.Sprintf("%d revisions", v.count)
+	}
+}
+
 func pillLabel(kind CompletionKind) string {
 	switch kind {
 	case KindFunction:
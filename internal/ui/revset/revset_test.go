@@ -1,9 +1,12 @@
 package revset
 
 import (
+	"errors"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,3 +43,36 @@ func TestModel_View_DisplaysCurrentRevset(t *testing.T) {
 	model := New(ctx)
 	assert.Contains(t, test.RenderImmediate(model, 80, 5), ctx.CurrentRevset)
 }
+
+func TestModel_Edit_ShowsRevisionCount(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListAll())
+	commandRunner.Expect(jj.TagList())
+	commandRunner.Expect(jj.GetIdsFromRevsetWithLimit("mine()", validationLimit+1)).SetOutput([]byte("kx\nzz\nyq"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "mine()"
+	ctx.DefaultRevset = "mine()"
+	model := New(ctx)
+	test.SimulateModel(model, model.handleIntent(intents.Edit{}))
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 1)), "3 revisions")
+}
+
+func TestModel_Edit_ShowsRevsetError(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListAll())
+	commandRunner.Expect(jj.TagList())
+	commandRunner.Expect(jj.GetIdsFromRevsetWithLimit("nope", validationLimit+1)).
+		SetError(errors.New("Error: Revision `nope` doesn't exist"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "nope"
+	ctx.DefaultRevset = "nope"
+	model := New(ctx)
+	test.SimulateModel(model, model.handleIntent(intents.Edit{}))
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 1)), "Revision `nope` doesn't exist")
+}
@@ -51,6 +51,8 @@ type Model struct {
 	styles          styles
 	statusExpanded  bool
 	statusTruncated bool
+	segments        []string
+	generation      int // of the segment polling, see segmentsMsg
 }
 
 type styles struct {
@@ -78,8 +80,41 @@ const CommandClearDuration = 3 * time.Second
 
 type clearMsg string
 
+// SegmentsInterval is how often the segments of the status line are updated
+const SegmentsInterval = 2 * time.Second
+
+// segmentsMsg carries the generation of the polling it belongs to. Init starts
+// a new one, so that the polling of a resumed UI doesn't run twice.
+type segmentsMsg struct {
+	generation int
+	segments   []string
+}
+
 func (m *Model) Init() tea.Cmd {
-	return nil
+	m.generation++
+	return m.pollSegments(0)
+}
+
+// pollSegments gets the segments of the status line from the providers of the
+// context after the delay, those of init.lua first
+func (m *Model) pollSegments(delay time.Duration) tea.Cmd {
+	var providers []context.StatusSegmentProvider
+	for _, provider := range []context.StatusSegmentProvider{m.context.StatusSegments, m.context.PluginSegments} {
+		if provider != nil {
+			providers = append(providers, provider)
+		}
+	}
+	if len(providers) == 0 {
+		return nil
+	}
+	generation := m.generation
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		var segments []string
+		for _, provider := range providers {
+			segments = append(segments, provider.StatusSegments()...)
+		}
+		return segmentsMsg{generation: generation, segments: segments}
+	})
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
@@ -91,6 +126,21 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.status = none
 		}
 		return nil
+	case common.ConfigReloadedMsg:
+		m.setStyles()
+		// the plugins were loaded again, so is their provider
+		m.generation++
+		cmd := m.pollSegments(0)
+		if cmd == nil {
+			m.segments = nil
+		}
+		return cmd
+	case segmentsMsg:
+		if msg.generation != m.generation {
+			return nil
+		}
+		m.segments = msg.segments
+		return m.pollSegments(SegmentsInterval)
 	case common.CommandRunningMsg:
 		m.command = string(msg)
 		m.status = commandRunning
@@ -226,6 +276,11 @@ func (m *Model) loadEditingSuggestions() {
 }
 
 func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	if segments := m.renderSegments(); segments != "" {
+		var segmentsBox layout.Box
+		box, segmentsBox = box.CutRight(min(lipgloss.Width(segments), box.R.Dx()))
+		dl.AddDraw(segmentsBox.R, segments, 0)
+	}
 	width := box.R.Dx()
 	modeWidth := max(10, len(m.mode)+2)
 	mode := m.styles.title.Width(modeWidth).Render(" ", m.mode)
@@ -249,6 +304,22 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	m.renderFuzzyOverlay(dl, box)
 }
 
+// renderSegments returns the segments of the status line separated by a
+// divider
+func (m *Model) renderSegments() string {
+	var parts []string
+	for _, segment := range m.segments {
+		if segment = strings.TrimSpace(strings.ReplaceAll(segment, "\n", " ")); segment != "" {
+			parts = append(parts, m.styles.text.Render(segment))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	separator := m.styles.dimmed.Render(" │ ")
+	return separator + strings.Join(parts, separator) + m.styles.text.Render(" ")
+}
+
 // renderStatusMark returns the command status indicator (spinner/success/error).
 func (m *Model) renderStatusMark() string {
 	switch m.status {
@@ -512,8 +583,8 @@ func (m *Model) collectHelpEntriesWithLimit(keyMap help.KeyMap, maxWidth, separa
 	return entries, false
 }
 
-func New(context *context.MainContext) *Model {
-	styles := styles{
+func (m *Model) setStyles() {
+	m.styles = styles{
 		shortcut: common.DefaultPalette.Get("status shortcut"),
 		dimmed:   common.DefaultPalette.Get("status dimmed"),
 		text:     common.DefaultPalette.Get("status text"),
@@ -521,22 +592,26 @@ func New(context *context.MainContext) *Model {
 		success:  common.DefaultPalette.Get("status success"),
 		error:    common.DefaultPalette.Get("status error"),
 	}
+	m.input.TextStyle = m.styles.text
+	m.input.CompletionStyle = m.styles.dimmed
+	m.input.PlaceholderStyle = m.styles.dimmed
+}
+
+func New(context *context.MainContext) *Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 
 	t := textinput.New()
 	t.Width = 50
-	t.TextStyle = styles.text
-	t.CompletionStyle = styles.dimmed
-	t.PlaceholderStyle = styles.dimmed
 
-	return &Model{
+	m := &Model{
 		context: context,
 		spinner: s,
 		command: "",
 		status:  none,
 		input:   t,
 		keyMap:  nil,
-		styles:  styles,
 	}
+	m.setStyles()
+	return m
 }
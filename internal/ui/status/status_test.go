@@ -2,12 +2,15 @@ package status
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStatus_Update_ExecProcessCompletedMsg(t *testing.T) {
@@ -69,3 +72,72 @@ func TestStatus_Update_ExecProcessCompletedMsg(t *testing.T) {
 		})
 	}
 }
+
+type segmentProvider []string
+
+func (p segmentProvider) StatusSegments() []string {
+	return p
+}
+
+func TestStatus_ShowsSegmentsOfProvider(t *testing.T) {
+	ctx := &context.MainContext{
+		Histories:      config.NewHistories(),
+		StatusSegments: segmentProvider{"ci: passing", "", "3 ahead"},
+	}
+	m := New(ctx)
+
+	cmd := m.Init()
+	require.NotNil(t, cmd)
+	assert.NotNil(t, m.Update(cmd()), "the segments are polled again")
+
+	rendered := test.Stripped(test.RenderImmediate(m, 80, 1))
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(rendered), "│ ci: passing │ 3 ahead"), rendered)
+}
+
+func TestStatus_ShowsSegmentsOfPluginsAfterInitLua(t *testing.T) {
+	ctx := &context.MainContext{
+		Histories:      config.NewHistories(),
+		StatusSegments: segmentProvider{"ci: passing"},
+		PluginSegments: segmentProvider{"ticket: JJ-1"},
+	}
+	m := New(ctx)
+	m.Update(m.Init()())
+
+	rendered := test.Stripped(test.RenderImmediate(m, 80, 1))
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(rendered), "│ ci: passing │ ticket: JJ-1"), rendered)
+}
+
+func TestStatus_PollsPluginSegmentsOfReloadedConfig(t *testing.T) {
+	ctx := &context.MainContext{Histories: config.NewHistories()}
+	m := New(ctx)
+	require.Nil(t, m.Init())
+
+	ctx.PluginSegments = segmentProvider{"ticket: JJ-1"}
+	cmd := m.Update(common.ConfigReloadedMsg{})
+	require.NotNil(t, cmd)
+	m.Update(cmd())
+	rendered := test.Stripped(test.RenderImmediate(m, 80, 1))
+	assert.Contains(t, rendered, "ticket: JJ-1")
+
+	ctx.PluginSegments = nil
+	assert.Nil(t, m.Update(common.ConfigReloadedMsg{}))
+	rendered = test.Stripped(test.RenderImmediate(m, 80, 1))
+	assert.NotContains(t, rendered, "ticket: JJ-1", "the segments of removed plugins are dropped")
+}
+
+func TestStatus_DropsSegmentsOfPreviousPolling(t *testing.T) {
+	ctx := &context.MainContext{
+		Histories:      config.NewHistories(),
+		StatusSegments: segmentProvider{"ci: passing"},
+	}
+	m := New(ctx)
+
+	stale := m.Init()()
+	require.NotNil(t, m.Init())
+	assert.Nil(t, m.Update(stale), "only the polling started last continues")
+}
+
+func TestStatus_DoesNotPollWithoutProvider(t *testing.T) {
+	m := New(&context.MainContext{Histories: config.NewHistories()})
+	assert.Nil(t, m.Init())
+}
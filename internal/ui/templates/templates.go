@@ -0,0 +1,316 @@
+package templates
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ help.KeyMap           = (*Model)(nil)
+)
+
+const maxVisibleRows = 15
+
+type tab int
+
+const (
+	templatesTab tab = iota
+	fieldsTab
+)
+
+type loadedMsg struct {
+	aliases []string
+	err     error
+}
+
+type rowClickedMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// entry is a template that can be picked, an empty template goes back to the
+// one of the view or the config
+type entry struct {
+	name     string
+	template string
+	kind     string
+}
+
+type styles struct {
+	border   lipgloss.Style
+	title    lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+	err      lipgloss.Style
+}
+
+// Model picks the template of the revisions among jj's builtin templates and
+// the template aliases, or builds one from the fields toggled on. The fields
+// are kept between openings.
+type Model struct {
+	context             *context.MainContext
+	entries             []entry
+	err                 error
+	tab                 tab
+	cursor              int
+	fields              []jj.LogField
+	fieldCursor         int
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	bindings := []key.Binding{m.keymap.Up, m.keymap.Down, m.keymap.Apply}
+	if m.tab == fieldsTab {
+		bindings = append(bindings, m.keymap.Templates.Toggle)
+	}
+	return append(bindings, m.keymap.Templates.Fields, m.keymap.Templates.Close)
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	ctx := m.context
+	return func() tea.Msg {
+		output, err := ctx.RunCommandImmediate(jj.TemplateAliases())
+		if err != nil {
+			return loadedMsg{err: err}
+		}
+		aliases, err := config.DefaultConfig(output)
+		if err != nil {
+			return loadedMsg{err: err}
+		}
+		return loadedMsg{aliases: aliases.TemplateNames()}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.err = msg.err
+		m.entries = m.load(msg.aliases)
+		m.cursor = max(slices.IndexFunc(m.entries, func(e entry) bool { return e.template == m.context.Template }), 0)
+		m.ensureCursorVisible = true
+	case tea.KeyMsg:
+		km := m.keymap.Templates
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.move(1)
+		case key.Matches(msg, km.Fields):
+			m.tab = (m.tab + 1) % 2
+			m.ensureCursorVisible = true
+		case key.Matches(msg, km.Toggle) && m.tab == fieldsTab:
+			return m.toggle(m.fieldCursor)
+		case key.Matches(msg, m.keymap.Apply):
+			return tea.Sequence(common.Close, m.apply())
+		case key.Matches(msg, km.Close), key.Matches(msg, m.keymap.Cancel):
+			return common.Close
+		}
+	case rowClickedMsg:
+		if m.tab == fieldsTab {
+			m.fieldCursor = msg.Index
+			return m.toggle(msg.Index)
+		}
+		m.cursor = msg.Index
+		return tea.Sequence(common.Close, m.apply())
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.ensureCursorVisible = false
+		m.listRenderer.SetScrollOffset(max(0, m.listRenderer.GetScrollOffset()+msg.Delta))
+	}
+	return nil
+}
+
+// load lists the default template, jj's builtin log templates and the
+// aliases of the user
+func (m *Model) load(aliases []string) []entry {
+	entries := []entry{{name: "default", template: "", kind: m.context.DefaultLogTemplate()}}
+	defined := m.context.JJConfig.TemplateNames()
+	for _, name := range jj.BuiltinLogTemplates {
+		if len(defined) == 0 || slices.Contains(defined, name) {
+			entries = append(entries, entry{name: name, template: name, kind: "builtin"})
+		}
+	}
+	for _, name := range aliases {
+		if !slices.Contains(jj.BuiltinLogTemplates, name) {
+			entries = append(entries, entry{name: name, template: name, kind: "alias"})
+		}
+	}
+	return entries
+}
+
+func (m *Model) move(delta int) {
+	cursor, count := &m.cursor, len(m.entries)
+	if m.tab == fieldsTab {
+		cursor, count = &m.fieldCursor, len(jj.LogFields)
+	}
+	next := *cursor + delta
+	if next < 0 || next >= count {
+		return
+	}
+	*cursor = next
+	m.ensureCursorVisible = true
+}
+
+func (m *Model) apply() tea.Cmd {
+	if m.tab == fieldsTab {
+		return intents.Invoke(intents.SetTemplate{Template: jj.FieldsTemplate(m.fields)})
+	}
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return nil
+	}
+	return intents.Invoke(intents.SetTemplate{Template: m.entries[m.cursor].template})
+}
+
+// toggle turns the field on or off and shows the revisions with the fields
+// right away
+func (m *Model) toggle(index int) tea.Cmd {
+	if index < 0 || index >= len(jj.LogFields) {
+		return nil
+	}
+	field := jj.LogFields[index]
+	if slices.Contains(m.fields, field) {
+		m.fields = slices.DeleteFunc(m.fields, func(f jj.LogField) bool { return f == field })
+	} else {
+		m.fields = append(m.fields, field)
+	}
+	return m.apply()
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	count, cursor := len(m.entries), m.cursor
+	if m.tab == fieldsTab {
+		count, cursor = len(jj.LogFields), m.fieldCursor
+	}
+	height := min(max(count, 1), maxVisibleRows) + 3
+	frame := box.Center(min(max(box.R.Dx()-8, 0), 100), min(height, max(box.R.Dy()-2, 0)))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 3 {
+		return
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	contentBox := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	window.AddDraw(frame.R, m.styles.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(contentBox.R, ' ', m.styles.text, render.ZDialogs)
+
+	titleBox, listBox := contentBox.CutTop(1)
+	templatesStyle, fieldsStyle := m.styles.title, m.styles.dimmed
+	if m.tab == fieldsTab {
+		templatesStyle, fieldsStyle = m.styles.dimmed, m.styles.title
+	}
+	window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).
+		Styled("Templates", templatesStyle).
+		Styled(" | ", m.styles.dimmed).
+		Styled("Fields", fieldsStyle).
+		Done()
+
+	if m.tab == templatesTab && m.err != nil {
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled(m.err.Error(), m.styles.err).Done()
+		listBox.R.Min.Y++
+	}
+
+	nameWidth := 0
+	for _, e := range m.entries {
+		nameWidth = max(nameWidth, lipgloss.Width(e.name))
+	}
+	m.listRenderer.Render(
+		window,
+		listBox,
+		count,
+		cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			if m.tab == fieldsTab {
+				m.renderField(dl, index, rect)
+			} else {
+				m.renderEntry(dl, index, rect, nameWidth)
+			}
+		},
+		func(index int) tea.Msg { return rowClickedMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(window, listBox)
+	m.ensureCursorVisible = false
+}
+
+func (m *Model) renderEntry(dl *render.DisplayContext, index int, rect cellbuf.Rectangle, nameWidth int) {
+	e := m.entries[index]
+	marker := "  "
+	if e.template == m.context.Template {
+		marker = "● "
+	}
+	dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs).
+		Styled(marker, m.styles.text).
+		Styled(e.name+strings.Repeat(" ", nameWidth-lipgloss.Width(e.name)), m.styles.text).
+		Space(2).
+		Styled(e.kind, m.styles.dimmed).
+		Done()
+	if index == m.cursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZDialogs+1)
+	}
+}
+
+func (m *Model) renderField(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+	field := jj.LogFields[index]
+	checkbox := "[ ] "
+	if slices.Contains(m.fields, field) {
+		checkbox = "[x] "
+	}
+	dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs).
+		Styled(checkbox, m.styles.text).
+		Styled(strings.ReplaceAll(string(field), "_", " "), m.styles.text).
+		Done()
+	if index == m.fieldCursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZDialogs+1)
+	}
+}
+
+func New(context *context.MainContext) *Model {
+	return &Model{
+		context:      context,
+		fields:       slices.Clone(jj.DefaultLogFields),
+		keymap:       config.Current.GetKeyMap(),
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+		styles: styles{
+			border:   common.DefaultPalette.GetBorder("templates border", lipgloss.RoundedBorder()),
+			title:    common.DefaultPalette.Get("templates title"),
+			text:     common.DefaultPalette.Get("templates text"),
+			dimmed:   common.DefaultPalette.Get("templates dimmed"),
+			selected: common.DefaultPalette.Get("templates selected"),
+			err:      common.DefaultPalette.Get("templates error"),
+		},
+	}
+}
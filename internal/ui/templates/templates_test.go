@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const aliases = `template-aliases.compact_with_pr = 'builtin_log_compact ++ "pr"'
+template-aliases.'my_id(id)' = 'id.short()'
+`
+
+func TestInit_ListsBuiltinTemplatesAndAliases(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TemplateAliases()).SetOutput([]byte(aliases))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "default")
+	assert.Contains(t, rendered, "builtin_log_oneline")
+	assert.Contains(t, rendered, "compact_with_pr")
+	assert.NotContains(t, rendered, "my_id")
+}
+
+func TestApply_SetsSelectedTemplate(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TemplateAliases())
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("j"))
+
+	var picked intents.SetTemplate
+	test.SimulateModel(model, test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		if got, ok := msg.(intents.SetTemplate); ok {
+			picked = got
+		}
+	})
+	assert.Equal(t, jj.BuiltinLogTemplates[0], picked.Template)
+}
+
+func TestToggle_SetsTemplateOfFields(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TemplateAliases())
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Press(tea.KeyTab))
+
+	var picked intents.SetTemplate
+	test.SimulateModel(model, test.Type(" "), func(msg tea.Msg) {
+		if got, ok := msg.(intents.SetTemplate); ok {
+			picked = got
+		}
+	})
+	assert.NotContains(t, picked.Template, "author")
+	assert.Contains(t, picked.Template, "committer.timestamp()")
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "[ ] author")
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,11 +25,15 @@ import (
 	"github.com/idursun/jjui/internal/ui/bookmarks"
 	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/conflicts"
 	"github.com/idursun/jjui/internal/ui/context"
 	customcommands "github.com/idursun/jjui/internal/ui/custom_commands"
 	"github.com/idursun/jjui/internal/ui/diff"
+	"github.com/idursun/jjui/internal/ui/dryrun"
 	"github.com/idursun/jjui/internal/ui/exec_process"
 	"github.com/idursun/jjui/internal/ui/git"
+	"github.com/idursun/jjui/internal/ui/hunks"
+	"github.com/idursun/jjui/internal/ui/journal"
 
 	"github.com/idursun/jjui/internal/ui/input"
 	"github.com/idursun/jjui/internal/ui/leader"
@@ -37,7 +43,10 @@ import (
 	"github.com/idursun/jjui/internal/ui/revisions"
 	"github.com/idursun/jjui/internal/ui/revset"
 	"github.com/idursun/jjui/internal/ui/status"
+	"github.com/idursun/jjui/internal/ui/templates"
 	"github.com/idursun/jjui/internal/ui/undo"
+	"github.com/idursun/jjui/internal/ui/views"
+	"github.com/idursun/jjui/internal/ui/widgets"
 )
 
 type Model struct {
@@ -55,6 +64,7 @@ type Model struct {
 	scriptRunner     *scripting.Runner
 	keyMap           config.KeyMappings[key.Binding]
 	stacked          common.ImmediateModel
+	templates        *templates.Model // kept so the fields toggled on stay between openings
 	sequenceOverlay  *customcommands.SequenceOverlay
 	displayContext   *render.DisplayContext
 	width            int
@@ -64,6 +74,8 @@ type Model struct {
 	splitActive      bool
 	layerStack       []uiLayerEntry
 	activeRevisionOp string
+	generation       int
+	reloadingConfig  bool
 }
 
 type uiLayer int
@@ -82,10 +94,40 @@ type uiLayerEntry struct {
 	name string
 }
 
-type triggerAutoRefreshMsg struct{}
+// the ticks are stamped with the generation they were scheduled in, resuming
+// the UI starts a new one so that the ticks still pending are dropped
+type triggerAutoRefreshMsg struct {
+	generation int
+}
+
+// checkConfigMsg polls the config files for changes
+type checkConfigMsg struct {
+	generation int
+}
+
+// configLoadedMsg is the configuration read after the files changed
+type configLoadedMsg struct {
+	config *context.LoadedConfig
+	err    error
+}
+
+const configCheckInterval = time.Second
 
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(tea.SetWindowTitle(fmt.Sprintf("jjui - %s", m.context.Location)), m.revisions.Init(), m.scheduleAutoRefresh())
+	return tea.Batch(tea.SetWindowTitle(fmt.Sprintf("jjui - %s", m.context.Location)), m.revisions.Init(), m.status.Init(), m.scheduleAutoRefresh(), m.scheduleConfigCheck(), m.configWarnings())
+}
+
+// resume shows the UI again after the dashboard, with the configuration of its
+// repository loaded again
+func (m *Model) resume() tea.Cmd {
+	m.generation++
+	return tea.Batch(
+		tea.SetWindowTitle(fmt.Sprintf("jjui - %s", m.context.Location)),
+		m.Update(common.ConfigReloadedMsg{}),
+		m.status.Init(),
+		m.scheduleAutoRefresh(),
+		m.scheduleConfigCheck(),
+	)
 }
 
 func (m *Model) pushLayer(kind uiLayer, name string) {
@@ -274,6 +316,33 @@ func (m *Model) shouldStartSequenceOverlay(msg tea.KeyMsg) bool {
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	defer m.syncRevisionOpLayer()
+	// handled before the focused views so that polling never stops and all
+	// the views get the reloaded configuration
+	switch msg := msg.(type) {
+	case checkConfigMsg:
+		if msg.generation != m.generation {
+			return nil
+		}
+		// the files are checked again once the preview restored the repository
+		// and the configuration being loaded is applied
+		if !m.context.Previewing.Load() && !m.reloadingConfig && m.context.ConfigLoader.Changed() {
+			return tea.Batch(m.scheduleConfigCheck(), m.reloadConfig())
+		}
+		return m.scheduleConfigCheck()
+	case configLoadedMsg:
+		m.reloadingConfig = false
+		if msg.err != nil {
+			return intents.Invoke(intents.AddMessage{Text: "Failed to reload the configuration", Err: msg.err})
+		}
+		m.context.ConfigLoader.Apply(m.context, msg.config)
+		return tea.Batch(
+			m.configReloaded(),
+			intents.Invoke(intents.AddMessage{Text: "Configuration reloaded"}),
+			m.configWarnings(),
+		)
+	case common.ConfigReloadedMsg:
+		return m.configReloaded()
+	}
 	if cmd, handled := m.handleFocusInputMessage(msg); handled {
 		return cmd
 	}
@@ -295,6 +364,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				return nil
 			}
 		}
+		if m.revisions.Dragging() && msg.Action != tea.MouseActionPress {
+			return m.revisions.Update(msg)
+		}
 
 		// Process interactions from DisplayContext first
 		if m.displayContext != nil {
@@ -349,6 +421,12 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return m.handleIntent(intents.Edit{Clear: m.state != common.Error})
 		case key.Matches(msg, m.keyMap.Git.Mode) && m.revisions.InNormalMode():
 			return m.handleIntent(intents.OpenGit{})
+		case key.Matches(msg, m.keyMap.Journal.Mode) && m.revisions.InNormalMode():
+			return m.handleIntent(intents.OpenJournal{})
+		case key.Matches(msg, m.keyMap.Views.Mode) && m.revisions.InNormalMode():
+			return m.handleIntent(intents.OpenViews{})
+		case key.Matches(msg, m.keyMap.Templates.Mode) && m.revisions.InNormalMode():
+			return m.handleIntent(intents.OpenTemplates{})
 		case key.Matches(msg, m.keyMap.Undo) && m.revisions.InNormalMode():
 			return m.handleIntent(intents.Undo{})
 		case key.Matches(msg, m.keyMap.Redo) && m.revisions.InNormalMode():
@@ -397,6 +475,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		case key.Matches(msg, m.keyMap.Suspend):
 			return m.handleIntent(intents.Suspend{})
 		default:
+			if name := viewForKey(msg); name != "" && m.revisions.InNormalMode() {
+				return m.handleIntent(intents.ApplyView{Name: name})
+			}
 			for _, command := range customcommands.SortedCustomCommands(m.context) {
 				if !command.IsApplicableTo(m.context.SelectedItem) {
 					continue
@@ -430,6 +511,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		}
 		return res.Cmd
 	case triggerAutoRefreshMsg:
+		if msg.generation != m.generation {
+			return nil
+		}
+		if m.context.Previewing.Load() {
+			// the repository is at the operation of the command being previewed
+			return m.scheduleAutoRefresh()
+		}
 		return tea.Batch(m.scheduleAutoRefresh(), func() tea.Msg {
 			return common.AutoRefreshMsg{}
 		})
@@ -438,6 +526,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		if m.context.CurrentRevset == "" {
 			m.context.CurrentRevset = m.context.DefaultRevset
 		}
+		if view := m.context.View; view != nil && view.Revset != m.context.CurrentRevset {
+			m.context.View = nil
+		}
 		m.revsetModel.AddToHistory(m.context.CurrentRevset)
 		return common.Refresh
 	case common.RunLuaScriptMsg:
@@ -457,6 +548,14 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.stacked = model
 		m.pushLayer(uiLayerStacked, "choose")
 		return m.stacked.Init()
+	case common.ShowHunkPickerMsg:
+		m.stacked = hunks.New(m.context, msg.Revision, msg.Diff)
+		m.pushLayer(uiLayerStacked, "hunks")
+		return m.stacked.Init()
+	case common.ShowConflictsMsg:
+		m.stacked = conflicts.New(m.context, msg.Revision, msg.Files)
+		m.pushLayer(uiLayerStacked, "conflicts")
+		return m.stacked.Init()
 	case choose.SelectedMsg, choose.CancelledMsg:
 		m.stacked = nil
 		m.removeLayer(uiLayerStacked)
@@ -468,6 +567,17 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	case input.SelectedMsg, input.CancelledMsg:
 		m.stacked = nil
 		m.removeLayer(uiLayerStacked)
+	case dryrun.ShowMsg:
+		m.stacked = dryrun.New(m.context, msg.Command)
+		m.pushLayer(uiLayerStacked, "dry run")
+		return m.stacked.Init()
+	case widgets.ShowMsg:
+		m.stacked = msg.Widget
+		m.pushLayer(uiLayerStacked, msg.Name)
+		return m.stacked.Init()
+	case widgets.DoneMsg:
+		m.stacked = nil
+		m.removeLayer(uiLayerStacked)
 	case common.ShowPreview:
 		cmds = append(cmds, m.setPreviewVisible(bool(msg)))
 		return tea.Batch(cmds...)
@@ -676,14 +786,64 @@ func (m *Model) initSplit() {
 
 func (m *Model) scheduleAutoRefresh() tea.Cmd {
 	interval := config.Current.UI.AutoRefreshInterval
+	generation := m.generation
 	if interval > 0 {
 		return tea.Tick(time.Duration(interval)*time.Second, func(time.Time) tea.Msg {
-			return triggerAutoRefreshMsg{}
+			return triggerAutoRefreshMsg{generation: generation}
 		})
 	}
 	return nil
 }
 
+func (m *Model) scheduleConfigCheck() tea.Cmd {
+	if m.context.ConfigLoader == nil {
+		return nil
+	}
+	generation := m.generation
+	return tea.Tick(configCheckInterval, func(time.Time) tea.Msg {
+		return checkConfigMsg{generation: generation}
+	})
+}
+
+// reloadConfig loads the changed configuration, which is applied when it
+// arrives. Errors are reported and the current configuration is kept.
+func (m *Model) reloadConfig() tea.Cmd {
+	m.reloadingConfig = true
+	loader, ctx := m.context.ConfigLoader, m.context
+	return func() tea.Msg {
+		loaded, err := loader.Read(ctx)
+		return configLoadedMsg{config: loaded, err: err}
+	}
+}
+
+// configReloaded passes the reloaded configuration to the views so that they
+// rebuild their styles and key bindings
+func (m *Model) configReloaded() tea.Cmd {
+	msg := common.ConfigReloadedMsg{}
+	m.keyMap = config.Current.GetKeyMap()
+	cmds := []tea.Cmd{m.revisions.Update(msg), m.status.Update(msg), common.RefreshAndKeepSelections}
+	if m.oplog != nil {
+		cmds = append(cmds, m.oplog.Update(msg))
+	}
+	if m.diff != nil {
+		cmds = append(cmds, m.diff.Update(msg))
+	}
+	if m.stacked != nil {
+		cmds = append(cmds, m.stacked.Update(msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// configWarnings shows the problems found in the loaded configuration files
+// until they are dismissed
+func (m *Model) configWarnings() tea.Cmd {
+	if m.context.ConfigLoader == nil || len(m.context.ConfigLoader.Warnings) == 0 {
+		return nil
+	}
+	text := "Configuration problems:\n" + strings.Join(m.context.ConfigLoader.Warnings, "\n")
+	return intents.Invoke(intents.AddMessage{Text: text, Sticky: true})
+}
+
 func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 	switch intent := intent.(type) {
 	case intents.Cancel:
@@ -727,6 +887,9 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		if !m.isSafeToQuit() {
 			return nil
 		}
+		if m.context.InDashboard {
+			return common.ShowDashboard
+		}
 		return tea.Quit
 	case intents.Suspend:
 		return tea.Suspend
@@ -752,6 +915,37 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		m.stacked = model
 		m.pushLayer(uiLayerStacked, "git")
 		return m.stacked.Init()
+	case intents.OpenJournal:
+		if !m.revisions.InNormalMode() {
+			return nil
+		}
+		m.stacked = journal.New(m.context)
+		m.pushLayer(uiLayerStacked, "journal")
+		return m.stacked.Init()
+	case intents.OpenViews:
+		if !m.revisions.InNormalMode() {
+			return nil
+		}
+		m.stacked = views.New(m.context)
+		m.pushLayer(uiLayerStacked, "views")
+		return m.stacked.Init()
+	case intents.ApplyView:
+		return m.applyView(intent.Name)
+	case intents.OpenTemplates:
+		if !m.revisions.InNormalMode() {
+			return nil
+		}
+		if m.templates == nil {
+			m.templates = templates.New(m.context)
+		}
+		m.stacked = m.templates
+		m.pushLayer(uiLayerStacked, "templates")
+		return m.stacked.Init()
+	case intents.SetTemplate:
+		m.context.Template = intent.Template
+		return common.RefreshAndKeepSelections
+	case intents.SaveView:
+		return m.saveView(intent.Name)
 	case intents.OpLogOpen:
 		if !m.revisions.InNormalMode() {
 			return nil
@@ -827,7 +1021,7 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		m.pushLayer(uiLayerStacked, "custom commands")
 		return m.stacked.Init()
 	case intents.OpenLeader:
-		m.leader = leader.New(m.context)
+		m.leader = leader.New(m.context, m.revisions.OperationName())
 		m.pushLayer(uiLayerLeader, "leader")
 		return leader.InitCmd
 	default:
@@ -874,6 +1068,58 @@ func bookmarkForPR(bookmarks []jj.Bookmark) string {
 	return ""
 }
 
+// applyView shows the revset of the view with its settings. The view is left
+// when another revset is set.
+func (m *Model) applyView(name string) tea.Cmd {
+	available, err := m.context.Views()
+	if err != nil {
+		return intents.Invoke(intents.AddMessage{Text: "Failed to load the saved views", Err: err})
+	}
+	view, ok := available[name]
+	if !ok {
+		return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("There is no view named %s", name), Err: errors.New("unknown view")})
+	}
+	m.context.View = &view
+	if view.Template != "" {
+		m.context.Template = ""
+	}
+	cmds := []tea.Cmd{common.UpdateRevSet(view.Revset)}
+	if view.Preview != nil {
+		cmds = append(cmds, m.setPreviewVisible(*view.Preview))
+	}
+	return tea.Batch(cmds...)
+}
+
+// saveView saves the current revset, the template picked and the preview as a
+// view of the repository, keeping the settings of the view shown
+func (m *Model) saveView(name string) tea.Cmd {
+	view := config.ViewConfig{}
+	if m.context.View != nil {
+		view = *m.context.View
+		view.Key = nil
+	}
+	if m.context.Template != "" {
+		view.Template = m.context.Template
+	}
+	visible := m.previewModel.Visible()
+	view.Name, view.Revset, view.Preview = name, m.context.CurrentRevset, &visible
+	if err := m.context.SavedViews.Save(view); err != nil {
+		return intents.Invoke(intents.AddMessage{Text: "Failed to save the view", Err: err})
+	}
+	m.context.View = &view
+	return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("Saved view %s", name)})
+}
+
+// viewForKey returns the name of the view of the config bound to the key
+func viewForKey(msg tea.KeyMsg) string {
+	for _, name := range slices.Sorted(maps.Keys(config.Current.Views)) {
+		if keys := config.Current.Views[name].Key; len(keys) > 0 && key.Matches(msg, key.NewBinding(key.WithKeys(keys...))) {
+			return name
+		}
+	}
+	return ""
+}
+
 func (m *Model) isSafeToQuit() bool {
 	if m.stacked != nil {
 		return false
@@ -889,10 +1135,17 @@ func (m *Model) isSafeToQuit() bool {
 
 var _ tea.Model = (*wrapper)(nil)
 
+// frame is the model rendered by the wrapper
+type frame interface {
+	Init() tea.Cmd
+	Update(msg tea.Msg) tea.Cmd
+	View() string
+}
+
 type (
 	frameTickMsg struct{}
 	wrapper      struct {
-		ui                 *Model
+		ui                 frame
 		scheduledNextFrame bool
 		render             bool
 		cachedFrame        string
@@ -1,19 +1,24 @@
 package ui
 
 import (
+	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/git"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/render"
 	"github.com/idursun/jjui/internal/ui/revset"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Update_EscapeQuitsInRootRevisionsMode(t *testing.T) {
@@ -252,3 +257,64 @@ func Test_GitWithExpandedStatus_EscClosesStatusFirst(t *testing.T) {
 	// Stacked (git) should still be open
 	assert.NotNil(t, model.stacked, "stacked (git) should still be open after closing expanded status")
 }
+
+func Test_HandleIntent_ApplyViewShowsItsRevsetAndPreview(t *testing.T) {
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+	config.Current = config.Default()
+	preview := true
+	config.Current.Views = map[string]config.ViewConfig{"mine": {Revset: "mine()", Limit: 5, Preview: &preview}}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	model := NewUI(ctx)
+
+	assert.NotNil(t, model.handleIntent(intents.ApplyView{Name: "mine"}))
+	assert.Equal(t, "mine", ctx.View.Name)
+	assert.Equal(t, 5, ctx.LogLimit())
+	assert.True(t, model.previewModel.Visible())
+
+	model.Update(common.UpdateRevSetMsg("mine()"))
+	assert.NotNil(t, ctx.View)
+	model.Update(common.UpdateRevSetMsg("all()"))
+	assert.Nil(t, ctx.View)
+}
+
+func Test_HandleIntent_SaveViewStoresCurrentRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SavedViews = config.NewSavedViewsFile(filepath.Join(t.TempDir(), "views.toml"))
+	ctx.CurrentRevset = "trunk()..@"
+	model := NewUI(ctx)
+
+	model.handleIntent(intents.SaveView{Name: "stack"})
+
+	saved, err := ctx.SavedViews.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "trunk()..@", saved["stack"].Revset)
+	assert.Equal(t, "stack", ctx.View.Name)
+}
+
+func Test_HandleIntent_SetTemplateOverridesTemplateOfView(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	ctx.View = &config.ViewConfig{Name: "mine", Revset: "mine()", Template: "builtin_log_detailed"}
+	model := NewUI(ctx)
+
+	assert.NotNil(t, model.handleIntent(intents.SetTemplate{Template: "builtin_log_oneline"}))
+	assert.Equal(t, "builtin_log_oneline", ctx.LogTemplate())
+
+	model.handleIntent(intents.SetTemplate{})
+	assert.Equal(t, "builtin_log_detailed", ctx.LogTemplate())
+}
+
+func Test_Update_FailedConfigReloadKeepsPolling(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := NewUI(ctx)
+	model.reloadingConfig = true
+
+	cmd := model.Update(configLoadedMsg{err: errors.New("bad toml")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "Failed to reload the configuration", Err: errors.New("bad toml")}, cmd())
+	assert.False(t, model.reloadingConfig)
+}
@@ -0,0 +1,301 @@
+package views
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ help.KeyMap           = (*Model)(nil)
+)
+
+const maxVisibleViews = 15
+
+type loadedMsg struct {
+	views map[string]config.ViewConfig
+	saved map[string]config.ViewConfig
+	err   error
+}
+
+type rowClickedMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type styles struct {
+	border   lipgloss.Style
+	title    lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+	shortcut lipgloss.Style
+	err      lipgloss.Style
+}
+
+// Model picks one of the views of the config and the repository, and saves
+// the current revset as a new view.
+type Model struct {
+	context             *context.MainContext
+	views               []config.ViewConfig
+	saved               map[string]config.ViewConfig
+	err                 error
+	cursor              int
+	naming              bool
+	name                textinput.Model
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	if m.naming {
+		return []key.Binding{m.keymap.Apply, m.keymap.Cancel}
+	}
+	return []key.Binding{
+		m.keymap.Up,
+		m.keymap.Down,
+		m.keymap.Apply,
+		m.keymap.Views.Save,
+		m.keymap.Views.Delete,
+		m.keymap.Views.Close,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func (m *Model) Init() tea.Cmd {
+	ctx := m.context
+	return func() tea.Msg {
+		views, err := ctx.Views()
+		if err != nil {
+			return loadedMsg{views: views, err: err}
+		}
+		saved, err := ctx.SavedViews.Load()
+		return loadedMsg{views: views, saved: saved, err: err}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.err = msg.err
+		m.saved = msg.saved
+		m.views = slices.SortedFunc(maps.Values(msg.views), func(a, b config.ViewConfig) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		m.cursor = 0
+		if m.context.View != nil {
+			m.cursor = max(slices.IndexFunc(m.views, func(v config.ViewConfig) bool { return v.Name == m.context.View.Name }), 0)
+		}
+		m.ensureCursorVisible = true
+	case tea.KeyMsg:
+		if m.naming {
+			return m.updateName(msg)
+		}
+		km := m.keymap.Views
+		switch {
+		case key.Matches(msg, m.keymap.Up):
+			m.move(-1)
+		case key.Matches(msg, m.keymap.Down):
+			m.move(1)
+		case key.Matches(msg, m.keymap.Apply):
+			return m.apply()
+		case key.Matches(msg, km.Save):
+			m.naming = true
+			m.name.SetValue("")
+			return m.name.Focus()
+		case key.Matches(msg, km.Delete):
+			return m.delete()
+		case key.Matches(msg, km.Close), key.Matches(msg, m.keymap.Cancel):
+			return common.Close
+		}
+	case rowClickedMsg:
+		if msg.Index >= 0 && msg.Index < len(m.views) {
+			m.cursor = msg.Index
+			return m.apply()
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.ensureCursorVisible = false
+		m.listRenderer.SetScrollOffset(max(0, m.listRenderer.GetScrollOffset()+msg.Delta))
+	}
+	return nil
+}
+
+func (m *Model) updateName(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keymap.Apply):
+		name := strings.TrimSpace(m.name.Value())
+		if name == "" {
+			return nil
+		}
+		return tea.Sequence(common.Close, intents.Invoke(intents.SaveView{Name: name}))
+	case key.Matches(msg, m.keymap.Cancel):
+		m.naming = false
+		m.name.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	m.name, cmd = m.name.Update(msg)
+	return cmd
+}
+
+func (m *Model) move(delta int) {
+	next := m.cursor + delta
+	if next < 0 || next >= len(m.views) {
+		return
+	}
+	m.cursor = next
+	m.ensureCursorVisible = true
+}
+
+func (m *Model) apply() tea.Cmd {
+	if m.cursor < 0 || m.cursor >= len(m.views) {
+		return nil
+	}
+	return tea.Sequence(common.Close, intents.Invoke(intents.ApplyView{Name: m.views[m.cursor].Name}))
+}
+
+func (m *Model) delete() tea.Cmd {
+	if m.cursor < 0 || m.cursor >= len(m.views) {
+		return nil
+	}
+	name := m.views[m.cursor].Name
+	if _, ok := m.saved[name]; !ok {
+		return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("%s is in the config, only saved views can be deleted", name)})
+	}
+	if err := m.context.SavedViews.Delete(name); err != nil {
+		return intents.Invoke(intents.AddMessage{Text: "Failed to delete the view", Err: err})
+	}
+	return m.Init()
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	height := min(max(len(m.views), 1), maxVisibleViews) + 4
+	frame := box.Center(min(max(box.R.Dx()-8, 0), 100), min(height, max(box.R.Dy()-2, 0)))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 4 {
+		return
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	contentBox := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	window.AddDraw(frame.R, m.styles.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(contentBox.R, ' ', m.styles.text, render.ZDialogs)
+
+	titleBox, listBox := contentBox.CutTop(1)
+	listBox, nameBox := listBox.CutBottom(1)
+	window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).
+		Styled(fmt.Sprintf("Views (%d)", len(m.views)), m.styles.title).
+		Done()
+
+	if m.naming {
+		m.name.Width = max(nameBox.R.Dx()-len(m.name.Prompt)-1, 1)
+		window.AddDraw(nameBox.R, m.name.View(), render.ZDialogs+1)
+	} else {
+		window.Text(nameBox.R.Min.X, nameBox.R.Min.Y, render.ZDialogs).
+			Styled(m.keymap.Views.Save.Help().Key+" saves the current revset as a view", m.styles.dimmed).
+			Done()
+	}
+
+	switch {
+	case m.err != nil:
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled(m.err.Error(), m.styles.err).Done()
+		return
+	case len(m.views) == 0:
+		window.Text(listBox.R.Min.X, listBox.R.Min.Y, render.ZDialogs).Styled("No views yet", m.styles.dimmed).Done()
+		return
+	}
+
+	nameWidth := 0
+	for _, view := range m.views {
+		nameWidth = max(nameWidth, lipgloss.Width(view.Name))
+	}
+	m.listRenderer.Render(
+		window,
+		listBox,
+		len(m.views),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			m.renderRow(dl, index, rect, nameWidth)
+		},
+		func(index int) tea.Msg { return rowClickedMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(window, listBox)
+	m.ensureCursorVisible = false
+}
+
+func (m *Model) renderRow(dl *render.DisplayContext, index int, rect cellbuf.Rectangle, nameWidth int) {
+	view := m.views[index]
+	marker := "  "
+	if m.context.View != nil && m.context.View.Name == view.Name {
+		marker = "● "
+	}
+	tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs).
+		Styled(marker, m.styles.text).
+		Styled(view.Name+strings.Repeat(" ", nameWidth-lipgloss.Width(view.Name)), m.styles.text).
+		Space(2).
+		Styled(view.Revset, m.styles.dimmed)
+	if len(view.Key) > 0 {
+		tb.Space(2).Styled(config.JoinKeys(view.Key), m.styles.shortcut)
+	}
+	if _, ok := m.saved[view.Name]; ok {
+		tb.Space(2).Styled("saved", m.styles.dimmed)
+	}
+	tb.Done()
+	if index == m.cursor {
+		dl.AddHighlight(rect, m.styles.selected, render.ZDialogs+1)
+	}
+}
+
+func New(context *context.MainContext) *Model {
+	name := textinput.New()
+	name.Prompt = "name: "
+	return &Model{
+		context:      context,
+		name:         name,
+		keymap:       config.Current.GetKeyMap(),
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+		styles: styles{
+			border:   common.DefaultPalette.GetBorder("views border", lipgloss.RoundedBorder()),
+			title:    common.DefaultPalette.Get("views title"),
+			text:     common.DefaultPalette.Get("views text"),
+			dimmed:   common.DefaultPalette.Get("views dimmed"),
+			selected: common.DefaultPalette.Get("views selected"),
+			shortcut: common.DefaultPalette.Get("views shortcut"),
+			err:      common.DefaultPalette.Get("views error"),
+		},
+	}
+}
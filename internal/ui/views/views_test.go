@@ -0,0 +1,127 @@
+package views
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withViews(t *testing.T, views map[string]config.ViewConfig) {
+	current := config.Current
+	t.Cleanup(func() { config.Current = current })
+	config.Current = config.Default()
+	config.Current.Views = views
+}
+
+func newSavedViews(t *testing.T, views ...config.ViewConfig) *config.SavedViews {
+	saved := config.NewSavedViewsFile(filepath.Join(t.TempDir(), "views.toml"))
+	for _, view := range views {
+		require.NoError(t, saved.Save(view))
+	}
+	return saved
+}
+
+func TestInit_ListsConfigAndSavedViews(t *testing.T) {
+	withViews(t, map[string]config.ViewConfig{
+		"mine": {Revset: "mine()", Key: []string{"ctrl+m"}},
+	})
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SavedViews = newSavedViews(t, config.ViewConfig{Name: "review", Revset: "reviewers(me)"})
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "Views (2)")
+	assert.Contains(t, rendered, "mine()")
+	assert.Contains(t, rendered, "ctrl+m")
+	assert.Contains(t, rendered, "reviewers(me)")
+	assert.Contains(t, rendered, "saved")
+}
+
+func TestApply_InvokesSelectedView(t *testing.T) {
+	withViews(t, map[string]config.ViewConfig{
+		"a": {Revset: "a"},
+		"b": {Revset: "b"},
+	})
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("j"))
+
+	var applied intents.ApplyView
+	test.SimulateModel(model, test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		if got, ok := msg.(intents.ApplyView); ok {
+			applied = got
+		}
+	})
+	assert.Equal(t, "b", applied.Name)
+}
+
+func TestSave_InvokesSaveViewWithName(t *testing.T) {
+	withViews(t, nil)
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("s"))
+	test.SimulateModel(model, test.Type("stack"))
+
+	var saved intents.SaveView
+	test.SimulateModel(model, test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		if got, ok := msg.(intents.SaveView); ok {
+			saved = got
+		}
+	})
+	assert.Equal(t, "stack", saved.Name)
+}
+
+func TestDelete_RemovesSavedView(t *testing.T) {
+	withViews(t, nil)
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SavedViews = newSavedViews(t, config.ViewConfig{Name: "review", Revset: "reviewers(me)"})
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("d"))
+
+	saved, err := ctx.SavedViews.Load()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "No views yet")
+}
+
+func TestDelete_KeepsViewOfConfig(t *testing.T) {
+	withViews(t, map[string]config.ViewConfig{"mine": {Revset: "mine()"}})
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+
+	model := New(ctx)
+	test.SimulateModel(model, model.Init())
+
+	var flashMsg intents.AddMessage
+	test.SimulateModel(model, test.Type("d"), func(msg tea.Msg) {
+		if got, ok := msg.(intents.AddMessage); ok {
+			flashMsg = got
+		}
+	})
+	assert.Contains(t, flashMsg.Text, "only saved views can be deleted")
+}
@@ -0,0 +1,159 @@
+package widgets
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Form)(nil)
+	_ help.KeyMap           = (*Form)(nil)
+)
+
+// Field is a named value of a form
+type Field struct {
+	Name  string
+	Label string
+	Value string
+}
+
+type formField struct {
+	name  string
+	label string
+	input textinput.Model
+}
+
+// Form edits a value for each of its fields. Enter moves to the next field
+// and submits the form on the last one.
+type Form struct {
+	title   string
+	fields  []formField
+	focused int
+	keymap  config.KeyMappings[key.Binding]
+	styles  styles
+}
+
+func NewForm(title string, fields []Field) *Form {
+	styles := newStyles("form")
+	f := &Form{
+		title:  title,
+		keymap: config.Current.GetKeyMap(),
+		styles: styles,
+	}
+	for _, field := range fields {
+		ti := textinput.New()
+		ti.Prompt = ""
+		ti.Width = 40
+		ti.SetValue(field.Value)
+		label := field.Label
+		if label == "" {
+			label = field.Name
+		}
+		f.fields = append(f.fields, formField{name: field.Name, label: label, input: ti})
+	}
+	return f
+}
+
+func (f *Form) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field")),
+		key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous field")),
+		f.keymap.Apply,
+		f.keymap.Cancel,
+	}
+}
+
+func (f *Form) FullHelp() [][]key.Binding {
+	return [][]key.Binding{f.ShortHelp()}
+}
+
+func (f *Form) Init() tea.Cmd {
+	return f.focus(0)
+}
+
+func (f *Form) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyTab, tea.KeyDown:
+			return f.focus(f.focused + 1)
+		case tea.KeyShiftTab, tea.KeyUp:
+			return f.focus(f.focused - 1)
+		case tea.KeyEnter:
+			if f.focused < len(f.fields)-1 {
+				return f.focus(f.focused + 1)
+			}
+			return done(f.values())
+		case tea.KeyEsc:
+			return done(nil)
+		default:
+			if f.focused >= len(f.fields) {
+				return nil
+			}
+			var cmd tea.Cmd
+			f.fields[f.focused].input, cmd = f.fields[f.focused].input.Update(msg)
+			return cmd
+		}
+	case clickMsg:
+		return f.focus(msg.Index)
+	case common.CloseViewMsg:
+		return done(nil)
+	}
+	return nil
+}
+
+func (f *Form) focus(index int) tea.Cmd {
+	if index < 0 || index >= len(f.fields) {
+		return nil
+	}
+	f.fields[f.focused].input.Blur()
+	f.focused = index
+	return f.fields[index].input.Focus()
+}
+
+func (f *Form) values() map[string]string {
+	values := make(map[string]string, len(f.fields))
+	for _, field := range f.fields {
+		values[field.name] = field.input.Value()
+	}
+	return values
+}
+
+func (f *Form) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	labelWidth := 0
+	for _, field := range f.fields {
+		labelWidth = max(labelWidth, lipgloss.Width(field.label))
+	}
+	inputWidth := min(40, max(box.R.Dx()-labelWidth-8, 1))
+	window, content, ok := f.styles.frame(dl, box, labelWidth+2+inputWidth+1, len(f.fields), f.title)
+	if !ok {
+		return
+	}
+	for i := range f.fields {
+		if i >= content.R.Dy() {
+			break
+		}
+		field := &f.fields[i]
+		row := cellbuf.Rect(content.R.Min.X, content.R.Min.Y+i, content.R.Dx(), 1)
+		labelStyle := f.styles.dimmed
+		if i == f.focused {
+			labelStyle = f.styles.title
+		}
+		field.input.Width = inputWidth
+		window.Text(row.Min.X, row.Min.Y, render.ZDialogs).
+			Styled(field.label, labelStyle).
+			Done()
+		inputRect := row
+		inputRect.Min.X += labelWidth + 2
+		window.AddDraw(inputRect, field.input.View(), render.ZDialogs)
+		window.AddInteraction(row, clickMsg{Index: i}, render.InteractionClick, 0)
+	}
+}
@@ -0,0 +1,43 @@
+package widgets
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForm_RendersFields(t *testing.T) {
+	model := NewForm("New bookmark", []Field{{Name: "name", Label: "Name"}, {Name: "remote", Value: "origin"}})
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "New bookmark")
+	assert.Contains(t, rendered, "Name")
+	assert.Contains(t, rendered, "remote")
+	assert.Contains(t, rendered, "origin")
+}
+
+func TestForm_SubmitsValuesOnLastField(t *testing.T) {
+	model := NewForm("", []Field{{Name: "name"}, {Name: "remote", Value: "origin"}})
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("feature"))
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	test.SimulateModel(model, test.Type("-fork"))
+
+	assert.Equal(t, map[string]string{"name": "feature", "remote": "origin-fork"}, apply(model, test.Press(tea.KeyEnter)))
+}
+
+func TestForm_EscCancels(t *testing.T) {
+	model := NewForm("", []Field{{Name: "name"}})
+	test.SimulateModel(model, model.Init())
+
+	var done bool
+	test.SimulateModel(model, test.Press(tea.KeyEsc), func(msg tea.Msg) {
+		if got, ok := msg.(DoneMsg); ok {
+			done = got.Value == nil
+		}
+	})
+	assert.True(t, done)
+}
@@ -0,0 +1,157 @@
+package widgets
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*List)(nil)
+	_ help.KeyMap           = (*List)(nil)
+)
+
+const maxVisibleOptions = 15
+
+// List picks any number of options. Applying without checking any option picks
+// the one under the cursor.
+type List struct {
+	title               string
+	options             []string
+	checked             []bool
+	cursor              int
+	listRenderer        *render.ListRenderer
+	ensureCursorVisible bool
+	keymap              config.KeyMappings[key.Binding]
+	styles              styles
+}
+
+// NewList creates a list of the options where the selected ones are checked
+func NewList(title string, options []string, selected []string) *List {
+	checked := make([]bool, len(options))
+	for i, option := range options {
+		checked[i] = slices.Contains(selected, option)
+	}
+	return &List{
+		title:        title,
+		options:      options,
+		checked:      checked,
+		listRenderer: render.NewListRenderer(scrollMsg{}),
+		keymap:       config.Current.GetKeyMap(),
+		styles:       newStyles("list"),
+	}
+}
+
+func (l *List) ShortHelp() []key.Binding {
+	return []key.Binding{l.keymap.Up, l.keymap.Down, l.keymap.ToggleSelect, l.keymap.Apply, l.keymap.Cancel}
+}
+
+func (l *List) FullHelp() [][]key.Binding {
+	return [][]key.Binding{l.ShortHelp()}
+}
+
+func (l *List) Init() tea.Cmd {
+	return nil
+}
+
+func (l *List) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, l.keymap.Up):
+			l.move(-1)
+		case key.Matches(msg, l.keymap.Down):
+			l.move(1)
+		case key.Matches(msg, l.keymap.ToggleSelect):
+			l.toggle(l.cursor)
+		case key.Matches(msg, l.keymap.Apply):
+			return done(l.picked())
+		case key.Matches(msg, l.keymap.Cancel):
+			return done(nil)
+		}
+	case clickMsg:
+		l.cursor = msg.Index
+		l.toggle(msg.Index)
+	case scrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		l.ensureCursorVisible = false
+		l.listRenderer.SetScrollOffset(max(0, l.listRenderer.GetScrollOffset()+msg.Delta))
+	case common.CloseViewMsg:
+		return done(nil)
+	}
+	return nil
+}
+
+func (l *List) move(delta int) {
+	next := l.cursor + delta
+	if next < 0 || next >= len(l.options) {
+		return
+	}
+	l.cursor = next
+	l.ensureCursorVisible = true
+}
+
+func (l *List) toggle(index int) {
+	if index < 0 || index >= len(l.checked) {
+		return
+	}
+	l.checked[index] = !l.checked[index]
+}
+
+func (l *List) picked() []string {
+	var picked []string
+	for i, option := range l.options {
+		if l.checked[i] {
+			picked = append(picked, option)
+		}
+	}
+	if len(picked) == 0 && l.cursor < len(l.options) {
+		picked = append(picked, l.options[l.cursor])
+	}
+	return picked
+}
+
+func (l *List) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	width := 30
+	for _, option := range l.options {
+		width = max(width, lipgloss.Width(option)+4)
+	}
+	window, content, ok := l.styles.frame(dl, box, width, min(max(len(l.options), 1), maxVisibleOptions), l.title)
+	if !ok {
+		return
+	}
+	l.listRenderer.Render(
+		window,
+		content,
+		len(l.options),
+		l.cursor,
+		l.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			checkbox := "[ ] "
+			if l.checked[index] {
+				checkbox = "[x] "
+			}
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs).
+				Styled(checkbox, l.styles.dimmed).
+				Styled(l.options[index], l.styles.text).
+				Done()
+			if index == l.cursor {
+				dl.AddHighlight(rect, l.styles.selected, render.ZDialogs+1)
+			}
+		},
+		func(index int) tea.Msg { return clickMsg{Index: index} },
+	)
+	l.listRenderer.RegisterScroll(window, content)
+	l.ensureCursorVisible = false
+}
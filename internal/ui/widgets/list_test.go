@@ -0,0 +1,57 @@
+package widgets
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func apply(model common.ImmediateModel, cmd tea.Cmd) any {
+	var value any
+	test.SimulateModel(model, cmd, func(msg tea.Msg) {
+		if got, ok := msg.(DoneMsg); ok {
+			value = got.Value
+		}
+	})
+	return value
+}
+
+func TestList_RendersCheckedOptions(t *testing.T) {
+	model := NewList("Bookmarks", []string{"main", "dev"}, []string{"dev"})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "Bookmarks")
+	assert.Contains(t, rendered, "[ ] main")
+	assert.Contains(t, rendered, "[x] dev")
+}
+
+func TestList_AppliesCheckedOptions(t *testing.T) {
+	model := NewList("", []string{"main", "dev", "feature"}, nil)
+	test.SimulateModel(model, test.Type(" "))
+	test.SimulateModel(model, test.Type("jj"))
+	test.SimulateModel(model, test.Type(" "))
+
+	assert.Equal(t, []string{"main", "feature"}, apply(model, test.Press(tea.KeyEnter)))
+}
+
+func TestList_AppliesOptionUnderCursorWhenNoneChecked(t *testing.T) {
+	model := NewList("", []string{"main", "dev"}, nil)
+	test.SimulateModel(model, test.Type("j"))
+
+	assert.Equal(t, []string{"dev"}, apply(model, test.Press(tea.KeyEnter)))
+}
+
+func TestList_CancelSendsNil(t *testing.T) {
+	model := NewList("", []string{"main"}, []string{"main"})
+
+	var done bool
+	test.SimulateModel(model, test.Press(tea.KeyEsc), func(msg tea.Msg) {
+		if got, ok := msg.(DoneMsg); ok {
+			done = got.Value == nil
+		}
+	})
+	assert.True(t, done)
+}
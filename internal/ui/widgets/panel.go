@@ -0,0 +1,106 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var (
+	_ common.ImmediateModel = (*Panel)(nil)
+	_ help.KeyMap           = (*Panel)(nil)
+)
+
+// Panel shows a text that is scrolled with the keys or the mouse wheel
+type Panel struct {
+	title        string
+	lines        []string
+	height       int // the number of lines shown the last time it was drawn
+	listRenderer *render.ListRenderer
+	keymap       config.KeyMappings[key.Binding]
+	styles       styles
+}
+
+func NewPanel(title string, text string) *Panel {
+	return &Panel{
+		title:        title,
+		lines:        strings.Split(strings.TrimRight(text, "\n"), "\n"),
+		listRenderer: render.NewListRenderer(scrollMsg{}),
+		keymap:       config.Current.GetKeyMap(),
+		styles:       newStyles("panel"),
+	}
+}
+
+func (p *Panel) ShortHelp() []key.Binding {
+	return []key.Binding{p.keymap.Up, p.keymap.Down, p.keymap.ScrollUp, p.keymap.ScrollDown, p.keymap.Cancel}
+}
+
+func (p *Panel) FullHelp() [][]key.Binding {
+	return [][]key.Binding{p.ShortHelp()}
+}
+
+func (p *Panel) Init() tea.Cmd {
+	return nil
+}
+
+func (p *Panel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, p.keymap.Up):
+			p.scroll(-1)
+		case key.Matches(msg, p.keymap.Down):
+			p.scroll(1)
+		case key.Matches(msg, p.keymap.ScrollUp):
+			p.scroll(-max(p.height-1, 1))
+		case key.Matches(msg, p.keymap.ScrollDown):
+			p.scroll(max(p.height-1, 1))
+		case key.Matches(msg, p.keymap.Apply), key.Matches(msg, p.keymap.Cancel):
+			return done(nil)
+		}
+	case scrollMsg:
+		if !msg.Horizontal {
+			p.scroll(msg.Delta)
+		}
+	case common.CloseViewMsg:
+		return done(nil)
+	}
+	return nil
+}
+
+func (p *Panel) scroll(delta int) {
+	p.listRenderer.SetScrollOffset(render.ClampStartLine(p.listRenderer.GetScrollOffset()+delta, p.height, len(p.lines)))
+}
+
+func (p *Panel) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	width := 40
+	for _, line := range p.lines {
+		width = max(width, lipgloss.Width(line))
+	}
+	window, content, ok := p.styles.frame(dl, box, width, len(p.lines), p.title)
+	if !ok {
+		return
+	}
+	p.height = content.R.Dy()
+	p.listRenderer.Render(
+		window,
+		content,
+		len(p.lines),
+		-1,
+		false,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect cellbuf.Rectangle) {
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZDialogs).Styled(p.lines[index], p.styles.text).Done()
+		},
+		func(index int) tea.Msg { return clickMsg{Index: index} },
+	)
+	p.listRenderer.RegisterScroll(window, content)
+}
@@ -0,0 +1,43 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanel_ScrollsText(t *testing.T) {
+	var lines []string
+	for i := range 50 {
+		lines = append(lines, fmt.Sprintf("line %02d", i))
+	}
+	model := NewPanel("Notes", strings.Join(lines, "\n"))
+
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "Notes")
+	assert.Contains(t, rendered, lines[0])
+	assert.NotContains(t, rendered, lines[49])
+
+	for range 50 {
+		test.SimulateModel(model, test.Type("j"))
+	}
+	rendered = test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.NotContains(t, rendered, lines[0])
+	assert.Contains(t, rendered, lines[49])
+}
+
+func TestPanel_CancelSendsDone(t *testing.T) {
+	model := NewPanel("", "text")
+
+	var closed bool
+	test.SimulateModel(model, test.Press(tea.KeyEsc), func(msg tea.Msg) {
+		if got, ok := msg.(DoneMsg); ok {
+			closed = got.Value == nil
+		}
+	})
+	assert.True(t, closed)
+}
@@ -0,0 +1,93 @@
+// Package widgets holds the dialogs scripts show over the UI: a scrollable
+// text panel, a multi-select list and a form of named fields.
+package widgets
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// ShowMsg shows the widget over the UI until it sends DoneMsg
+type ShowMsg struct {
+	Name   string
+	Widget common.ImmediateModel
+}
+
+// DoneMsg closes the widget with its value: nil when it is cancelled, the
+// options picked from a list or the values of the fields of a form
+type DoneMsg struct {
+	Value any
+}
+
+func Show(name string, widget common.ImmediateModel) tea.Cmd {
+	return func() tea.Msg {
+		return ShowMsg{Name: name, Widget: widget}
+	}
+}
+
+func done(value any) tea.Cmd {
+	return func() tea.Msg {
+		return DoneMsg{Value: value}
+	}
+}
+
+type scrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m scrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type clickMsg struct {
+	Index int
+}
+
+type styles struct {
+	border   lipgloss.Style
+	title    lipgloss.Style
+	text     lipgloss.Style
+	dimmed   lipgloss.Style
+	selected lipgloss.Style
+}
+
+func newStyles(name string) styles {
+	return styles{
+		border:   common.DefaultPalette.GetBorder(name+" border", lipgloss.RoundedBorder()),
+		title:    common.DefaultPalette.Get(name + " title"),
+		text:     common.DefaultPalette.Get(name + " text"),
+		dimmed:   common.DefaultPalette.Get(name + " dimmed"),
+		selected: common.DefaultPalette.Get(name + " selected"),
+	}
+}
+
+// frame draws the border of a widget with room for width x height cells of
+// content in the middle of box, and its title when there is one. It returns
+// the window to draw in and the box left for the content.
+func (s styles) frame(dl *render.DisplayContext, box layout.Box, width int, height int, title string) (*render.DisplayContext, layout.Box, bool) {
+	if title != "" {
+		height++
+		width = max(width, lipgloss.Width(title))
+	}
+	frame := box.Center(min(width+2, max(box.R.Dx()-4, 0)), min(height+2, max(box.R.Dy()-2, 0)))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return nil, layout.Box{}, false
+	}
+	window := dl.Window(frame.R, render.ZDialogs)
+	content := frame.Inset(1)
+	borderBase := lipgloss.NewStyle().Width(content.R.Dx()).Height(content.R.Dy()).Render("")
+	window.AddDraw(frame.R, s.border.Render(borderBase), render.ZDialogs)
+	window.AddFill(content.R, ' ', s.text, render.ZDialogs)
+	if title != "" {
+		var titleBox layout.Box
+		titleBox, content = content.CutTop(1)
+		window.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZDialogs).Styled(title, s.title).Done()
+	}
+	return window, content, content.R.Dy() > 0
+}
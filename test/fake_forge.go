@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/idursun/jjui/internal/forge"
+)
+
+// FakeForge is a forge.Submitter that serves pull requests from memory
+type FakeForge struct {
+	mutex        sync.Mutex
+	pullRequests map[string]forge.PullRequest
+	bodies       map[int]string
+	Requested    [][]string
+	Created      []forge.PullRequest
+	Updated      []FakePullRequestUpdate
+	Err          error
+}
+
+// FakePullRequestUpdate records a call to UpdatePullRequest
+type FakePullRequestUpdate struct {
+	Number int
+	Base   string
+	Body   string
+}
+
+var _ forge.Submitter = (*FakeForge)(nil)
+
+func NewFakeForge(pullRequests ...forge.PullRequest) *FakeForge {
+	f := &FakeForge{pullRequests: make(map[string]forge.PullRequest), bodies: make(map[int]string)}
+	for _, pr := range pullRequests {
+		f.pullRequests[pr.Bookmark] = pr
+	}
+	return f
+}
+
+func (f *FakeForge) PullRequests(_ context.Context, bookmarks []string) (map[string]forge.PullRequest, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.Requested = append(f.Requested, bookmarks)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	result := make(map[string]forge.PullRequest)
+	for _, bookmark := range bookmarks {
+		if pr, ok := f.pullRequests[bookmark]; ok {
+			result[bookmark] = pr
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeForge) CreatePullRequest(_ context.Context, head string, _ string, _ string, body string) (forge.PullRequest, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.Err != nil {
+		return forge.PullRequest{}, f.Err
+	}
+	number := 1
+	for _, pr := range f.pullRequests {
+		number = max(number, pr.Number+1)
+	}
+	pr := forge.PullRequest{Bookmark: head, Number: number, State: forge.StateOpen}
+	f.pullRequests[head] = pr
+	f.bodies[number] = body
+	f.Created = append(f.Created, pr)
+	return pr, nil
+}
+
+func (f *FakeForge) UpdatePullRequest(_ context.Context, number int, base string, body string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.bodies[number] = body
+	f.Updated = append(f.Updated, FakePullRequestUpdate{Number: number, Base: base, Body: body})
+	return nil
+}
+
+func (f *FakeForge) PullRequestBody(_ context.Context, number int) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.Err != nil {
+		return "", f.Err
+	}
+	return f.bodies[number], nil
+}